@@ -34,6 +34,8 @@ export default function ForgotPassword() {
     } catch (error: any) {
       if (error instanceof z.ZodError) {
         showToast(error.issues[0].message, 'error')
+      } else if (error.response?.status === 503) {
+        showToast(error.response?.data?.error || 'Password reset is currently unavailable', 'error')
       } else {
         
         setEmailSent(true)
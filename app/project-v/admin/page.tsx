@@ -4,7 +4,8 @@ import { useAuth } from "@/lib/auth-context";
 import { useRouter } from "next/navigation";
 import { useEffect, useState, useCallback } from "react";
 import { useToast } from "@/components/ToastContainer";
-import { apiClient } from "@/lib/api-client";
+import { apiClient, RubricSubmission } from "@/lib/api-client";
+import RubricScoring from "@/components/RubricScoring";
 import Breadcrumb from "@/components/Breadcrumb";
 import { CompactThemeToggle } from "@/components/ThemeToggle";
 import { BarChart, DonutChart, StatCard } from "@/components/StatCharts";
@@ -78,6 +79,7 @@ export default function ProjectVAdmin() {
   const [feedback, setFeedback] = useState<string>("");
   const [accountPosted, setAccountPosted] = useState<string>("");
   const [showFeedbackDialog, setShowFeedbackDialog] = useState(false);
+  const [rubric, setRubric] = useState<RubricSubmission | null>(null);
   const [processing, setProcessing] = useState(false);
   const [mobileMenuOpen, setMobileMenuOpen] = useState(false);
 
@@ -145,10 +147,14 @@ export default function ProjectVAdmin() {
       showToast("Please provide feedback", "error");
       return;
     }
+    if (!rubric) {
+      showToast("Please score every rubric criterion", "error");
+      return;
+    }
 
     setProcessing(true);
     try {
-      await apiClient.markChangesRequested(selectedSubmission.id, feedback);
+      await apiClient.markChangesRequested(selectedSubmission.id, feedback, rubric);
       showToast("✅ Changes requested successfully", "success");
       setShowFeedbackDialog(false);
       setFeedback("");
@@ -162,9 +168,14 @@ export default function ProjectVAdmin() {
   };
 
   const handleFinalChecks = async (submissionId: string) => {
+    if (!rubric) {
+      showToast("Please score every rubric criterion", "error");
+      return;
+    }
+
     setProcessing(true);
     try {
-      await apiClient.markFinalChecks(submissionId);
+      await apiClient.markFinalChecks(submissionId, rubric);
       showToast("✅ Marked for final checks successfully", "success");
       setSelectedSubmission(null);
       fetchSubmissions();
@@ -1124,6 +1135,10 @@ export default function ProjectVAdmin() {
                   <div className="border-t-2 border-gray-700 pt-6">
                     <h4 className="font-bold text-white mb-4 text-lg">👑 Admin Actions:</h4>
 
+                    <div className="mb-4">
+                      <RubricScoring submissionId={selectedSubmission.id} disabled={processing} onChange={setRubric} />
+                    </div>
+
                     {!showFeedbackDialog ? (
                       <div className="space-y-3">
                         {selectedSubmission.status === "FINAL_CHECKS" && (
@@ -1147,7 +1162,7 @@ export default function ProjectVAdmin() {
                               {processing ? "Approving..." : "✓ Approve Submission"}
                             </button>
                           ) : (
-                            <button onClick={() => handleFinalChecks(selectedSubmission.id)} disabled={processing}
+                            <button onClick={() => handleFinalChecks(selectedSubmission.id)} disabled={processing || !rubric}
                               className="flex-1 px-6 py-4 bg-gradient-to-r from-cyan-600 to-blue-600 hover:from-cyan-700 hover:to-blue-700 text-white font-bold rounded-xl transition-all duration-300 shadow-xl hover:scale-105 disabled:opacity-50 disabled:cursor-not-allowed">
                               {processing ? "Processing..." : "→ Mark for Final Checks"}
                             </button>
@@ -1163,7 +1178,7 @@ export default function ProjectVAdmin() {
                             className="w-full px-5 py-4 rounded-xl border-2 border-gray-700 transition-all duration-300 focus:scale-[1.02] bg-gray-900/50 text-white placeholder-gray-400 focus:border-orange-500 focus:ring-4 focus:ring-orange-500/20 font-medium" />
                         </div>
                         <div className="flex gap-3">
-                          <button onClick={handleRequestChanges} disabled={processing || !feedback.trim()}
+                          <button onClick={handleRequestChanges} disabled={processing || !feedback.trim() || !rubric}
                             className="flex-1 px-6 py-4 bg-gradient-to-r from-orange-600 to-red-600 hover:from-orange-700 hover:to-red-700 text-white font-bold rounded-xl transition-all duration-300 shadow-xl hover:scale-105 disabled:opacity-50 disabled:cursor-not-allowed">
                             {processing ? "Submitting..." : "Submit Feedback"}
                           </button>
@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 
 	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/githost"
 	"github.com/adzzatxperts/backend/internal/handlers"
+	"github.com/adzzatxperts/backend/internal/mailer"
 	"github.com/adzzatxperts/backend/internal/middleware"
+	"github.com/adzzatxperts/backend/internal/services"
 	"github.com/adzzatxperts/backend/internal/storage"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -50,8 +54,71 @@ func main() {
 
 	log.Println("🔌 Initializing WebSocket service...")
 	handlers.InitWebSocket()
+	services.SetValidationHub(handlers.WSHub)
 	log.Println("✓ WebSocket service initialized")
 
+	log.Println("🐳 Initializing container runtime...")
+	runtime, err := services.InitContainerRuntime()
+	if err != nil {
+		log.Printf("❌ Failed to initialize container runtime: %v", err)
+		log.Fatal("Container runtime initialization failed")
+	}
+	log.Printf("✓ Container runtime initialized (%s)", runtime.Name())
+	sandboxConfig := services.LoadSandboxConfig()
+	log.Printf("✓ Sandbox limits: %d MB memory, %g CPUs, test network %q, build timeout %s, test timeout %s",
+		sandboxConfig.Test.MemoryMB, sandboxConfig.Test.CPUs, sandboxConfig.Test.Network, sandboxConfig.BuildTimeout, sandboxConfig.TestTimeout)
+	if err := services.CheckSandboxSupport(context.Background(), runtime, sandboxConfig); err != nil {
+		log.Printf("❌ Sandbox is not supported by this container runtime: %v", err)
+		log.Fatal("Sandbox check failed")
+	}
+	log.Printf("✓ Build sandbox: %s builder, %d MB memory, %g CPUs, %d pids",
+		sandboxConfig.Builder, sandboxConfig.Build.MemoryMB, sandboxConfig.Build.CPUs, sandboxConfig.Build.PIDs)
+
+	log.Println("🔗 Initializing git host client...")
+	gitHost, err := githost.Init()
+	if err != nil {
+		log.Printf("❌ Failed to initialize git host client: %v", err)
+		log.Fatal("Git host initialization failed")
+	}
+	if gitHost != nil {
+		log.Printf("✓ Git host client initialized (%s)", gitHost.Name())
+	} else {
+		log.Println("⚠️  Git host verification disabled")
+	}
+
+	log.Println("📧 Initializing mailer...")
+	mail, err := mailer.Init()
+	if err != nil {
+		log.Printf("❌ Failed to initialize mailer: %v", err)
+		log.Fatal("Mailer initialization failed")
+	}
+	if mail != nil {
+		log.Printf("✓ Mailer initialized (%s)", mail.Name())
+	} else {
+		log.Println("⚠️  Email delivery disabled")
+	}
+
+	services.RegisterWorkflowEffects()
+	log.Println("✓ Project V workflow effects registered")
+
+	log.Println("⚙️  Starting Project V validation workers...")
+	queueConfig := services.LoadValidationQueueConfig()
+	if err := services.StartValidationQueue(queueConfig); err != nil {
+		log.Printf("❌ Failed to start validation workers: %v", err)
+		log.Fatal("Validation queue startup failed")
+	}
+	log.Printf("✓ Validation workers started (concurrency: %d)", queueConfig.Workers)
+
+	log.Println("⏱️  Starting assignment scheduler...")
+	schedulerConfig := services.LoadAssignmentSchedulerConfig()
+	services.StartAssignmentScheduler(schedulerConfig)
+	log.Printf("✓ Assignment scheduler started (interval: %s)", schedulerConfig.Interval)
+
+	log.Println("🚨 Starting SLA scheduler...")
+	slaConfig := services.LoadSLASchedulerConfig()
+	services.StartSLAScheduler(slaConfig)
+	log.Printf("✓ SLA scheduler started (interval: %s)", slaConfig.Interval)
+
 	router := setupRouter()
 
 	port := os.Getenv("PORT")
@@ -93,12 +160,15 @@ func setupRouter() *gin.Engine {
 		{
 			auth.POST("/signup", handlers.Signup)
 			auth.POST("/signin", handlers.Signin)
-			auth.POST("/logout", handlers.Logout)
+			auth.POST("/logout", middleware.AuthMiddleware(), handlers.Logout)
 			auth.POST("/refresh", handlers.RefreshToken)
 			auth.POST("/revoke", handlers.RevokeRefreshToken)
 			auth.GET("/me", middleware.AuthMiddleware(), handlers.GetMe)
 			auth.POST("/forgot-password", handlers.ForgotPassword)
 			auth.POST("/reset-password", handlers.ResetPassword)
+			auth.POST("/2fa/verify", handlers.VerifyTwoFactorSignin)
+			auth.POST("/2fa/enroll", handlers.BeginTwoFactorEnrollment)
+			auth.POST("/2fa/enroll/confirm", handlers.ConfirmTwoFactorEnrollment)
 		}
 
 		api.GET("/ws", middleware.WebSocketAuthMiddleware(), handlers.HandleWebSocket)
@@ -110,6 +180,19 @@ func setupRouter() *gin.Engine {
 			protected.GET("/profile", handlers.GetProfile)
 			protected.PUT("/profile", handlers.UpdateProfile)
 			protected.DELETE("/profile", handlers.DeleteMyAccount)
+			protected.GET("/profile/availability", handlers.GetMyAvailability)
+			protected.PUT("/profile/availability", handlers.UpdateMyAvailability)
+			protected.POST("/profile/out-of-office", handlers.AddMyOutOfOffice)
+			protected.DELETE("/profile/out-of-office/:id", handlers.DeleteMyOutOfOffice)
+
+			protected.GET("/profile/2fa", handlers.GetMyTwoFactor)
+			protected.POST("/profile/2fa/setup", handlers.SetupMyTwoFactor)
+			protected.POST("/profile/2fa/enable", handlers.EnableMyTwoFactor)
+			protected.POST("/profile/2fa/disable", handlers.DisableMyTwoFactor)
+			protected.POST("/profile/2fa/recovery-codes", handlers.RegenerateMyRecoveryCodes)
+
+			protected.GET("/sessions", handlers.GetMySessions)
+			protected.DELETE("/sessions/:id", handlers.RevokeMySession)
 
 			protected.PUT("/greenlight/toggle", handlers.ToggleMyGreenLight)
 
@@ -121,14 +204,25 @@ func setupRouter() *gin.Engine {
 				submissions.GET("/:id", handlers.GetSubmission)
 				submissions.DELETE("/:id", handlers.DeleteSubmission)
 				submissions.GET("/:id/download", handlers.GetDownloadURL)
+				submissions.GET("/:id/timeline", handlers.GetSubmissionTimeline)
 				submissions.POST("/:id/feedback", handlers.SubmitFeedback)
 			}
 
 			projectv := protected.Group("/projectv")
 			{
+				projectv.GET("/workflow", handlers.GetProjectVWorkflow)
 				projectv.POST("/submissions", handlers.CreateProjectVSubmission)
 				projectv.GET("/submissions", handlers.GetProjectVSubmissions)
 				projectv.GET("/submissions/:id", handlers.GetProjectVSubmission)
+				projectv.GET("/submissions/:id/runs", handlers.GetValidationRuns)
+				projectv.GET("/submissions/:id/runs/:runId", handlers.GetValidationRun)
+				projectv.GET("/submissions/:id/timeline", handlers.GetProjectVTimeline)
+				projectv.GET("/submissions/:id/comments", handlers.GetComments)
+				projectv.POST("/submissions/:id/comments", handlers.CreateComment)
+				projectv.PUT("/submissions/:id/comments/:commentId/resolve", handlers.ResolveComment)
+				projectv.GET("/submissions/:id/revisions", handlers.GetSubmissionRevisions)
+				projectv.GET("/submissions/:id/revisions/diff", handlers.GetRevisionDiff)
+				projectv.GET("/submissions/:id/rubric", handlers.GetSubmissionRubric)
 				projectv.PUT("/submissions/:id/status", handlers.UpdateProjectVStatus)
 				projectv.PUT("/submissions/:id/changes-requested", handlers.MarkChangesRequested)
 				projectv.PUT("/submissions/:id/final-checks", handlers.MarkFinalChecks)
@@ -149,6 +243,12 @@ func setupRouter() *gin.Engine {
 				admin.PUT("/users/:id/approve", handlers.ApproveTester)
 				admin.PUT("/users/:id/greenlight", handlers.ToggleGreenLight)
 				admin.PUT("/users/:id/role", handlers.SwitchUserRole)
+				admin.GET("/users/:id/availability", handlers.GetUserAvailability)
+				admin.PUT("/users/:id/availability", handlers.UpdateUserAvailability)
+				admin.GET("/users/:id/sessions", handlers.GetUserSessions)
+				admin.DELETE("/users/:id/sessions", handlers.TerminateUserSessions)
+				admin.DELETE("/users/:id/sessions/:sessionId", handlers.TerminateUserSession)
+				admin.DELETE("/users/:id/2fa", handlers.ResetUserTwoFactor)
 				admin.DELETE("/users/:id", handlers.DeleteUser)
 
 				admin.PUT("/submissions/:id/approve", handlers.ApproveSubmission)
@@ -163,10 +263,33 @@ func setupRouter() *gin.Engine {
 
 				admin.GET("/admin/audit-logs", handlers.GetAuditLogs)
 
+				admin.GET("/admin/rubrics", handlers.GetRubrics)
+				admin.POST("/admin/rubrics", handlers.CreateRubric)
+				admin.PUT("/admin/rubrics/:id", handlers.UpdateRubric)
+				admin.DELETE("/admin/rubrics/:id", handlers.DeleteRubric)
+
+				admin.GET("/admin/settings/assignment", handlers.GetAssignmentSettings)
+				admin.PUT("/admin/settings/assignment/:pipeline", handlers.UpdateAssignmentSetting)
+				admin.GET("/admin/settings/two-factor", handlers.GetTwoFactorPolicies)
+				admin.PUT("/admin/settings/two-factor/:role", handlers.UpdateTwoFactorPolicy)
+
+				admin.GET("/admin/sla/policies", handlers.GetSLAPolicies)
+				admin.POST("/admin/sla/policies", handlers.CreateSLAPolicy)
+				admin.PUT("/admin/sla/policies/:id", handlers.UpdateSLAPolicy)
+				admin.DELETE("/admin/sla/policies/:id", handlers.DeleteSLAPolicy)
+				admin.GET("/admin/sla/breaches", handlers.GetSLABreaches)
+
 				admin.GET("/admin/reviews", handlers.GetAllReviews)
 				admin.GET("/admin/projectv/submissions", handlers.GetAllProjectVSubmissions)
 
 				admin.POST("/admin/projectv/reassign-pending", handlers.ReassignPendingTasks)
+
+				admin.GET("/admin/projectv/jobs", handlers.GetValidationJobs)
+				admin.PUT("/admin/projectv/jobs/:id/cancel", handlers.CancelValidationJob)
+				admin.POST("/admin/projectv/jobs/:id/rerun", handlers.RerunValidationJob)
+				admin.GET("/admin/projectv/repo-cache", handlers.GetRepoCache)
+				admin.DELETE("/admin/projectv/repo-cache", handlers.PurgeRepoCache)
+				admin.DELETE("/admin/projectv/repo-cache/:key", handlers.PurgeRepoCacheEntry)
 			}
 		}
 	}
@@ -0,0 +1,370 @@
+package assignment
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+const (
+	StrategyLeastLoaded      = "least_loaded"
+	StrategyRoundRobin       = "round_robin"
+	StrategyWeightedCapacity = "weighted_capacity"
+	StrategySkillMatch       = "skill_match"
+)
+
+var (
+	ErrUnknownStrategy = errors.New("unknown assignment strategy")
+	ErrInvalidParams   = errors.New("invalid strategy parameters")
+	ErrNoCandidate     = errors.New("no candidate available")
+)
+
+type Candidate struct {
+	UserID        uuid.UUID
+	Name          string
+	ActiveTasks   int64
+	Capacity      int
+	HasProfile    bool
+	Languages     []string
+	Categories    []string
+	MaxDifficulty string
+}
+
+type Task struct {
+	ID         uuid.UUID
+	Language   string
+	Category   string
+	Difficulty string
+}
+
+type Request struct {
+	Task         Task
+	Candidates   []Candidate
+	LastAssigned *uuid.UUID
+}
+
+type Decision struct {
+	Candidate  Candidate
+	Strategy   string
+	Reason     string
+	Score      float64
+	Fallback   bool
+	Matched    []string
+	Considered int
+	Qualified  int
+}
+
+type Strategy interface {
+	Name() string
+	Select(req Request) (*Decision, error)
+}
+
+type Info struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Defaults    map[string]interface{} `json:"defaults"`
+}
+
+func Available() []Info {
+	return []Info{
+		{
+			Name:        StrategyLeastLoaded,
+			Description: "Assigns the candidate with the fewest active tasks",
+			Defaults:    map[string]interface{}{},
+		},
+		{
+			Name:        StrategyRoundRobin,
+			Description: "Rotates through candidates in a stable order, starting after the last assignee",
+			Defaults:    map[string]interface{}{"skipAtCapacity": false},
+		},
+		{
+			Name:        StrategyWeightedCapacity,
+			Description: "Assigns the candidate with the lowest active tasks to capacity ratio, skipping full candidates",
+			Defaults:    map[string]interface{}{"defaultCapacity": 5, "capacities": map[string]int{}},
+		},
+		{
+			Name:        StrategySkillMatch,
+			Description: "Scores candidates by language and category match within their max difficulty, falling back to least loaded",
+			Defaults:    map[string]interface{}{"languageWeight": 2, "categoryWeight": 1, "fallback": true},
+		},
+	}
+}
+
+func New(name string, params json.RawMessage) (Strategy, error) {
+	switch name {
+	case StrategyLeastLoaded:
+		return &LeastLoaded{}, nil
+	case StrategyRoundRobin:
+		s := &RoundRobin{}
+		if err := decodeParams(params, s); err != nil {
+			return nil, err
+		}
+		return s, nil
+	case StrategyWeightedCapacity:
+		s := &WeightedCapacity{DefaultCapacity: 5}
+		if err := decodeParams(params, s); err != nil {
+			return nil, err
+		}
+		if s.DefaultCapacity < 1 {
+			return nil, fmt.Errorf("%w: defaultCapacity must be at least 1", ErrInvalidParams)
+		}
+		for id, capacity := range s.Capacities {
+			if _, err := uuid.Parse(id); err != nil || capacity < 0 {
+				return nil, fmt.Errorf("%w: capacities must map user IDs to non-negative numbers", ErrInvalidParams)
+			}
+		}
+		return s, nil
+	case StrategySkillMatch:
+		s := &SkillMatch{LanguageWeight: 2, CategoryWeight: 1, Fallback: true}
+		if err := decodeParams(params, s); err != nil {
+			return nil, err
+		}
+		if s.LanguageWeight < 0 || s.CategoryWeight < 0 || s.LanguageWeight+s.CategoryWeight == 0 {
+			return nil, fmt.Errorf("%w: languageWeight and categoryWeight must be non-negative and not both zero", ErrInvalidParams)
+		}
+		return s, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownStrategy, name)
+	}
+}
+
+func decodeParams(params json.RawMessage, target interface{}) error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(params, target); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidParams, err)
+	}
+	return nil
+}
+
+type LeastLoaded struct{}
+
+func (s *LeastLoaded) Name() string { return StrategyLeastLoaded }
+
+func (s *LeastLoaded) Select(req Request) (*Decision, error) {
+	best := leastLoaded(req.Candidates)
+	if best == nil {
+		return nil, ErrNoCandidate
+	}
+	return &Decision{
+		Candidate:  *best,
+		Strategy:   s.Name(),
+		Reason:     fmt.Sprintf("Least loaded with %d active tasks", best.ActiveTasks),
+		Considered: len(req.Candidates),
+		Qualified:  len(req.Candidates),
+	}, nil
+}
+
+type RoundRobin struct {
+	SkipAtCapacity bool `json:"skipAtCapacity"`
+}
+
+func (s *RoundRobin) Name() string { return StrategyRoundRobin }
+
+func (s *RoundRobin) Select(req Request) (*Decision, error) {
+	ordered := make([]Candidate, 0, len(req.Candidates))
+	for _, candidate := range req.Candidates {
+		if s.SkipAtCapacity && candidate.Capacity > 0 && candidate.ActiveTasks >= int64(candidate.Capacity) {
+			continue
+		}
+		ordered = append(ordered, candidate)
+	}
+	if len(ordered) == 0 {
+		return nil, ErrNoCandidate
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].UserID.String() < ordered[j].UserID.String() })
+
+	next := 0
+	if req.LastAssigned != nil {
+		last := req.LastAssigned.String()
+		for i, candidate := range ordered {
+			if candidate.UserID.String() > last {
+				next = i
+				break
+			}
+			next = (i + 1) % len(ordered)
+		}
+	}
+
+	return &Decision{
+		Candidate:  ordered[next],
+		Strategy:   s.Name(),
+		Reason:     fmt.Sprintf("Next in rotation (%d of %d)", next+1, len(ordered)),
+		Considered: len(req.Candidates),
+		Qualified:  len(ordered),
+	}, nil
+}
+
+type WeightedCapacity struct {
+	DefaultCapacity int            `json:"defaultCapacity"`
+	Capacities      map[string]int `json:"capacities"`
+}
+
+func (s *WeightedCapacity) Name() string { return StrategyWeightedCapacity }
+
+func (s *WeightedCapacity) capacity(candidate Candidate) int {
+	if capacity, ok := s.Capacities[candidate.UserID.String()]; ok {
+		return capacity
+	}
+	if candidate.Capacity > 0 {
+		return candidate.Capacity
+	}
+	return s.DefaultCapacity
+}
+
+func (s *WeightedCapacity) Select(req Request) (*Decision, error) {
+	var best *Candidate
+	bestRatio, bestCapacity, qualified := 0.0, 0, 0
+	for i := range req.Candidates {
+		candidate := &req.Candidates[i]
+		capacity := s.capacity(*candidate)
+		if capacity <= 0 || candidate.ActiveTasks >= int64(capacity) {
+			continue
+		}
+		qualified++
+		ratio := float64(candidate.ActiveTasks) / float64(capacity)
+		if best == nil || ratio < bestRatio {
+			best, bestRatio, bestCapacity = candidate, ratio, capacity
+		}
+	}
+	if best == nil {
+		return nil, ErrNoCandidate
+	}
+
+	return &Decision{
+		Candidate:  *best,
+		Strategy:   s.Name(),
+		Reason:     fmt.Sprintf("Lowest utilisation at %d of %d capacity", best.ActiveTasks, bestCapacity),
+		Score:      bestRatio,
+		Considered: len(req.Candidates),
+		Qualified:  qualified,
+	}, nil
+}
+
+type SkillMatch struct {
+	LanguageWeight float64 `json:"languageWeight"`
+	CategoryWeight float64 `json:"categoryWeight"`
+	Fallback       bool    `json:"fallback"`
+}
+
+func (s *SkillMatch) Name() string { return StrategySkillMatch }
+
+func (s *SkillMatch) Select(req Request) (*Decision, error) {
+	taskRank := DifficultyRank(req.Task.Difficulty)
+
+	var best *Candidate
+	var bestMatched []string
+	bestScore, qualified := 0.0, 0
+	for i := range req.Candidates {
+		candidate := &req.Candidates[i]
+		if !candidate.HasProfile {
+			continue
+		}
+		if candidate.MaxDifficulty != "" && taskRank > 0 && DifficultyRank(candidate.MaxDifficulty) < taskRank {
+			continue
+		}
+
+		score := 0.0
+		var matched []string
+		if contains(candidate.Languages, req.Task.Language) {
+			score += s.LanguageWeight
+			matched = append(matched, "language:"+req.Task.Language)
+		}
+		if contains(candidate.Categories, req.Task.Category) {
+			score += s.CategoryWeight
+			matched = append(matched, "category:"+req.Task.Category)
+		}
+		if score <= 0 {
+			continue
+		}
+
+		qualified++
+		if best == nil || score > bestScore || (score == bestScore && candidate.ActiveTasks < best.ActiveTasks) {
+			best, bestScore, bestMatched = candidate, score, matched
+		}
+	}
+
+	if best != nil {
+		return &Decision{
+			Candidate: *best,
+			Strategy:  s.Name(),
+			Reason: fmt.Sprintf("Best skill match (%s) among %d qualified candidates, %d active tasks",
+				strings.Join(bestMatched, ", "), qualified, best.ActiveTasks),
+			Score:      bestScore,
+			Matched:    bestMatched,
+			Considered: len(req.Candidates),
+			Qualified:  qualified,
+		}, nil
+	}
+
+	if !s.Fallback {
+		return nil, ErrNoCandidate
+	}
+	fallback := leastLoaded(req.Candidates)
+	if fallback == nil {
+		return nil, ErrNoCandidate
+	}
+	return &Decision{
+		Candidate: *fallback,
+		Strategy:  s.Name(),
+		Reason: fmt.Sprintf("No candidate matches %s/%s at %s difficulty, fell back to least loaded with %d active tasks",
+			req.Task.Language, req.Task.Category, req.Task.Difficulty, fallback.ActiveTasks),
+		Fallback:   true,
+		Considered: len(req.Candidates),
+	}, nil
+}
+
+func (d *Decision) Metadata() map[string]interface{} {
+	matched := d.Matched
+	if matched == nil {
+		matched = []string{}
+	}
+	return map[string]interface{}{
+		"assignmentStrategy": d.Strategy,
+		"assignmentReason":   d.Reason,
+		"assignmentScore":    d.Score,
+		"assignmentFallback": d.Fallback,
+		"matchedSkills":      matched,
+		"activeTasks":        d.Candidate.ActiveTasks,
+		"candidateCount":     d.Considered,
+		"qualifiedCount":     d.Qualified,
+	}
+}
+
+func DifficultyRank(difficulty string) int {
+	for i, known := range models.Difficulties {
+		if strings.EqualFold(strings.TrimSpace(difficulty), known) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func leastLoaded(candidates []Candidate) *Candidate {
+	var best *Candidate
+	for i := range candidates {
+		if best == nil || candidates[i].ActiveTasks < best.ActiveTasks {
+			best = &candidates[i]
+		}
+	}
+	return best
+}
+
+func contains(values []string, want string) bool {
+	want = strings.TrimSpace(want)
+	if want == "" {
+		return false
+	}
+	for _, value := range values {
+		if strings.EqualFold(value, want) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,235 @@
+package assignment
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+var (
+	alice = uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	bob   = uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	carol = uuid.MustParse("33333333-3333-3333-3333-333333333333")
+)
+
+func TestRoundRobin(t *testing.T) {
+	candidates := []Candidate{
+		{UserID: carol, Name: "carol", ActiveTasks: 1, Capacity: 2},
+		{UserID: alice, Name: "alice", ActiveTasks: 0, Capacity: 2},
+		{UserID: bob, Name: "bob", ActiveTasks: 2, Capacity: 2},
+	}
+	before := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	between := uuid.MustParse("2aaaaaaa-0000-0000-0000-000000000000")
+	after := uuid.MustParse("ffffffff-ffff-ffff-ffff-ffffffffffff")
+
+	cases := []struct {
+		name           string
+		lastAssigned   *uuid.UUID
+		skipAtCapacity bool
+		want           uuid.UUID
+	}{
+		{name: "no previous assignee starts at the first", want: alice},
+		{name: "continues after the last assignee", lastAssigned: &alice, want: bob},
+		{name: "wraps around after the last candidate", lastAssigned: &carol, want: alice},
+		{name: "last assignee before every candidate", lastAssigned: &before, want: alice},
+		{name: "last assignee no longer a candidate", lastAssigned: &between, want: carol},
+		{name: "last assignee after every candidate wraps around", lastAssigned: &after, want: alice},
+		{name: "skips candidates at capacity", lastAssigned: &alice, skipAtCapacity: true, want: carol},
+		{name: "wraps around past candidates at capacity", lastAssigned: &carol, skipAtCapacity: true, want: alice},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &RoundRobin{SkipAtCapacity: tc.skipAtCapacity}
+			decision, err := s.Select(Request{Candidates: candidates, LastAssigned: tc.lastAssigned})
+			if err != nil {
+				t.Fatalf("Select: %v", err)
+			}
+			if decision.Candidate.UserID != tc.want {
+				t.Fatalf("selected %s, want %s", decision.Candidate.Name, tc.want)
+			}
+		})
+	}
+
+	full := []Candidate{{UserID: alice, ActiveTasks: 2, Capacity: 2}}
+	if _, err := (&RoundRobin{SkipAtCapacity: true}).Select(Request{Candidates: full}); !errors.Is(err, ErrNoCandidate) {
+		t.Fatalf("Select with every candidate at capacity = %v, want ErrNoCandidate", err)
+	}
+}
+
+func TestWeightedCapacity(t *testing.T) {
+	cases := []struct {
+		name       string
+		params     string
+		candidates []Candidate
+		want       uuid.UUID
+		wantErr    error
+	}{
+		{
+			name:   "lowest utilisation with the default capacity",
+			params: `{"defaultCapacity": 4}`,
+			candidates: []Candidate{
+				{UserID: alice, ActiveTasks: 2},
+				{UserID: bob, ActiveTasks: 1},
+			},
+			want: bob,
+		},
+		{
+			name:   "profile capacity beats the default",
+			params: `{"defaultCapacity": 2}`,
+			candidates: []Candidate{
+				{UserID: alice, ActiveTasks: 3, Capacity: 10},
+				{UserID: bob, ActiveTasks: 1},
+			},
+			want: alice,
+		},
+		{
+			name:   "override beats the profile capacity",
+			params: `{"defaultCapacity": 2, "capacities": {"11111111-1111-1111-1111-111111111111": 4}}`,
+			candidates: []Candidate{
+				{UserID: alice, ActiveTasks: 3, Capacity: 10},
+				{UserID: bob, ActiveTasks: 1, Capacity: 4},
+			},
+			want: bob,
+		},
+		{
+			name:   "zero override excludes a candidate",
+			params: `{"capacities": {"22222222-2222-2222-2222-222222222222": 0}}`,
+			candidates: []Candidate{
+				{UserID: alice, ActiveTasks: 4},
+				{UserID: bob, ActiveTasks: 0},
+			},
+			want: alice,
+		},
+		{
+			name:   "full candidates are skipped",
+			params: `{"capacities": {"11111111-1111-1111-1111-111111111111": 1, "22222222-2222-2222-2222-222222222222": 1}}`,
+			candidates: []Candidate{
+				{UserID: alice, ActiveTasks: 1},
+				{UserID: bob, ActiveTasks: 1},
+			},
+			wantErr: ErrNoCandidate,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := New(StrategyWeightedCapacity, json.RawMessage(tc.params))
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			decision, err := s.Select(Request{Candidates: tc.candidates})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Select error = %v, want %v", err, tc.wantErr)
+			}
+			if err == nil && decision.Candidate.UserID != tc.want {
+				t.Fatalf("selected %s, want %s", decision.Candidate.UserID, tc.want)
+			}
+		})
+	}
+}
+
+func TestSkillMatch(t *testing.T) {
+	task := Task{Language: "Go", Category: "bug", Difficulty: models.DifficultyHard}
+
+	cases := []struct {
+		name         string
+		fallback     bool
+		candidates   []Candidate
+		want         uuid.UUID
+		wantFallback bool
+		wantErr      error
+	}{
+		{
+			name: "language outweighs category",
+			candidates: []Candidate{
+				{UserID: alice, HasProfile: true, Categories: []string{"bug"}},
+				{UserID: bob, HasProfile: true, Languages: []string{"go"}, ActiveTasks: 5},
+			},
+			want: bob,
+		},
+		{
+			name: "ties go to the least loaded",
+			candidates: []Candidate{
+				{UserID: alice, HasProfile: true, Languages: []string{"Go"}, ActiveTasks: 3},
+				{UserID: bob, HasProfile: true, Languages: []string{"Go"}, ActiveTasks: 1},
+			},
+			want: bob,
+		},
+		{
+			name: "max difficulty excludes a match",
+			candidates: []Candidate{
+				{UserID: alice, HasProfile: true, Languages: []string{"Go"}, Categories: []string{"bug"}, MaxDifficulty: models.DifficultyMedium},
+				{UserID: bob, HasProfile: true, Categories: []string{"bug"}, MaxDifficulty: models.DifficultyHard, ActiveTasks: 4},
+			},
+			want: bob,
+		},
+		{
+			name:     "falls back to least loaded without a match",
+			fallback: true,
+			candidates: []Candidate{
+				{UserID: alice, HasProfile: true, Languages: []string{"Python"}, ActiveTasks: 2},
+				{UserID: bob, ActiveTasks: 1},
+				{UserID: carol, HasProfile: true, Languages: []string{"Go"}, MaxDifficulty: models.DifficultyEasy, ActiveTasks: 3},
+			},
+			want:         bob,
+			wantFallback: true,
+		},
+		{
+			name: "no match without fallback",
+			candidates: []Candidate{
+				{UserID: alice, HasProfile: true, Languages: []string{"Python"}},
+				{UserID: bob},
+			},
+			wantErr: ErrNoCandidate,
+		},
+		{
+			name:     "fallback with no candidates",
+			fallback: true,
+			wantErr:  ErrNoCandidate,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &SkillMatch{LanguageWeight: 2, CategoryWeight: 1, Fallback: tc.fallback}
+			decision, err := s.Select(Request{Task: task, Candidates: tc.candidates})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Select error = %v, want %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if decision.Candidate.UserID != tc.want || decision.Fallback != tc.wantFallback {
+				t.Fatalf("selected %s (fallback %v), want %s (fallback %v)", decision.Candidate.UserID, decision.Fallback, tc.want, tc.wantFallback)
+			}
+		})
+	}
+}
+
+func TestNewRejectsInvalidParams(t *testing.T) {
+	cases := []struct {
+		strategy string
+		params   string
+		wantErr  error
+	}{
+		{strategy: "random", wantErr: ErrUnknownStrategy},
+		{strategy: StrategyRoundRobin, params: `{"skipAtCapacity": "yes"}`, wantErr: ErrInvalidParams},
+		{strategy: StrategyWeightedCapacity, params: `{"defaultCapacity": 0}`, wantErr: ErrInvalidParams},
+		{strategy: StrategyWeightedCapacity, params: `{"capacities": {"not-a-uuid": 3}}`, wantErr: ErrInvalidParams},
+		{strategy: StrategyWeightedCapacity, params: `{"capacities": {"11111111-1111-1111-1111-111111111111": -1}}`, wantErr: ErrInvalidParams},
+		{strategy: StrategySkillMatch, params: `{"languageWeight": 0, "categoryWeight": 0}`, wantErr: ErrInvalidParams},
+		{strategy: StrategySkillMatch, params: `{"languageWeight": -1}`, wantErr: ErrInvalidParams},
+		{strategy: StrategyLeastLoaded, params: `{"ignored": true}`},
+		{strategy: StrategySkillMatch, params: `null`},
+	}
+
+	for _, tc := range cases {
+		if _, err := New(tc.strategy, json.RawMessage(tc.params)); !errors.Is(err, tc.wantErr) {
+			t.Errorf("New(%s, %s) error = %v, want %v", tc.strategy, tc.params, err, tc.wantErr)
+		}
+	}
+}
@@ -80,6 +80,12 @@ func AutoMigrate() error {
 	log.Println("  - Running schema migrations...")
 	err = DB.AutoMigrate(
 		&models.User{},
+		&models.SkillProfile{},
+		&models.Availability{},
+		&models.OutOfOffice{},
+		&models.TwoFactorAuth{},
+		&models.RecoveryCode{},
+		&models.TwoFactorPolicy{},
 		&models.Submission{},
 		&models.Review{},
 		&models.ActivityLog{},
@@ -87,6 +93,19 @@ func AutoMigrate() error {
 		&models.RefreshToken{},
 		&models.AuditLog{},
 		&models.ProjectVSubmission{},
+		&models.ValidationJob{},
+		&models.ValidationRun{},
+		&models.ValidationStep{},
+		&models.StatusTransition{},
+		&models.Comment{},
+		&models.SubmissionRevision{},
+		&models.Rubric{},
+		&models.RubricCriterion{},
+		&models.RubricEvaluation{},
+		&models.RubricScore{},
+		&models.AssignmentSetting{},
+		&models.SLAPolicy{},
+		&models.SLABreach{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
@@ -105,6 +124,26 @@ func AutoMigrate() error {
 
 	}
 
+	log.Println("  - Backfilling status transition history...")
+	if err := backfillStatusTransitions(); err != nil {
+		log.Printf("⚠️  Warning: Failed to backfill status transitions: %v", err)
+	}
+
+	log.Println("  - Backfilling submission revisions...")
+	if err := backfillSubmissionRevisions(); err != nil {
+		log.Printf("⚠️  Warning: Failed to backfill submission revisions: %v", err)
+	}
+
+	log.Println("  - Backfilling validation runs...")
+	if err := backfillValidationRuns(); err != nil {
+		log.Printf("⚠️  Warning: Failed to backfill validation runs: %v", err)
+	}
+
+	log.Println("  - Seeding default review rubric...")
+	if err := seedDefaultRubric(); err != nil {
+		log.Printf("⚠️  Warning: Failed to seed default rubric: %v", err)
+	}
+
 	log.Println("✓ Database migrations completed successfully")
 	return nil
 }
@@ -116,6 +155,15 @@ func createPerformanceIndexes() error {
 		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_projectv_reviewer_status ON project_v_submissions(reviewer_id, status) WHERE reviewer_id IS NOT NULL",
 		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_projectv_tester_status ON project_v_submissions(tester_id, status) WHERE tester_id IS NOT NULL",
 		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_projectv_contributor ON project_v_submissions(contributor_id, created_at DESC)",
+		"CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS idx_projectv_repo_commit ON project_v_submissions(LOWER(REGEXP_REPLACE(github_repo, '(\\.git)?/*$', '')), LOWER(commit_hash))",
+
+		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_validation_runs_submission ON validation_runs(submission_id, run_number DESC)",
+		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_validation_jobs_pending ON validation_jobs(run_after) WHERE status = 'PENDING'",
+
+		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_comments_open_blocking ON comments(submission_id) WHERE parent_id IS NULL AND blocking AND NOT resolved",
+		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_rubric_evaluations_submission ON rubric_evaluations(submission_id, created_at DESC)",
+		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_status_transitions_subject ON status_transitions(subject_type, subject_id, created_at)",
+		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_sla_breaches_open ON sla_breaches(policy_id, subject_id) WHERE resolved_at IS NULL",
 
 		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_submissions_status_created ON submissions(status, created_at DESC)",
 		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_submissions_claimedby ON submissions(claimed_by_id, status) WHERE claimed_by_id IS NOT NULL",
@@ -123,6 +171,7 @@ func createPerformanceIndexes() error {
 
 		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_users_email_lower ON users(LOWER(email))",
 		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_users_role_approved ON users(role, is_approved)",
+		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_out_of_offices_user_range ON out_of_offices(user_id, starts_at, ends_at)",
 
 		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_activity_created ON activity_logs(created_at DESC)",
 
@@ -144,6 +193,173 @@ func createPerformanceIndexes() error {
 	return nil
 }
 
+func backfillStatusTransitions() error {
+	subjects := []struct {
+		subjectType   string
+		table         string
+		initialStatus string
+	}{
+		{models.TransitionSubjectSubmission, "submissions", string(models.StatusPending)},
+		{models.TransitionSubjectProjectV, "project_v_submissions", string(models.ProjectVStatusSubmitted)},
+	}
+
+	for _, subject := range subjects {
+		result := DB.Exec(fmt.Sprintf(`
+			INSERT INTO status_transitions (id, subject_type, subject_id, from_status, to_status, actor_role, actor_name, reason, created_at)
+			SELECT gen_random_uuid(), ?, s.id, NULL, ?, 'SYSTEM', 'System', 'Backfilled from submission creation time', s.created_at
+			FROM %s s
+			WHERE NOT EXISTS (
+				SELECT 1 FROM status_transitions t WHERE t.subject_type = ? AND t.subject_id = s.id
+			)`, subject.table), subject.subjectType, subject.initialStatus, subject.subjectType)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		err := DB.Exec(fmt.Sprintf(`
+			INSERT INTO status_transitions (id, subject_type, subject_id, from_status, to_status, actor_role, actor_name, reason, created_at)
+			SELECT gen_random_uuid(), ?, s.id, ?, s.status, 'SYSTEM', 'System', 'Backfilled from status at migration time', s.updated_at
+			FROM %s s
+			WHERE s.status <> ?
+			AND (
+				SELECT COUNT(*) FROM status_transitions t WHERE t.subject_type = ? AND t.subject_id = s.id
+			) = 1`, subject.table), subject.subjectType, subject.initialStatus, subject.initialStatus, subject.subjectType).Error
+		if err != nil {
+			return err
+		}
+		log.Printf("    ✓ Backfilled history for %d %s", result.RowsAffected, subject.table)
+	}
+	return nil
+}
+
+func backfillSubmissionRevisions() error {
+	columns := map[string]string{
+		models.ArtifactTestPatch:     "test_patch_url",
+		models.ArtifactDockerfile:    "dockerfile_url",
+		models.ArtifactSolutionPatch: "solution_patch_url",
+	}
+
+	for _, artifact := range models.ArtifactNames {
+		column := columns[artifact]
+		err := DB.Exec(fmt.Sprintf(`
+			INSERT INTO submission_revisions (id, submission_id, artifact, version, storage_key, file_name, content_type, size_bytes, sha256, uploaded_by_id, created_at)
+			SELECT gen_random_uuid(), s.id, ?, 1, s.%[1]s, regexp_replace(s.%[1]s, '^[0-9]+-', ''), 'text/plain', 0, '', s.contributor_id, s.created_at
+			FROM project_v_submissions s
+			WHERE s.%[1]s <> ''
+			AND NOT EXISTS (
+				SELECT 1 FROM submission_revisions r WHERE r.submission_id = s.id AND r.artifact = ?
+			)`, column), artifact, artifact).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func backfillValidationRuns() error {
+	if !DB.Migrator().HasColumn("project_v_submissions", "clone_success") {
+		return nil
+	}
+
+	legacySteps := []struct {
+		name   string
+		prefix string
+	}{
+		{models.StepClone, "clone"},
+		{models.StepTestPatch, "test_patch"},
+		{models.StepDockerBuild, "docker_build"},
+		{models.StepBaseTests, "base_test"},
+		{models.StepNewTests, "new_test"},
+		{models.StepSolutionPatch, "solution_patch"},
+		{models.StepFinalBaseTests, "final_base_test"},
+		{models.StepFinalNewTests, "final_new_test"},
+	}
+	positions := make(map[string]int, len(models.ValidationStepNames))
+	for i, name := range models.ValidationStepNames {
+		positions[name] = i + 1
+	}
+
+	errorColumns := make([]string, 0, len(legacySteps))
+	for _, step := range legacySteps {
+		errorColumns = append(errorColumns, fmt.Sprintf("NULLIF(s.%s_error, '')", step.prefix))
+	}
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(fmt.Sprintf(`
+			INSERT INTO validation_runs (id, submission_id, run_number, trigger, status, commit_hash, test_patch_key, dockerfile_key, solution_patch_key, error, started_at, finished_at, duration_ms, created_at)
+			SELECT gen_random_uuid(), s.id, 1, 'BACKFILL',
+				CASE WHEN s.final_new_test_success THEN ? ELSE ? END,
+				s.commit_hash, s.test_patch_url, s.dockerfile_url, s.solution_patch_url,
+				COALESCE(%s, ''), s.updated_at, s.updated_at, 0, s.updated_at
+			FROM project_v_submissions s
+			WHERE (s.processing_complete OR s.clone_success OR COALESCE(s.clone_error, '') <> '')
+			AND NOT EXISTS (
+				SELECT 1 FROM validation_runs r WHERE r.submission_id = s.id
+			)`, strings.Join(errorColumns, ", ")),
+			string(models.ValidationRunPassed), string(models.ValidationRunFailed))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		for _, step := range legacySteps {
+			err := tx.Exec(fmt.Sprintf(`
+				INSERT INTO validation_steps (id, run_id, position, name, status, duration_ms, output_truncated, error)
+				SELECT gen_random_uuid(), r.id, ?, ?,
+					CASE WHEN s.%[1]s_success THEN ? WHEN COALESCE(s.%[1]s_error, '') <> '' THEN ? ELSE ? END,
+					0, false, COALESCE(s.%[1]s_error, '')
+				FROM validation_runs r
+				JOIN project_v_submissions s ON s.id = r.submission_id
+				WHERE r.trigger = 'BACKFILL'
+				AND NOT EXISTS (
+					SELECT 1 FROM validation_steps v WHERE v.run_id = r.id AND v.name = ?
+				)`, step.prefix),
+				positions[step.name], step.name,
+				string(models.ValidationStepPassed), string(models.ValidationStepFailed), string(models.ValidationStepSkipped),
+				step.name).Error
+			if err != nil {
+				return err
+			}
+		}
+		log.Printf("    ✓ Backfilled validation runs for %d submissions", result.RowsAffected)
+		return nil
+	})
+}
+
+func seedDefaultRubric() error {
+	var count int64
+	err := DB.Model(&models.Rubric{}).
+		Where("category = '' AND difficulty = ''").
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	rubric := models.Rubric{
+		Name:        "General review",
+		Description: "Default rubric applied when no category or difficulty specific rubric is configured",
+		Active:      true,
+		Criteria: []models.RubricCriterion{
+			{Position: 1, Name: "Correctness", Description: "The solution patch fixes the issue without regressions", Weight: 3, MinScore: 1, MaxScore: 5},
+			{Position: 2, Name: "Test quality", Description: "Tests fail before and pass after the solution, and cover the issue", Weight: 2, MinScore: 1, MaxScore: 5},
+			{Position: 3, Name: "Environment", Description: "The Dockerfile builds reproducibly and pins its dependencies", Weight: 1, MinScore: 1, MaxScore: 5},
+			{Position: 4, Name: "Clarity", Description: "The description and patches are clear and focused", Weight: 1, MinScore: 1, MaxScore: 5},
+		},
+	}
+	if err := DB.Create(&rubric).Error; err != nil {
+		return err
+	}
+	log.Println("    ✓ Default rubric created")
+	return nil
+}
+
 func GetDB() *gorm.DB {
 	return DB
 }
@@ -0,0 +1,261 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+const DefaultContext = 3
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type op struct {
+	kind opKind
+	a, b int
+}
+
+func Lines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func Unified(fromName, toName, from, to string, context int) string {
+	a, b := Lines(from), Lines(to)
+	ops := compute(a, b)
+
+	changed := false
+	for _, o := range ops {
+		if o.kind != opEqual {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+
+	for start := 0; start < len(ops); {
+		for start < len(ops) && ops[start].kind == opEqual {
+			start++
+		}
+		if start >= len(ops) {
+			break
+		}
+
+		first := start - context
+		if first < 0 {
+			first = 0
+		}
+		last := start
+		for i := start; i < len(ops); i++ {
+			if ops[i].kind != opEqual {
+				last = i
+				continue
+			}
+			if i-last > 2*context {
+				break
+			}
+		}
+		end := last + context + 1
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		writeHunk(&out, a, b, ops[first:end])
+		start = end
+	}
+
+	return out.String()
+}
+
+func writeHunk(out *strings.Builder, a, b []string, ops []op) {
+	aStart, bStart := -1, -1
+	aCount, bCount := 0, 0
+	for _, o := range ops {
+		switch o.kind {
+		case opEqual:
+			if aStart < 0 {
+				aStart = o.a
+			}
+			if bStart < 0 {
+				bStart = o.b
+			}
+			aCount++
+			bCount++
+		case opDelete:
+			if aStart < 0 {
+				aStart = o.a
+			}
+			aCount++
+		case opInsert:
+			if bStart < 0 {
+				bStart = o.b
+			}
+			bCount++
+		}
+	}
+	if aStart < 0 {
+		aStart = ops[0].a
+	}
+	if bStart < 0 {
+		bStart = ops[0].b
+	}
+
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(aStart, aCount), hunkRange(bStart, bCount))
+	for _, o := range ops {
+		switch o.kind {
+		case opEqual:
+			writeLine(out, " ", a[o.a])
+		case opDelete:
+			writeLine(out, "-", a[o.a])
+		case opInsert:
+			writeLine(out, "+", b[o.b])
+		}
+	}
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func writeLine(out *strings.Builder, prefix, line string) {
+	out.WriteString(prefix)
+	out.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		out.WriteString("\n\\ No newline at end of file\n")
+	}
+}
+
+const maxEditDistance = 2000
+
+func compute(a, b []string) []op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []op
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, op{kind: opEqual, a: i, b: i})
+	}
+	for _, o := range myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		o.a += prefix
+		o.b += prefix
+		ops = append(ops, o)
+	}
+	for i := suffix; i > 0; i-- {
+		ops = append(ops, op{kind: opEqual, a: len(a) - i, b: len(b) - i})
+	}
+	return ops
+}
+
+func myers(a, b []string) []op {
+	n, m := len(a), len(b)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+
+	offset := max
+	v := make([]int, 2*max+2)
+	var trace [][]int
+
+	for d := 0; d <= max && d <= maxEditDistance; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+2]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, n, m)
+			}
+		}
+	}
+
+	ops := make([]op, 0, max)
+	for i := range a {
+		ops = append(ops, op{kind: opDelete, a: i, b: 0})
+	}
+	for j := range b {
+		ops = append(ops, op{kind: opInsert, a: n, b: j})
+	}
+	return ops
+}
+
+func backtrack(trace [][]int, n, m int) []op {
+	var ops []op
+	x, y := n, m
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d] }
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := 0
+		if d > 0 {
+			prevX = at(prevK)
+		}
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, op{kind: opEqual, a: x, b: y})
+		}
+		if d > 0 {
+			if x == prevX {
+				y--
+				ops = append(ops, op{kind: opInsert, a: x, b: y})
+			} else {
+				x--
+				ops = append(ops, op{kind: opDelete, a: x, b: y})
+			}
+		}
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
@@ -0,0 +1,200 @@
+package diff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedRoundTrip(t *testing.T) {
+	cases := []struct {
+		name string
+		from string
+		to   string
+	}{
+		{name: "single change", from: "a\nb\nc\n", to: "a\nB\nc\n"},
+		{name: "change at the start and end", from: "a\nb\nc\nd\n", to: "A\nb\nc\nD\n"},
+		{name: "insert only", from: "a\nb\n", to: "a\nx\ny\nb\n"},
+		{name: "delete only", from: "a\nx\ny\nb\n", to: "a\nb\n"},
+		{name: "append lines", from: "a\n", to: "a\nb\nc\n"},
+		{name: "separate hunks", from: numbered("line", 0, 30), to: strings.Replace(strings.Replace(numbered("line", 0, 30), "line 2\n", "line two\n", 1), "line 25\n", "", 1)},
+		{name: "nearby changes share a hunk", from: numbered("line", 0, 12), to: strings.Replace(strings.Replace(numbered("line", 0, 12), "line 3\n", "three\n", 1), "line 8\n", "eight\n", 1)},
+		{name: "replace everything", from: "a\nb\n", to: "c\nd\ne\n"},
+		{name: "empty to content", from: "", to: "a\nb\n"},
+		{name: "content to empty", from: "a\nb\n", to: ""},
+		{name: "missing trailing newline added", from: "a\nb", to: "a\nb\n"},
+		{name: "missing trailing newline removed", from: "a\nb\n", to: "a\nb"},
+		{name: "last line changed without trailing newline", from: "a\nb", to: "a\nc"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			patch := Unified("a/file", "b/file", tc.from, tc.to, DefaultContext)
+			if patch == "" {
+				t.Fatal("Unified returned no diff for different inputs")
+			}
+			if !strings.HasPrefix(patch, "--- a/file\n+++ b/file\n@@ ") {
+				t.Fatalf("patch is missing file headers:\n%s", patch)
+			}
+			if got := apply(t, tc.from, patch); got != tc.to {
+				t.Fatalf("applying the patch gave %q, want %q\n%s", got, tc.to, patch)
+			}
+		})
+	}
+}
+
+func TestUnifiedOutput(t *testing.T) {
+	cases := []struct {
+		name string
+		from string
+		to   string
+		want string
+	}{
+		{name: "identical files", from: "a\nb\n", to: "a\nb\n", want: ""},
+		{name: "both empty", from: "", to: "", want: ""},
+		{name: "empty to content", from: "", to: "a\n", want: "--- a\n+++ b\n@@ -0,0 +1 @@\n+a\n"},
+		{name: "content to empty", from: "a\nb\n", to: "", want: "--- a\n+++ b\n@@ -1,2 +0,0 @@\n-a\n-b\n"},
+		{
+			name: "no trailing newline",
+			from: "a\nb",
+			to:   "a\nc",
+			want: "--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n",
+		},
+		{
+			name: "trailing newline added",
+			from: "a",
+			to:   "a\n",
+			want: "--- a\n+++ b\n@@ -1 +1 @@\n-a\n\\ No newline at end of file\n+a\n",
+		},
+		{
+			name: "context is limited",
+			from: numbered("l", 0, 10),
+			to:   strings.Replace(numbered("l", 0, 10), "l 5\n", "five\n", 1),
+			want: "--- a\n+++ b\n@@ -3,7 +3,7 @@\n l 2\n l 3\n l 4\n-l 5\n+five\n l 6\n l 7\n l 8\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Unified("a", "b", tc.from, tc.to, DefaultContext); got != tc.want {
+				t.Fatalf("Unified = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedFallsBackAboveMaxEditDistance(t *testing.T) {
+	for _, size := range []int{maxEditDistance / 4, maxEditDistance} {
+		t.Run(strconv.Itoa(size), func(t *testing.T) {
+			var from, to strings.Builder
+			for i := 0; i < size; i++ {
+				fmt.Fprintf(&from, "old %d\nshared %d\n", i, i)
+				fmt.Fprintf(&to, "new %d\nshared %d\n", i, i)
+			}
+			from.WriteString("old end\n")
+			to.WriteString("new end\n")
+
+			patch := Unified("a", "b", from.String(), to.String(), DefaultContext)
+			if got := apply(t, from.String(), patch); got != to.String() {
+				t.Fatal("applying the patch did not reproduce the new file")
+			}
+
+			kept := strings.Count(patch, "\n shared ")
+			if size > maxEditDistance/2 {
+				want := fmt.Sprintf("@@ -1,%[1]d +1,%[1]d @@\n", 2*size+1)
+				if !strings.Contains(patch, want) || kept != 0 {
+					t.Fatalf("expected a single replace-everything hunk, kept %d shared lines", kept)
+				}
+			} else if kept != size {
+				t.Fatalf("kept %d of %d shared lines as context", kept, size)
+			}
+		})
+	}
+}
+
+func TestLines(t *testing.T) {
+	cases := map[string][]string{
+		"":         nil,
+		"a":        {"a"},
+		"a\n":      {"a\n"},
+		"a\nb":     {"a\n", "b"},
+		"a\n\nb\n": {"a\n", "\n", "b\n"},
+	}
+	for text, want := range cases {
+		got := Lines(text)
+		if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", want) {
+			t.Errorf("Lines(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func numbered(prefix string, start, end int) string {
+	var b strings.Builder
+	for i := start; i < end; i++ {
+		fmt.Fprintf(&b, "%s %d\n", prefix, i)
+	}
+	return b.String()
+}
+
+func apply(t *testing.T, from, patch string) string {
+	t.Helper()
+
+	source := Lines(from)
+	lines := Lines(patch)
+	if len(lines) < 2 {
+		t.Fatalf("patch has no headers:\n%s", patch)
+	}
+
+	var out []string
+	cursor := 0
+	for i := 2; i < len(lines); {
+		var aStart, aCount, bStart, bCount int
+		if _, err := fmt.Sscanf(normalizeHunkHeader(lines[i]), "@@ -%d,%d +%d,%d @@", &aStart, &aCount, &bStart, &bCount); err != nil {
+			t.Fatalf("bad hunk header %q: %v", lines[i], err)
+		}
+		if aCount > 0 {
+			aStart--
+		}
+		if aStart < cursor {
+			t.Fatalf("hunk %q overlaps the previous hunk", lines[i])
+		}
+		out = append(out, source[cursor:aStart]...)
+		cursor = aStart
+		i++
+
+		for ; i < len(lines) && !strings.HasPrefix(lines[i], "@@ "); i++ {
+			line := lines[i]
+			if i+1 < len(lines) && lines[i+1] == "\\ No newline at end of file\n" {
+				line = strings.TrimSuffix(line, "\n")
+			}
+			switch line[0] {
+			case ' ', '-':
+				if cursor >= len(source) || source[cursor] != line[1:] {
+					t.Fatalf("patch line %q does not match the source", line)
+				}
+				if line[0] == ' ' {
+					out = append(out, line[1:])
+				}
+				cursor++
+			case '+':
+				out = append(out, line[1:])
+			case '\\':
+			default:
+				t.Fatalf("unexpected patch line %q", line)
+			}
+		}
+	}
+	out = append(out, source[cursor:]...)
+	return strings.Join(out, "")
+}
+
+func normalizeHunkHeader(header string) string {
+	fields := strings.Fields(header)
+	for i := 1; i <= 2 && i < len(fields); i++ {
+		if !strings.Contains(fields[i], ",") {
+			fields[i] += ",1"
+		}
+	}
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,82 @@
+package githost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrRepoNotFound    = errors.New("repository not found or not accessible")
+	ErrCommitNotFound  = errors.New("commit not found in repository")
+	ErrAmbiguousCommit = errors.New("short commit hash is ambiguous")
+	ErrHostUnavailable = errors.New("git host is unavailable")
+)
+
+type GitHost interface {
+	Name() string
+	VerifyRepository(ctx context.Context, repoURL string) error
+	ResolveCommit(ctx context.Context, repoURL, commitHash string) (string, error)
+}
+
+var (
+	current GitHost
+	mu      sync.RWMutex
+)
+
+func Init() (GitHost, error) {
+	host, err := New(os.Getenv("GIT_HOST"))
+	if err != nil {
+		return nil, err
+	}
+
+	Set(host)
+	return host, nil
+}
+
+func New(name string) (GitHost, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "github":
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			log.Println("⚠️  GITHUB_TOKEN is not set, verifying GitHub repositories unauthenticated (public repositories only, 60 requests per hour)")
+		}
+		return NewGitHub(os.Getenv("GITHUB_API_URL"), token), nil
+	case "local":
+		return NewLocalGit(os.Getenv("GIT_LOCAL_ROOT")), nil
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown git host %q (expected github, local or none)", name)
+	}
+}
+
+func Set(host GitHost) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = host
+}
+
+func Current() GitHost {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+var commitHashPattern = regexp.MustCompile(`^[0-9a-fA-F]{4,40}$`)
+
+func IsCommitHash(value string) bool {
+	return commitHashPattern.MatchString(value)
+}
+
+func NormalizeRepoURL(repoURL string) string {
+	normalized := strings.ToLower(strings.TrimSpace(repoURL))
+	normalized = strings.TrimRight(normalized, "/")
+	normalized = strings.TrimSuffix(normalized, ".git")
+	return strings.TrimRight(normalized, "/")
+}
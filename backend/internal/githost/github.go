@@ -0,0 +1,125 @@
+package githost
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type GitHub struct {
+	apiURL string
+	token  string
+	client *http.Client
+}
+
+func NewGitHub(apiURL, token string) *GitHub {
+	if apiURL == "" {
+		apiURL = "https://api.github.com"
+	}
+	return &GitHub{
+		apiURL: strings.TrimRight(apiURL, "/"),
+		token:  token,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (g *GitHub) Name() string {
+	return "github"
+}
+
+func (g *GitHub) VerifyRepository(ctx context.Context, repoURL string) error {
+	owner, repo, err := parseGitHubURL(repoURL)
+	if err != nil {
+		return err
+	}
+
+	status, err := g.get(ctx, fmt.Sprintf("/repos/%s/%s", owner, repo), nil)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNotFound {
+		return ErrRepoNotFound
+	}
+	return nil
+}
+
+func (g *GitHub) ResolveCommit(ctx context.Context, repoURL, commitHash string) (string, error) {
+	owner, repo, err := parseGitHubURL(repoURL)
+	if err != nil {
+		return "", err
+	}
+	if !IsCommitHash(commitHash) {
+		return "", ErrCommitNotFound
+	}
+
+	var commit struct {
+		SHA string `json:"sha"`
+	}
+	status, err := g.get(ctx, fmt.Sprintf("/repos/%s/%s/commits/%s", owner, repo, url.PathEscape(commitHash)), &commit)
+	if err != nil {
+		return "", err
+	}
+
+	switch status {
+	case http.StatusNotFound:
+		return "", ErrCommitNotFound
+	case http.StatusUnprocessableEntity:
+		if len(commitHash) < 40 {
+			return "", ErrAmbiguousCommit
+		}
+		return "", ErrCommitNotFound
+	}
+
+	if !strings.HasPrefix(strings.ToLower(commit.SHA), strings.ToLower(commitHash)) {
+		return "", ErrCommitNotFound
+	}
+	return commit.SHA, nil
+}
+
+func (g *GitHub) get(ctx context.Context, path string, out interface{}) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.apiURL+path, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if g.token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrHostUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		if out != nil {
+			if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+				return 0, fmt.Errorf("%w: invalid response: %v", ErrHostUnavailable, err)
+			}
+		}
+		return resp.StatusCode, nil
+	case resp.StatusCode == http.StatusNotFound, resp.StatusCode == http.StatusUnprocessableEntity:
+		return resp.StatusCode, nil
+	default:
+		return resp.StatusCode, fmt.Errorf("%w: GitHub returned %s", ErrHostUnavailable, resp.Status)
+	}
+}
+
+func parseGitHubURL(repoURL string) (string, string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(repoURL))
+	if err != nil || !strings.EqualFold(parsed.Host, "github.com") {
+		return "", "", ErrRepoNotFound
+	}
+
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", ErrRepoNotFound
+	}
+	return parts[0], strings.TrimSuffix(parts[1], ".git"), nil
+}
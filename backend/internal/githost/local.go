@@ -0,0 +1,73 @@
+package githost
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+type LocalGit struct {
+	root string
+}
+
+func NewLocalGit(root string) *LocalGit {
+	return &LocalGit{root: root}
+}
+
+func (l *LocalGit) Name() string {
+	return "local"
+}
+
+func (l *LocalGit) VerifyRepository(ctx context.Context, repoURL string) error {
+	if err := exec.CommandContext(ctx, "git", "ls-remote", "--heads", l.location(repoURL)).Run(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return ErrRepoNotFound
+	}
+	return nil
+}
+
+func (l *LocalGit) ResolveCommit(ctx context.Context, repoURL, commitHash string) (string, error) {
+	if !IsCommitHash(commitHash) {
+		return "", ErrCommitNotFound
+	}
+
+	location := l.location(repoURL)
+	if info, err := os.Stat(location); err != nil || !info.IsDir() {
+		return "", ErrRepoNotFound
+	}
+
+	output, err := exec.CommandContext(ctx, "git", "-C", location, "rev-parse", "--verify", commitHash+"^{commit}").Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && strings.Contains(string(exitErr.Stderr), "ambiguous") {
+			return "", ErrAmbiguousCommit
+		}
+		return "", ErrCommitNotFound
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+func (l *LocalGit) location(repoURL string) string {
+	location := strings.TrimPrefix(repoURL, "file://")
+	if strings.Contains(location, "://") {
+		parsed, err := url.Parse(repoURL)
+		if err != nil || l.root == "" {
+			return repoURL
+		}
+		return filepath.Join(l.root, parsed.Host, strings.TrimSuffix(strings.Trim(parsed.Path, "/"), ".git"))
+	}
+	if l.root != "" && !filepath.IsAbs(location) {
+		return filepath.Join(l.root, location)
+	}
+	return location
+}
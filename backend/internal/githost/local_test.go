@@ -0,0 +1,141 @@
+package githost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalGitResolveCommit(t *testing.T) {
+	root := t.TempDir()
+	commits := initRepo(t, filepath.Join(root, "github.com", "acme", "widgets"), 2000)
+	host := NewLocalGit(root)
+	repoURL := "https://github.com/acme/widgets.git"
+
+	ambiguous := ambiguousPrefix(commits)
+	if ambiguous == "" {
+		t.Fatal("no two commits share a 4 character prefix")
+	}
+
+	head := commits[len(commits)-1]
+	cases := []struct {
+		name    string
+		repoURL string
+		hash    string
+		want    string
+		wantErr error
+	}{
+		{name: "full hash", repoURL: repoURL, hash: head, want: head},
+		{name: "short hash", repoURL: repoURL, hash: head[:7], want: head},
+		{name: "upper case short hash", repoURL: repoURL, hash: strings.ToUpper(head[:10]), want: head},
+		{name: "ambiguous hash", repoURL: repoURL, hash: ambiguous, wantErr: ErrAmbiguousCommit},
+		{name: "missing commit", repoURL: repoURL, hash: strings.Repeat("0", 40), wantErr: ErrCommitNotFound},
+		{name: "not a hash", repoURL: repoURL, hash: "main", wantErr: ErrCommitNotFound},
+		{name: "missing repository", repoURL: "https://github.com/acme/missing.git", hash: head, wantErr: ErrRepoNotFound},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := host.ResolveCommit(context.Background(), tc.repoURL, tc.hash)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("ResolveCommit(%q) error = %v, want %v", tc.hash, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Fatalf("ResolveCommit(%q) = %q, want %q", tc.hash, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLocalGitVerifyRepository(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "github.com", "acme", "widgets")
+	initRepo(t, repo, 1)
+	host := NewLocalGit(root)
+
+	for _, repoURL := range []string{"https://github.com/acme/widgets", "file://" + repo, repo, "github.com/acme/widgets"} {
+		if err := host.VerifyRepository(context.Background(), repoURL); err != nil {
+			t.Errorf("VerifyRepository(%q) = %v", repoURL, err)
+		}
+	}
+	for _, repoURL := range []string{"https://github.com/acme/missing", filepath.Join(root, "missing")} {
+		if err := host.VerifyRepository(context.Background(), repoURL); !errors.Is(err, ErrRepoNotFound) {
+			t.Errorf("VerifyRepository(%q) = %v, want ErrRepoNotFound", repoURL, err)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+
+	host, err := New("")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, ok := host.(*GitHub); !ok {
+		t.Fatalf("New(\"\") = %T, want *GitHub without a token", host)
+	}
+
+	host, err = New("none")
+	if err != nil || host != nil {
+		t.Fatalf("New(\"none\") = %v, %v, want verification disabled", host, err)
+	}
+
+	if _, err := New("gitlab"); err == nil {
+		t.Fatal("New accepted an unknown driver")
+	}
+}
+
+func initRepo(t *testing.T, dir string, count int) []string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	git(t, "", "init", "--quiet", dir)
+
+	var stream strings.Builder
+	for i := 0; i < count; i++ {
+		message := fmt.Sprintf("commit %d", i)
+		fmt.Fprintf(&stream, "commit refs/heads/main\ncommitter Test <test@example.com> 1700000000 +0000\ndata %d\n%s\n\n", len(message), message)
+	}
+	cmd := exec.Command("git", "-C", dir, "fast-import", "--quiet")
+	cmd.Stdin = strings.NewReader(stream.String())
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git fast-import: %v\n%s", err, output)
+	}
+
+	commits := strings.Fields(git(t, dir, "rev-list", "--reverse", "main"))
+	if len(commits) != count {
+		t.Fatalf("created %d commits, want %d", len(commits), count)
+	}
+	return commits
+}
+
+func ambiguousPrefix(commits []string) string {
+	seen := make(map[string]bool, len(commits))
+	for _, commit := range commits {
+		if seen[commit[:4]] {
+			return commit[:4]
+		}
+		seen[commit[:4]] = true
+	}
+	return ""
+}
+
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	output, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return string(output)
+}
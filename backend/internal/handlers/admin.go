@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"log"
 	"net/http"
 	"strconv"
 	"time"
@@ -129,6 +130,12 @@ func GetStats(c *gin.Context) {
 		statusCountsMap[sc.Status] = sc.Count
 	}
 
+	slaStats, err := services.GetSLAStats()
+	if err != nil {
+		log.Printf("Failed to load SLA stats: %v", err)
+		slaStats = &services.SLAStats{ByPipeline: map[string]int64{}}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"overview": gin.H{
 			"totalUsers":        totalUsers,
@@ -142,7 +149,9 @@ func GetStats(c *gin.Context) {
 			"pendingReviews":    pendingReviews,
 			"queuedTasks":       queuedTasks,
 			"statusCounts":      statusCountsMap,
+			"slaBreaches":       slaStats.OpenBreaches,
 		},
+		"sla":          slaStats,
 		"contributors": contributorStats,
 		"testers":      testerStats,
 	})
@@ -269,6 +278,11 @@ func GetAnalytics(c *gin.Context) {
 		})
 	}
 
+	rubricMetrics, err := services.GetRubricQualityMetrics(10)
+	if err != nil {
+		log.Printf("Failed to aggregate rubric scores: %v", err)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"overview": gin.H{
 			"totalSubmissions": totalSubmissions,
@@ -279,6 +293,7 @@ func GetAnalytics(c *gin.Context) {
 		"topContributors": topContributors,
 		"domains":         domains,
 		"languages":       languages,
+		"rubrics":         rubricMetrics,
 	})
 }
 
@@ -575,46 +590,69 @@ func GetAllProjectVSubmissions(c *gin.Context) {
 		return
 	}
 
+	submissionIDs := make([]uuid.UUID, 0, len(submissions))
+	for _, sub := range submissions {
+		submissionIDs = append(submissionIDs, sub.ID)
+	}
+	latestRuns, _ := services.GetLatestValidationRuns(submissionIDs)
+
 	submissionsResponse := make([]gin.H, 0, len(submissions))
 	for _, sub := range submissions {
 		submissionData := gin.H{
-			"id":                   sub.ID,
-			"title":                sub.Title,
-			"language":             sub.Language,
-			"category":             sub.Category,
-			"difficulty":           sub.Difficulty,
-			"description":          sub.Description,
-			"githubRepo":           sub.GithubRepo,
-			"commitHash":           sub.CommitHash,
-			"issueUrl":             sub.IssueURL,
-			"testPatchUrl":         sub.TestPatchURL,
-			"dockerfileUrl":        sub.DockerfileURL,
-			"solutionPatchUrl":     sub.SolutionPatchURL,
-			"status":               sub.Status,
-			"createdAt":            sub.CreatedAt,
-			"updatedAt":            sub.UpdatedAt,
-			"testerFeedback":       sub.TesterFeedback,
-			"reviewerFeedback":     sub.ReviewerFeedback,
-			"hasChangesRequested":  sub.HasChangesRequested,
-			"changesDone":          sub.ChangesDone,
-			"processingComplete":   sub.ProcessingComplete,
-			"processingLogs":       sub.ProcessingLogs,
-			"cloneSuccess":         sub.CloneSuccess,
-			"cloneError":           sub.CloneError,
-			"testPatchSuccess":     sub.TestPatchSuccess,
-			"testPatchError":       sub.TestPatchError,
-			"dockerBuildSuccess":   sub.DockerBuildSuccess,
-			"dockerBuildError":     sub.DockerBuildError,
-			"baseTestSuccess":      sub.BaseTestSuccess,
-			"baseTestError":        sub.BaseTestError,
-			"newTestSuccess":       sub.NewTestSuccess,
-			"newTestError":         sub.NewTestError,
-			"solutionPatchSuccess": sub.SolutionPatchSuccess,
-			"solutionPatchError":   sub.SolutionPatchError,
-			"finalBaseTestSuccess": sub.FinalBaseTestSuccess,
-			"finalBaseTestError":   sub.FinalBaseTestError,
-			"finalNewTestSuccess":  sub.FinalNewTestSuccess,
-			"finalNewTestError":    sub.FinalNewTestError,
+			"id":                  sub.ID,
+			"title":               sub.Title,
+			"language":            sub.Language,
+			"category":            sub.Category,
+			"difficulty":          sub.Difficulty,
+			"description":         sub.Description,
+			"githubRepo":          sub.GithubRepo,
+			"commitHash":          sub.CommitHash,
+			"issueUrl":            sub.IssueURL,
+			"testPatchUrl":        sub.TestPatchURL,
+			"dockerfileUrl":       sub.DockerfileURL,
+			"solutionPatchUrl":    sub.SolutionPatchURL,
+			"status":              sub.Status,
+			"createdAt":           sub.CreatedAt,
+			"updatedAt":           sub.UpdatedAt,
+			"testerFeedback":      sub.TesterFeedback,
+			"reviewerFeedback":    sub.ReviewerFeedback,
+			"hasChangesRequested": sub.HasChangesRequested,
+			"changesDone":         sub.ChangesDone,
+			"processingComplete":  sub.ProcessingComplete,
+			"processingLogs":      sub.ProcessingLogs,
+			"failToPass":          sub.FailToPass,
+			"passToPass":          sub.PassToPass,
+			"passToFail":          sub.PassToFail,
+			"patchAnalysis":       sub.PatchAnalysis,
+		}
+
+		if run, ok := latestRuns[sub.ID]; ok {
+			submissionData["latestRun"] = gin.H{
+				"id":         run.ID,
+				"runNumber":  run.RunNumber,
+				"status":     run.Status,
+				"error":      run.Error,
+				"startedAt":  run.StartedAt,
+				"finishedAt": run.FinishedAt,
+			}
+
+			results := services.ValidationStepResultsFor(run)
+			submissionData["cloneSuccess"] = results.CloneSuccess
+			submissionData["cloneError"] = results.CloneError
+			submissionData["testPatchSuccess"] = results.TestPatchSuccess
+			submissionData["testPatchError"] = results.TestPatchError
+			submissionData["dockerBuildSuccess"] = results.DockerBuildSuccess
+			submissionData["dockerBuildError"] = results.DockerBuildError
+			submissionData["baseTestSuccess"] = results.BaseTestSuccess
+			submissionData["baseTestError"] = results.BaseTestError
+			submissionData["newTestSuccess"] = results.NewTestSuccess
+			submissionData["newTestError"] = results.NewTestError
+			submissionData["solutionPatchSuccess"] = results.SolutionPatchSuccess
+			submissionData["solutionPatchError"] = results.SolutionPatchError
+			submissionData["finalBaseTestSuccess"] = results.FinalBaseTestSuccess
+			submissionData["finalBaseTestError"] = results.FinalBaseTestError
+			submissionData["finalNewTestSuccess"] = results.FinalNewTestSuccess
+			submissionData["finalNewTestError"] = results.FinalNewTestError
 		}
 
 		if sub.SubmittedAccount != nil {
@@ -3,10 +3,13 @@ package handlers
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
+	"log"
 	"net/http"
 	"time"
 
 	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/mailer"
 	"github.com/adzzatxperts/backend/internal/models"
 	"github.com/adzzatxperts/backend/internal/services"
 	"github.com/adzzatxperts/backend/internal/utils"
@@ -69,22 +72,7 @@ func Signup(c *gin.Context) {
 		TargetType:  &targetType,
 	})
 
-	token, err := utils.GenerateJWT(user.ID.String(), user.Email, string(user.Role))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
-		return
-	}
-
-	c.JSON(http.StatusCreated, gin.H{
-		"user": gin.H{
-			"id":         user.ID,
-			"email":      user.Email,
-			"name":       user.Name,
-			"role":       user.Role,
-			"isApproved": user.IsApproved,
-		},
-		"token": token,
-	})
+	signinResponse(c, &user, http.StatusCreated)
 }
 
 func Signin(c *gin.Context) {
@@ -105,13 +93,11 @@ func Signin(c *gin.Context) {
 		return
 	}
 
-	token, err := utils.GenerateJWT(user.ID.String(), user.Email, string(user.Role))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
-		return
-	}
+	signinResponse(c, &user, http.StatusOK)
+}
 
-	c.JSON(http.StatusOK, gin.H{
+func authResponse(user *models.User, tokens *services.TokenPair) gin.H {
+	return gin.H{
 		"user": gin.H{
 			"id":         user.ID,
 			"email":      user.Email,
@@ -119,8 +105,19 @@ func Signin(c *gin.Context) {
 			"role":       user.Role,
 			"isApproved": user.IsApproved,
 		},
-		"token": token,
-	})
+		"token":            tokens.AccessToken,
+		"accessToken":      tokens.AccessToken,
+		"refreshToken":     tokens.RefreshToken,
+		"expiresIn":        tokens.ExpiresIn,
+		"refreshExpiresAt": tokens.RefreshExpiresAt,
+	}
+}
+
+func clientInfo(c *gin.Context) services.ClientInfo {
+	return services.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
 }
 
 func GetMe(c *gin.Context) {
@@ -151,9 +148,30 @@ func GetMe(c *gin.Context) {
 }
 
 func Logout(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	uid, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if sessionID, err := uuid.Parse(c.GetString("sessionId")); err == nil {
+		if err := services.RevokeSession(uid, sessionID); err != nil && !errors.Is(err, services.ErrSessionNotFound) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+			return
+		}
+	}
+
+	if err := services.BumpTokenVersion(uid); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
 }
 
+const resetTokenTTL = time.Hour
+
 type ForgotPasswordRequest struct {
 	Email string `json:"email" binding:"required,email"`
 }
@@ -170,6 +188,12 @@ func ForgotPassword(c *gin.Context) {
 		return
 	}
 
+	if mailer.Current() == nil {
+		log.Println("Failed to send password reset: no mailer is configured, set MAIL_DRIVER")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Password reset is unavailable because email is not configured"})
+		return
+	}
+
 	var user models.User
 	if err := database.DB.Where("email = ?", req.Email).First(&user).Error; err != nil {
 
@@ -189,7 +213,7 @@ func ForgotPassword(c *gin.Context) {
 	resetToken := models.PasswordResetToken{
 		UserID:    user.ID,
 		Token:     token,
-		ExpiresAt: time.Now().Add(1 * time.Hour),
+		ExpiresAt: time.Now().Add(resetTokenTTL),
 		Used:      false,
 	}
 
@@ -210,10 +234,11 @@ func ForgotPassword(c *gin.Context) {
 		TargetType:  &targetType,
 	})
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Password reset token generated successfully",
-		"token":   token,
-	})
+	if err := services.SendPasswordResetEmail(&user, token, resetTokenTTL); err != nil {
+		log.Printf("Failed to email password reset to %s: %v", user.Email, err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "If an account exists, a password reset link has been sent"})
 }
 
 func ResetPassword(c *gin.Context) {
@@ -249,6 +274,10 @@ func ResetPassword(c *gin.Context) {
 
 	database.DB.Model(&resetToken).Update("used", true)
 
+	if err := services.RevokeUserTokens(user.ID); err != nil {
+		log.Printf("Failed to revoke tokens after password reset for %s: %v", user.Email, err)
+	}
+
 	userRole := string(user.Role)
 	targetType := "user"
 	services.LogActivity(services.LogActivityParams{
@@ -275,27 +304,24 @@ func RefreshToken(c *gin.Context) {
 		return
 	}
 
-	var refreshToken models.RefreshToken
-	if err := database.DB.Where("token = ? AND revoked_at IS NULL AND expires_at > ?",
-		req.RefreshToken, time.Now()).Preload("User").First(&refreshToken).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
-		return
-	}
-
-	user := refreshToken.User
-	if user == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "User not found"})
-		return
-	}
-
-	accessToken, err := utils.GenerateShortLivedJWT(user.ID.String(), user.Email, string(user.Role))
+	tokens, _, err := services.RotateRefreshToken(req.RefreshToken, clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate access token"})
+		switch {
+		case errors.Is(err, services.ErrRefreshTokenReused):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token was already used, please sign in again"})
+		case errors.Is(err, services.ErrInvalidRefreshToken):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh token"})
+		}
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"accessToken": accessToken,
+		"accessToken":      tokens.AccessToken,
+		"refreshToken":     tokens.RefreshToken,
+		"expiresIn":        tokens.ExpiresIn,
+		"refreshExpiresAt": tokens.RefreshExpiresAt,
 	})
 }
 
@@ -306,15 +332,7 @@ func RevokeRefreshToken(c *gin.Context) {
 		return
 	}
 
-	var refreshToken models.RefreshToken
-	if err := database.DB.Where("token = ?", req.RefreshToken).First(&refreshToken).Error; err != nil {
-
-		c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
-		return
-	}
-
-	now := time.Now()
-	if err := database.DB.Model(&refreshToken).Update("revoked_at", &now).Error; err != nil {
+	if err := services.RevokeRefreshToken(req.RefreshToken); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke token"})
 		return
 	}
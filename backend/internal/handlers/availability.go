@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/adzzatxperts/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func GetMyAvailability(c *gin.Context) {
+	uid, _ := uuid.Parse(c.GetString("userId"))
+
+	availability, err := services.GetAvailability(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch availability"})
+		return
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
+
+func UpdateMyAvailability(c *gin.Context) {
+	uid, _ := uuid.Parse(c.GetString("userId"))
+	saveAvailability(c, uid)
+}
+
+func GetUserAvailability(c *gin.Context) {
+	uid, ok := availabilityTarget(c)
+	if !ok {
+		return
+	}
+
+	availability, err := services.GetAvailability(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch availability"})
+		return
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
+
+func UpdateUserAvailability(c *gin.Context) {
+	uid, ok := availabilityTarget(c)
+	if !ok {
+		return
+	}
+	saveAvailability(c, uid)
+}
+
+func AddMyOutOfOffice(c *gin.Context) {
+	uid, _ := uuid.Parse(c.GetString("userId"))
+
+	var req struct {
+		StartsAt time.Time `json:"startsAt" binding:"required"`
+		EndsAt   time.Time `json:"endsAt" binding:"required"`
+		Reason   string    `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "startsAt and endsAt are required RFC 3339 timestamps"})
+		return
+	}
+
+	entry, err := services.AddOutOfOffice(uid, req.StartsAt, req.EndsAt, req.Reason)
+	if err != nil {
+		respondAvailabilityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Out of office added", "outOfOffice": entry})
+}
+
+func DeleteMyOutOfOffice(c *gin.Context) {
+	uid, _ := uuid.Parse(c.GetString("userId"))
+
+	entryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid out of office ID"})
+		return
+	}
+
+	if err := services.DeleteOutOfOffice(uid, entryID); err != nil {
+		respondAvailabilityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Out of office removed"})
+}
+
+func availabilityTarget(c *gin.Context) (uuid.UUID, bool) {
+	uid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return uuid.Nil, false
+	}
+
+	var user models.User
+	if err := database.DB.Select("id").First(&user, uid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return uuid.Nil, false
+	}
+	return uid, true
+}
+
+func saveAvailability(c *gin.Context, uid uuid.UUID) {
+	var req services.AvailabilityInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	availability, err := services.SaveAvailability(uid, req)
+	if err != nil {
+		respondAvailabilityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Availability updated", "availability": availability})
+}
+
+func respondAvailabilityError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidAvailability):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrOutOfOfficeNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Out of office entry not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update availability"})
+	}
+}
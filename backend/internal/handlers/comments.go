@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/adzzatxperts/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type commentRequest struct {
+	Body     string                  `json:"body" binding:"required"`
+	ParentID *string                 `json:"parentId,omitempty"`
+	Blocking *bool                   `json:"blocking,omitempty"`
+	Anchor   *services.CommentAnchor `json:"anchor,omitempty"`
+}
+
+type feedbackComment struct {
+	Body   string                 `json:"body"`
+	Anchor services.CommentAnchor `json:"anchor"`
+}
+
+func GetComments(c *gin.Context) {
+	submission, ok := loadRunSubmission(c)
+	if !ok {
+		return
+	}
+
+	threads, err := services.GetCommentThreads(submission.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		return
+	}
+	openBlocking, _ := services.CountOpenBlockingComments(submission.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments":     threads,
+		"total":        len(threads),
+		"openBlocking": openBlocking,
+	})
+}
+
+func CreateComment(c *gin.Context) {
+	submission, ok := loadRunSubmission(c)
+	if !ok {
+		return
+	}
+
+	var req commentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Comment body is required"})
+		return
+	}
+
+	userRole := c.GetString("userRole")
+	authorID, _ := uuid.Parse(c.GetString("userId"))
+	if !ensureAssignedToComment(c, submission, authorID, userRole) {
+		return
+	}
+
+	input := services.NewComment{
+		SubmissionID: submission.ID,
+		AuthorID:     &authorID,
+		AuthorRole:   userRole,
+		Body:         req.Body,
+		Anchor:       req.Anchor,
+	}
+
+	if req.ParentID != nil {
+		parentID, err := uuid.Parse(*req.ParentID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid parent comment ID"})
+			return
+		}
+		input.ParentID = &parentID
+	} else if userRole == string(models.RoleContributor) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Contributors can only reply to existing comments"})
+		return
+	} else {
+		input.Blocking = req.Blocking == nil || *req.Blocking
+	}
+
+	comment, err := services.CreateComment(input)
+	if err != nil {
+		respondCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Comment added successfully", "comment": comment})
+}
+
+func ResolveComment(c *gin.Context) {
+	submission, ok := loadRunSubmission(c)
+	if !ok {
+		return
+	}
+
+	commentID, err := uuid.Parse(c.Param("commentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	userRole := c.GetString("userRole")
+	if userRole == string(models.RoleContributor) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Contributors can only reply to comments"})
+		return
+	}
+	actorID, _ := uuid.Parse(c.GetString("userId"))
+	if !ensureAssignedToComment(c, submission, actorID, userRole) {
+		return
+	}
+
+	var req struct {
+		Resolved *bool `json:"resolved"`
+	}
+	c.ShouldBindJSON(&req)
+	resolved := req.Resolved == nil || *req.Resolved
+
+	comment, err := services.SetCommentResolved(submission, commentID, resolved, actorID, userRole)
+	if err != nil {
+		respondCommentError(c, err)
+		return
+	}
+
+	message := "Comment resolved successfully"
+	if !resolved {
+		message = "Comment reopened successfully"
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message, "comment": comment})
+}
+
+func ensureAssignedToComment(c *gin.Context, submission *models.ProjectVSubmission, userID uuid.UUID, userRole string) bool {
+	var assignee *uuid.UUID
+	switch models.UserRole(userRole) {
+	case models.RoleTester:
+		assignee = submission.TesterID
+	case models.RoleReviewer:
+		assignee = submission.ReviewerID
+	default:
+		return true
+	}
+	if assignee == nil || *assignee != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only comment on tasks assigned to you"})
+		return false
+	}
+	return true
+}
+
+func openFeedbackThreads(c *gin.Context, submissionID uuid.UUID, feedback string, anchored []services.NewComment) {
+	authorID, _ := uuid.Parse(c.GetString("userId"))
+	if _, err := services.OpenFeedbackThreads(submissionID, &authorID, c.GetString("userRole"), feedback, anchored); err != nil {
+		log.Printf("Failed to open feedback threads for submission %s: %v", submissionID, err)
+	}
+}
+
+func parseFeedbackComments(c *gin.Context, submissionID uuid.UUID, anchors []feedbackComment) ([]services.NewComment, bool) {
+	comments := make([]services.NewComment, 0, len(anchors))
+	for _, item := range anchors {
+		anchor := item.Anchor
+		if err := services.ValidateCommentAnchor(submissionID, &anchor); err != nil {
+			respondCommentError(c, err)
+			return nil, false
+		}
+		comments = append(comments, services.NewComment{Body: item.Body, Anchor: &anchor})
+	}
+	return comments, true
+}
+
+func respondCommentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrCommentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+	case errors.Is(err, services.ErrCommentForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the comment author, the assigned tester or reviewer, or an admin can resolve this thread"})
+	case errors.Is(err, services.ErrCommentEmpty),
+		errors.Is(err, services.ErrCommentInvalidAnchor),
+		errors.Is(err, services.ErrCommentReplyToReply):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save comment"})
+	}
+}
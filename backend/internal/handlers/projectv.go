@@ -1,16 +1,20 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"regexp"
 	"unicode"
 
 	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/githost"
 	"github.com/adzzatxperts/backend/internal/models"
 	"github.com/adzzatxperts/backend/internal/services"
 	"github.com/adzzatxperts/backend/internal/storage"
+	"github.com/adzzatxperts/backend/internal/workflow"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -57,6 +61,13 @@ func CreateProjectVSubmission(c *gin.Context) {
 		return
 	}
 
+	resolvedCommit, err := services.VerifySubmissionCommit(c.Request.Context(), githubRepo, commitHash, nil)
+	if err != nil {
+		respondCommitVerificationError(c, err, commitHash)
+		return
+	}
+	commitHash = resolvedCommit
+
 	testPatchFile, testPatchHeader, err := c.Request.FormFile("testPatch")
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Test patch file is required"})
@@ -131,15 +142,37 @@ func CreateProjectVSubmission(c *gin.Context) {
 	}
 
 	if err := database.DB.Create(&submission).Error; err != nil {
+		if errors.Is(services.DuplicateSubmissionError(err), services.ErrDuplicateSubmission) {
+			respondCommitVerificationError(c, services.ErrDuplicateSubmission, commitHash)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create submission"})
 		return
 	}
+	recordStatusChange(c, models.TransitionSubjectProjectV, submission.ID, "", string(models.ProjectVStatusSubmitted), "CREATE", "")
 
-	testerID, err := services.AutoAssignTester(submission.ID)
-	if err == nil && testerID != nil {
-		submission.TesterID = testerID
-		submission.Status = models.ProjectVStatusInTesting
-		database.DB.Save(&submission)
+	uploads := []struct {
+		artifact string
+		key      string
+		name     string
+		data     []byte
+	}{
+		{models.ArtifactTestPatch, testPatchURL, testPatchHeader.Filename, testPatchData},
+		{models.ArtifactDockerfile, dockerfileURL, dockerHeader.Filename, dockerData},
+		{models.ArtifactSolutionPatch, solutionPatchURL, solutionHeader.Filename, solutionPatchData},
+	}
+	for _, upload := range uploads {
+		if _, err := services.RecordRevision(submission.ID, upload.artifact, upload.key, upload.name, upload.data, &contributorID); err != nil {
+			log.Printf("Failed to record %s revision for submission %s: %v", upload.artifact, submission.ID, err)
+		}
+	}
+
+	if err := workflow.Apply(&submission, workflow.ActionStartTesting, workflow.SystemActor, workflow.Params{}); err != nil {
+		log.Printf("Submission %s left in %s: %v", submission.ID, submission.Status, err)
+	}
+
+	if _, err := services.EnqueueValidation(submission.ID, services.ValidationTriggerCreate, &contributorID); err != nil {
+		log.Printf("Failed to enqueue validation for submission %s: %v", submission.ID, err)
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
@@ -182,6 +215,8 @@ func GetProjectVSubmissions(c *gin.Context) {
 		}
 	}
 
+	services.AttachLatestValidationRuns(submissions)
+
 	if userRole == "CONTRIBUTOR" {
 		for i := range submissions {
 			submissions[i].AccountPostedIn = nil
@@ -231,26 +266,26 @@ func GetProjectVSubmission(c *gin.Context) {
 		submission.SolutionPatchURL = solutionPatchURL
 	}
 
+	if latestRuns, err := services.GetLatestValidationRuns([]uuid.UUID{submission.ID}); err == nil {
+		submission.LatestRun = latestRuns[submission.ID]
+		submission.ValidationStepResults = services.ValidationStepResultsFor(submission.LatestRun)
+	}
+
 	c.JSON(http.StatusOK, submission)
 }
 
 func UpdateProjectVStatus(c *gin.Context) {
-	userRole := c.GetString("userRole")
-	if userRole != "TESTER" && userRole != "ADMIN" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only testers and admins can update status"})
-		return
-	}
-
-	id := c.Param("id")
-	submissionID, err := uuid.Parse(id)
+	submissionID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
 		return
 	}
 
 	var req struct {
-		Status          string  `json:"status" binding:"required"`
-		AccountPostedIn *string `json:"accountPostedIn,omitempty"`
+		Status          string                     `json:"status" binding:"required"`
+		AccountPostedIn *string                    `json:"accountPostedIn,omitempty"`
+		Reason          string                     `json:"reason,omitempty"`
+		Rubric          *services.RubricSubmission `json:"rubric,omitempty"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -258,61 +293,39 @@ func UpdateProjectVStatus(c *gin.Context) {
 		return
 	}
 
-	if req.Status == string(models.ProjectVStatusApproved) && userRole != "ADMIN" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only admins can approve tasks"})
-		return
-	}
-
-	validStatuses := []string{
-		string(models.ProjectVStatusSubmitted),
-		string(models.ProjectVStatusInTesting),
-		string(models.ProjectVStatusPendingReview),
-		string(models.ProjectVStatusChangesRequested),
-		string(models.ProjectVStatusChangesDone),
-		string(models.ProjectVStatusFinalChecks),
-		string(models.ProjectVStatusApproved),
-		string(models.ProjectVStatusRejected),
-	}
-
-	isValid := false
-	for _, s := range validStatuses {
-		if s == req.Status {
-			isValid = true
-			break
-		}
-	}
-
-	if !isValid {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
-		return
-	}
-
 	var submission models.ProjectVSubmission
 	if err := database.DB.First(&submission, submissionID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
 		return
 	}
 
-	submission.Status = models.ProjectVStatus(req.Status)
-
-	if submission.TesterID == nil {
-		testerID, _ := uuid.Parse(c.GetString("userId"))
-		submission.TesterID = &testerID
-	}
-
-	if req.Status == string(models.ProjectVStatusPendingReview) && submission.ReviewerID == nil {
-		reviewerID, err := services.AutoAssignReviewer(submissionID)
-		if err == nil && reviewerID != nil {
-			submission.ReviewerID = reviewerID
-		}
+	action, ok := workflow.ActionFor(submission.Status, models.ProjectVStatus(req.Status))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":         "Invalid status transition",
+			"currentStatus": submission.Status,
+			"status":        req.Status,
+		})
+		return
 	}
 
+	params := workflow.Params{Reason: req.Reason}
 	if req.AccountPostedIn != nil {
+		params.AccountPostedIn = *req.AccountPostedIn
 		submission.AccountPostedIn = req.AccountPostedIn
 	}
+	if req.Rubric != nil {
+		reviewerID, _ := uuid.Parse(c.GetString("userId"))
+		evaluation, err := services.BuildRubricEvaluation(&submission, &reviewerID, req.Rubric)
+		if err != nil {
+			respondRubricError(c, err)
+			return
+		}
+		params.Rubric = evaluation
+	}
 
-	if err := database.DB.Save(&submission).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update status"})
+	if err := workflow.Apply(&submission, action, workflowActor(c), params); err != nil {
+		respondWorkflowError(c, err)
 		return
 	}
 
@@ -320,25 +333,10 @@ func UpdateProjectVStatus(c *gin.Context) {
 }
 
 func MarkChangesRequested(c *gin.Context) {
-	userRole := c.GetString("userRole")
-	if userRole != "REVIEWER" && userRole != "ADMIN" {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error":        "Only reviewers can request changes",
-			"receivedRole": userRole,
-			"expectedRole": "REVIEWER or ADMIN",
-		})
-		return
-	}
-
-	id := c.Param("id")
-	submissionID, err := uuid.Parse(id)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
-		return
-	}
-
 	var req struct {
-		Feedback string `json:"feedback" binding:"required"`
+		Feedback string                     `json:"feedback" binding:"required"`
+		Comments []feedbackComment          `json:"comments,omitempty"`
+		Rubric   *services.RubricSubmission `json:"rubric"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -346,85 +344,42 @@ func MarkChangesRequested(c *gin.Context) {
 		return
 	}
 
-	var submission models.ProjectVSubmission
-	if err := database.DB.First(&submission, submissionID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+	submissionID, _ := uuid.Parse(c.Param("id"))
+	anchored, ok := parseFeedbackComments(c, submissionID, req.Comments)
+	if !ok {
 		return
 	}
-
-	if submission.Status != models.ProjectVStatusEligible &&
-		submission.Status != models.ProjectVStatusPendingReview &&
-		submission.Status != models.ProjectVStatusChangesDone {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":         "Task is not in a reviewable state",
-			"currentStatus": submission.Status,
-			"allowedStates": "ELIGIBLE_FOR_MANUAL_REVIEW, PENDING_REVIEW, or CHANGES_DONE",
-		})
+	evaluation, ok := prepareRubricEvaluation(c, req.Rubric)
+	if !ok {
 		return
 	}
 
-	userID, _ := uuid.Parse(c.GetString("userId"))
-	if submission.ReviewerID == nil {
-		submission.ReviewerID = &userID
-	}
-
-	submission.Status = models.ProjectVStatusChangesRequested
-	submission.ReviewerFeedback = req.Feedback
-	submission.HasChangesRequested = true
-	submission.ChangesDone = false
-
-	if err := database.DB.Save(&submission).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update submission"})
+	submission, ok := applyProjectVTransition(c, workflow.ActionRequestChanges, workflow.Params{Feedback: req.Feedback, Rubric: evaluation})
+	if !ok {
 		return
 	}
+	openFeedbackThreads(c, submission.ID, req.Feedback, anchored)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Changes requested successfully", "submission": submission})
 }
 
 func MarkFinalChecks(c *gin.Context) {
-	userRole := c.GetString("userRole")
-	if userRole != "REVIEWER" && userRole != "ADMIN" {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error":        "Only reviewers can mark for final checks",
-			"receivedRole": userRole,
-			"expectedRole": "REVIEWER or ADMIN",
-		})
-		return
-	}
-
-	id := c.Param("id")
-	submissionID, err := uuid.Parse(id)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
-		return
+	var req struct {
+		Rubric *services.RubricSubmission `json:"rubric" binding:"required"`
 	}
 
-	var submission models.ProjectVSubmission
-	if err := database.DB.First(&submission, submissionID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A completed review rubric is required"})
 		return
 	}
 
-	if submission.Status != models.ProjectVStatusEligible &&
-		submission.Status != models.ProjectVStatusPendingReview &&
-		submission.Status != models.ProjectVStatusChangesDone {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":         "Task is not in a reviewable state",
-			"currentStatus": submission.Status,
-			"allowedStates": "ELIGIBLE_FOR_MANUAL_REVIEW, PENDING_REVIEW, or CHANGES_DONE",
-		})
+	evaluation, ok := prepareRubricEvaluation(c, req.Rubric)
+	if !ok {
 		return
 	}
 
-	userID, _ := uuid.Parse(c.GetString("userId"))
-	if submission.ReviewerID == nil {
-		submission.ReviewerID = &userID
-	}
-
-	submission.Status = models.ProjectVStatusFinalChecks
-
-	if err := database.DB.Save(&submission).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update submission"})
+	submission, ok := applyProjectVTransition(c, workflow.ActionFinalChecks, workflow.Params{Rubric: evaluation})
+	if !ok {
 		return
 	}
 
@@ -432,37 +387,8 @@ func MarkFinalChecks(c *gin.Context) {
 }
 
 func MarkChangesDone(c *gin.Context) {
-	userID := c.GetString("userId")
-	userRole := c.GetString("userRole")
-
-	id := c.Param("id")
-	submissionID, err := uuid.Parse(id)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
-		return
-	}
-
-	var submission models.ProjectVSubmission
-	if err := database.DB.First(&submission, submissionID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
-		return
-	}
-
-	if userRole != "ADMIN" && submission.ContributorID.String() != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to update this submission"})
-		return
-	}
-
-	if submission.Status != models.ProjectVStatusChangesRequested {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Task does not have changes requested"})
-		return
-	}
-
-	submission.Status = models.ProjectVStatusInTesting
-	submission.ChangesDone = true
-
-	if err := database.DB.Save(&submission).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update submission"})
+	submission, ok := applyProjectVTransition(c, workflow.ActionChangesDone, workflow.Params{})
+	if !ok {
 		return
 	}
 
@@ -491,9 +417,9 @@ func DeleteProjectVSubmission(c *gin.Context) {
 		return
 	}
 
-	storage.DeleteFile(submission.TestPatchURL)
-	storage.DeleteFile(submission.DockerfileURL)
-	storage.DeleteFile(submission.SolutionPatchURL)
+	services.CancelValidationJobsForSubmission(submission.ID, "Submission deleted")
+
+	services.DeleteSubmissionArtifacts(&submission)
 
 	if err := database.DB.Delete(&submission).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete submission"})
@@ -504,19 +430,6 @@ func DeleteProjectVSubmission(c *gin.Context) {
 }
 
 func MarkTaskSubmitted(c *gin.Context) {
-	userRole := c.GetString("userRole")
-	if userRole != "TESTER" && userRole != "ADMIN" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only testers can mark task as submitted"})
-		return
-	}
-
-	id := c.Param("id")
-	submissionID, err := uuid.Parse(id)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
-		return
-	}
-
 	var req struct {
 		SubmittedAccount  string `json:"submittedAccount" binding:"required"`
 		TaskLinkSubmitted string `json:"taskLinkSubmitted" binding:"required"`
@@ -527,23 +440,11 @@ func MarkTaskSubmitted(c *gin.Context) {
 		return
 	}
 
-	var submission models.ProjectVSubmission
-	if err := database.DB.First(&submission, submissionID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
-		return
-	}
-
-	userID, _ := uuid.Parse(c.GetString("userId"))
-	if submission.TesterID == nil {
-		submission.TesterID = &userID
-	}
-
-	submission.Status = models.ProjectVStatusTaskSubmittedToPlatform
-	submission.SubmittedAccount = &req.SubmittedAccount
-	submission.TaskLinkSubmitted = &req.TaskLinkSubmitted
-
-	if err := database.DB.Save(&submission).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update submission"})
+	submission, ok := applyProjectVTransition(c, workflow.ActionSubmitToPlatform, workflow.Params{
+		SubmittedAccount:  req.SubmittedAccount,
+		TaskLinkSubmitted: req.TaskLinkSubmitted,
+	})
+	if !ok {
 		return
 	}
 
@@ -551,19 +452,6 @@ func MarkTaskSubmitted(c *gin.Context) {
 }
 
 func MarkEligibleForManualReview(c *gin.Context) {
-	userRole := c.GetString("userRole")
-	if userRole != "TESTER" && userRole != "ADMIN" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only testers can mark task as eligible"})
-		return
-	}
-
-	id := c.Param("id")
-	submissionID, err := uuid.Parse(id)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
-		return
-	}
-
 	var req struct {
 		TaskLink string `json:"taskLink" binding:"required"`
 	}
@@ -573,29 +461,8 @@ func MarkEligibleForManualReview(c *gin.Context) {
 		return
 	}
 
-	var submission models.ProjectVSubmission
-	if err := database.DB.First(&submission, submissionID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
-		return
-	}
-
-	userID, _ := uuid.Parse(c.GetString("userId"))
-	if submission.TesterID == nil {
-		submission.TesterID = &userID
-	}
-
-	submission.Status = models.ProjectVStatusEligible
-	submission.TaskLink = &req.TaskLink
-
-	if submission.ReviewerID == nil {
-		reviewerID, err := services.AutoAssignReviewer(submissionID)
-		if err == nil && reviewerID != nil {
-			submission.ReviewerID = reviewerID
-		}
-	}
-
-	if err := database.DB.Save(&submission).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update submission"})
+	submission, ok := applyProjectVTransition(c, workflow.ActionMarkEligible, workflow.Params{TaskLink: req.TaskLink})
+	if !ok {
 		return
 	}
 
@@ -603,21 +470,9 @@ func MarkEligibleForManualReview(c *gin.Context) {
 }
 
 func SendTesterFeedback(c *gin.Context) {
-	userRole := c.GetString("userRole")
-	if userRole != "TESTER" && userRole != "ADMIN" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only testers can send feedback"})
-		return
-	}
-
-	id := c.Param("id")
-	submissionID, err := uuid.Parse(id)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
-		return
-	}
-
 	var req struct {
-		Feedback string `json:"feedback" binding:"required"`
+		Feedback string            `json:"feedback" binding:"required"`
+		Comments []feedbackComment `json:"comments,omitempty"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -625,48 +480,25 @@ func SendTesterFeedback(c *gin.Context) {
 		return
 	}
 
-	var submission models.ProjectVSubmission
-	if err := database.DB.First(&submission, submissionID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+	submissionID, _ := uuid.Parse(c.Param("id"))
+	anchored, ok := parseFeedbackComments(c, submissionID, req.Comments)
+	if !ok {
 		return
 	}
 
-	userID, _ := uuid.Parse(c.GetString("userId"))
-	if submission.TesterID == nil {
-		submission.TesterID = &userID
-	}
-
-	submission.Status = models.ProjectVStatusRework
-	submission.TesterFeedback = req.Feedback
-
-	if err := database.DB.Save(&submission).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update submission"})
+	submission, ok := applyProjectVTransition(c, workflow.ActionSendFeedback, workflow.Params{Feedback: req.Feedback})
+	if !ok {
 		return
 	}
+	openFeedbackThreads(c, submission.ID, req.Feedback, anchored)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Feedback sent successfully", "submission": submission})
 }
 
 func MarkRejected(c *gin.Context) {
-	userRole := c.GetString("userRole")
-	if userRole != "REVIEWER" && userRole != "ADMIN" {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error":        "Only reviewers can reject tasks",
-			"receivedRole": userRole,
-			"expectedRole": "REVIEWER or ADMIN",
-		})
-		return
-	}
-
-	id := c.Param("id")
-	submissionID, err := uuid.Parse(id)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
-		return
-	}
-
 	var req struct {
-		RejectionReason string `json:"rejectionReason" binding:"required"`
+		RejectionReason string                     `json:"rejectionReason" binding:"required"`
+		Rubric          *services.RubricSubmission `json:"rubric"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -674,27 +506,13 @@ func MarkRejected(c *gin.Context) {
 		return
 	}
 
-	var submission models.ProjectVSubmission
-	if err := database.DB.First(&submission, submissionID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+	evaluation, ok := prepareRubricEvaluation(c, req.Rubric)
+	if !ok {
 		return
 	}
 
-	if submission.Status != models.ProjectVStatusEligible && submission.Status != models.ProjectVStatusPendingReview && submission.Status != models.ProjectVStatusChangesDone {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Task is not in a reviewable state"})
-		return
-	}
-
-	userID, _ := uuid.Parse(c.GetString("userId"))
-	if submission.ReviewerID == nil {
-		submission.ReviewerID = &userID
-	}
-
-	submission.Status = models.ProjectVStatusRejected
-	submission.RejectionReason = &req.RejectionReason
-
-	if err := database.DB.Save(&submission).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update submission"})
+	submission, ok := applyProjectVTransition(c, workflow.ActionReject, workflow.Params{RejectionReason: req.RejectionReason, Rubric: evaluation})
+	if !ok {
 		return
 	}
 
@@ -703,7 +521,6 @@ func MarkRejected(c *gin.Context) {
 
 func ResubmitProjectVSubmission(c *gin.Context) {
 	userID := c.GetString("userId")
-	userRole := c.GetString("userRole")
 
 	id := c.Param("id")
 	submissionID, err := uuid.Parse(id)
@@ -718,15 +535,18 @@ func ResubmitProjectVSubmission(c *gin.Context) {
 		return
 	}
 
-	if userRole != "ADMIN" && submission.ContributorID.String() != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to update this submission"})
-		return
+	action := workflow.ActionResubmit
+	if submission.Status == models.ProjectVStatusChangesRequested {
+		action = workflow.ActionChangesDone
 	}
-
 	if submission.Status != models.ProjectVStatusRework && submission.Status != models.ProjectVStatusChangesRequested {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Task does not have feedback to address"})
 		return
 	}
+	if _, err := workflow.Check(&submission, action, workflowActor(c), workflow.Params{}); err != nil {
+		respondWorkflowError(c, err)
+		return
+	}
 
 	if err := c.Request.ParseMultipartForm(50 << 20); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse form data"})
@@ -773,62 +593,68 @@ func ResubmitProjectVSubmission(c *gin.Context) {
 	if commitHash != "" {
 		submission.CommitHash = commitHash
 	}
+	if githubRepo != "" || commitHash != "" {
+		resolvedCommit, err := services.VerifySubmissionCommit(c.Request.Context(), submission.GithubRepo, submission.CommitHash, &submission.ID)
+		if err != nil {
+			respondCommitVerificationError(c, err, submission.CommitHash)
+			return
+		}
+		submission.CommitHash = resolvedCommit
+	}
 	if issueURL != "" {
 		submission.IssueURL = issueURL
 	}
 
-	testPatchFile, testPatchHeader, err := c.Request.FormFile("testPatch")
-	if err == nil {
-		defer testPatchFile.Close()
-		testPatchData, err := io.ReadAll(testPatchFile)
-		if err == nil {
-
-			storage.DeleteFile(submission.TestPatchURL)
-			testPatchURL, err := storage.UploadFile(testPatchData, testPatchHeader.Filename, "text/plain")
-			if err == nil {
-				submission.TestPatchURL = testPatchURL
-			}
+	requesterID, _ := uuid.Parse(userID)
+	artifacts := []struct {
+		field    string
+		artifact string
+		column   string
+		target   *string
+	}{
+		{"testPatch", models.ArtifactTestPatch, "test_patch_url", &submission.TestPatchURL},
+		{"dockerfile", models.ArtifactDockerfile, "dockerfile_url", &submission.DockerfileURL},
+		{"solutionPatch", models.ArtifactSolutionPatch, "solution_patch_url", &submission.SolutionPatchURL},
+	}
+	params := workflow.Params{
+		Fields: []string{"title", "language", "category", "difficulty", "description", "github_repo", "commit_hash", "issue_url"},
+	}
+	for _, artifact := range artifacts {
+		file, header, err := c.Request.FormFile(artifact.field)
+		if err != nil {
+			continue
 		}
-	}
-
-	dockerFile, dockerHeader, err := c.Request.FormFile("dockerfile")
-	if err == nil {
-		defer dockerFile.Close()
-		dockerData, err := io.ReadAll(dockerFile)
-		if err == nil {
-
-			storage.DeleteFile(submission.DockerfileURL)
-			dockerfileURL, err := storage.UploadFile(dockerData, dockerHeader.Filename, "text/plain")
-			if err == nil {
-				submission.DockerfileURL = dockerfileURL
-			}
+		data, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			services.DiscardStagedRevisions(params.Revisions)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to read %s", artifact.field)})
+			return
 		}
-	}
 
-	solutionPatchFile, solutionHeader, err := c.Request.FormFile("solutionPatch")
-	if err == nil {
-		defer solutionPatchFile.Close()
-		solutionPatchData, err := io.ReadAll(solutionPatchFile)
-		if err == nil {
-
-			storage.DeleteFile(submission.SolutionPatchURL)
-			solutionPatchURL, err := storage.UploadFile(solutionPatchData, solutionHeader.Filename, "text/plain")
-			if err == nil {
-				submission.SolutionPatchURL = solutionPatchURL
-			}
+		revision, err := services.StageRevision(artifact.artifact, header.Filename, data, &requesterID)
+		if err != nil {
+			services.DiscardStagedRevisions(params.Revisions)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to upload %s: %v", artifact.field, err)})
+			return
 		}
+		*artifact.target = revision.StorageKey
+		params.Fields = append(params.Fields, artifact.column)
+		params.Revisions = append(params.Revisions, revision)
 	}
 
-	if submission.Status == models.ProjectVStatusRework {
-		submission.Status = models.ProjectVStatusReworkDone
-	} else if submission.Status == models.ProjectVStatusChangesRequested {
-		submission.Status = models.ProjectVStatusInTesting
-		submission.ChangesDone = true
+	if err := workflow.Apply(&submission, action, workflowActor(c), params); err != nil {
+		services.DiscardStagedRevisions(params.Revisions)
+		if errors.Is(services.DuplicateSubmissionError(err), services.ErrDuplicateSubmission) {
+			respondCommitVerificationError(c, services.ErrDuplicateSubmission, submission.CommitHash)
+			return
+		}
+		respondWorkflowError(c, err)
+		return
 	}
 
-	if err := database.DB.Save(&submission).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update submission"})
-		return
+	if _, err := services.EnqueueValidation(submission.ID, services.ValidationTriggerResubmit, &requesterID); err != nil {
+		log.Printf("Failed to enqueue validation for submission %s: %v", submission.ID, err)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -846,6 +672,23 @@ func isASCII(s string) bool {
 	return true
 }
 
+func respondCommitVerificationError(c *gin.Context, err error, commitHash string) {
+	switch {
+	case errors.Is(err, githost.ErrRepoNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Repository not found or not accessible"})
+	case errors.Is(err, githost.ErrCommitNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Commit %s not found in repository", commitHash)})
+	case errors.Is(err, githost.ErrAmbiguousCommit):
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Commit hash %s is ambiguous, please use the full SHA", commitHash)})
+	case errors.Is(err, services.ErrDuplicateSubmission):
+		c.JSON(http.StatusConflict, gin.H{"error": "This repository and commit have already been submitted"})
+	case errors.Is(err, githost.ErrHostUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Unable to verify the repository right now, please try again later"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify repository and commit"})
+	}
+}
+
 func isValidGitHubURL(url string) bool {
 	pattern := `^https?://github\.com/[\w-]+/[\w.-]+/?$`
 	matched, _ := regexp.MatchString(pattern, url)
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/adzzatxperts/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func GetRepoCache(c *gin.Context) {
+	cache := services.GetRepoCache()
+	if cache == nil {
+		c.JSON(http.StatusOK, gin.H{"enabled": false, "entries": []services.RepoCacheEntry{}})
+		return
+	}
+
+	entries, err := cache.Entries()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read repository cache"})
+		return
+	}
+
+	var totalBytes int64
+	for _, entry := range entries {
+		totalBytes += entry.SizeBytes
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"enabled":     true,
+		"entries":     entries,
+		"total":       len(entries),
+		"totalBytes":  totalBytes,
+		"budgetBytes": cache.BudgetBytes(),
+	})
+}
+
+func PurgeRepoCache(c *gin.Context) {
+	cache := services.GetRepoCache()
+	if cache == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Repository cache is disabled"})
+		return
+	}
+
+	purged, skipped := cache.PurgeAll()
+	logRepoCacheActivity(c, "Admin purged the repository cache", map[string]interface{}{
+		"purged":  purged,
+		"skipped": skipped,
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Repository cache purged",
+		"purged":  purged,
+		"skipped": skipped,
+	})
+}
+
+func PurgeRepoCacheEntry(c *gin.Context) {
+	cache := services.GetRepoCache()
+	if cache == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Repository cache is disabled"})
+		return
+	}
+
+	key := c.Param("key")
+	if err := cache.Purge(key); err != nil {
+		switch {
+		case errors.Is(err, services.ErrRepoCacheEntryNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Cache entry not found"})
+		case errors.Is(err, services.ErrRepoCacheEntryInUse):
+			c.JSON(http.StatusConflict, gin.H{"error": "Cache entry is in use by a running validation"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to purge cache entry"})
+		}
+		return
+	}
+
+	logRepoCacheActivity(c, "Admin purged repository cache entry "+key, map[string]interface{}{
+		"key": key,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Cache entry purged"})
+}
+
+func logRepoCacheActivity(c *gin.Context, description string, metadata map[string]interface{}) {
+	uid, _ := uuid.Parse(c.GetString("userId"))
+	userName := c.GetString("userEmail")
+	userRole := c.GetString("userRole")
+
+	services.LogActivity(services.LogActivityParams{
+		Action:      "PURGE_REPO_CACHE",
+		Description: description,
+		UserID:      &uid,
+		UserName:    &userName,
+		UserRole:    &userRole,
+		Metadata:    metadata,
+	})
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/adzzatxperts/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func GetSubmissionRevisions(c *gin.Context) {
+	submission, ok := loadRunSubmission(c)
+	if !ok {
+		return
+	}
+
+	revisions, err := services.GetRevisions(submission.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch revisions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"revisions": revisions,
+		"total":     len(revisions),
+	})
+}
+
+func GetRevisionDiff(c *gin.Context) {
+	submission, ok := loadRunSubmission(c)
+	if !ok {
+		return
+	}
+
+	artifact := c.Query("artifact")
+	if !services.IsArtifact(artifact) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "artifact must be one of test_patch, dockerfile, solution_patch"})
+		return
+	}
+
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil || from < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from version"})
+		return
+	}
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil || to < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to version"})
+		return
+	}
+
+	result, err := services.DiffRevisions(submission.ID, artifact, from, to)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrRevisionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		case errors.Is(err, services.ErrRevisionTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute diff"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/adzzatxperts/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func GetRubrics(c *gin.Context) {
+	rubrics, err := services.ListRubrics(c.Query("includeInactive") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch rubrics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rubrics": rubrics})
+}
+
+func CreateRubric(c *gin.Context) {
+	var req services.RubricInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	createdBy, _ := uuid.Parse(c.GetString("userId"))
+	rubric, err := services.CreateRubric(req, &createdBy)
+	if err != nil {
+		respondRubricError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Rubric created successfully", "rubric": rubric})
+}
+
+func UpdateRubric(c *gin.Context) {
+	rubricID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rubric ID"})
+		return
+	}
+
+	var req services.RubricInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	rubric, err := services.UpdateRubric(rubricID, req)
+	if err != nil {
+		respondRubricError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Rubric updated successfully", "rubric": rubric})
+}
+
+func DeleteRubric(c *gin.Context) {
+	rubricID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rubric ID"})
+		return
+	}
+
+	if err := services.DeleteRubric(rubricID); err != nil {
+		respondRubricError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Rubric deleted successfully"})
+}
+
+func GetSubmissionRubric(c *gin.Context) {
+	submission, ok := loadRunSubmission(c)
+	if !ok {
+		return
+	}
+
+	evaluations, err := services.GetRubricEvaluations(submission.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch rubric scores"})
+		return
+	}
+
+	response := gin.H{"evaluations": evaluations}
+	if rubric, err := services.ResolveRubric(submission.Category, submission.Difficulty); err == nil {
+		response["rubric"] = rubric
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func prepareRubricEvaluation(c *gin.Context, input *services.RubricSubmission) (*models.RubricEvaluation, bool) {
+	submissionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+		return nil, false
+	}
+
+	var submission models.ProjectVSubmission
+	if err := database.DB.First(&submission, submissionID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+		return nil, false
+	}
+
+	reviewerID, _ := uuid.Parse(c.GetString("userId"))
+	evaluation, err := services.BuildRubricEvaluation(&submission, &reviewerID, input)
+	if err != nil {
+		respondRubricError(c, err)
+		return nil, false
+	}
+	return evaluation, true
+}
+
+func respondRubricError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrRubricNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rubric not found"})
+	case errors.Is(err, services.ErrRubricNotConfigured):
+		c.JSON(http.StatusConflict, gin.H{"error": "No active rubric is configured for this task, ask an admin to add one"})
+	case errors.Is(err, services.ErrRubricRequired),
+		errors.Is(err, services.ErrRubricMismatch),
+		errors.Is(err, services.ErrRubricIncomplete),
+		errors.Is(err, services.ErrRubricInvalidScore),
+		errors.Is(err, services.ErrRubricInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process rubric"})
+	}
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/adzzatxperts/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func GetMySessions(c *gin.Context) {
+	uid, _ := uuid.Parse(c.GetString("userId"))
+
+	sessions, err := services.ListUserSessions(uid, c.GetString("sessionId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+func RevokeMySession(c *gin.Context) {
+	uid, _ := uuid.Parse(c.GetString("userId"))
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	if err := services.RevokeSession(uid, sessionID); err != nil {
+		respondSessionError(c, err)
+		return
+	}
+
+	logSessionChange(c, "REVOKE_SESSION", "Signed out of a session", uid, &sessionID)
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked"})
+}
+
+func GetUserSessions(c *gin.Context) {
+	user, ok := sessionTarget(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := services.ListUserSessions(user.ID, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+func TerminateUserSession(c *gin.Context) {
+	user, ok := sessionTarget(c)
+	if !ok {
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("sessionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	if err := services.RevokeSession(user.ID, sessionID); err != nil {
+		respondSessionError(c, err)
+		return
+	}
+
+	logSessionChange(c, "TERMINATE_SESSION", "Admin terminated a session of "+user.Name, user.ID, &sessionID)
+	c.JSON(http.StatusOK, gin.H{"message": "Session terminated"})
+}
+
+func TerminateUserSessions(c *gin.Context) {
+	user, ok := sessionTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := services.RevokeUserTokens(user.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to terminate sessions"})
+		return
+	}
+
+	logSessionChange(c, "TERMINATE_ALL_SESSIONS", "Admin terminated all sessions of "+user.Name, user.ID, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "All sessions terminated"})
+}
+
+func sessionTarget(c *gin.Context) (*models.User, bool) {
+	uid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return nil, false
+	}
+
+	var user models.User
+	if err := database.DB.Select("id", "name", "email").First(&user, uid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return nil, false
+	}
+	return &user, true
+}
+
+func respondSessionError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrSessionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session"})
+}
+
+func logSessionChange(c *gin.Context, action, description string, targetID uuid.UUID, sessionID *uuid.UUID) {
+	uid, _ := uuid.Parse(c.GetString("userId"))
+	userName := c.GetString("userEmail")
+	userRole := c.GetString("userRole")
+	targetType := "user"
+
+	metadata := map[string]interface{}{}
+	if sessionID != nil {
+		metadata["sessionId"] = sessionID.String()
+	}
+	services.LogActivity(services.LogActivityParams{
+		Action:      action,
+		Description: description,
+		UserID:      &uid,
+		UserName:    &userName,
+		UserRole:    &userRole,
+		TargetID:    &targetID,
+		TargetType:  &targetType,
+		Metadata:    metadata,
+	})
+}
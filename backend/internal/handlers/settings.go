@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/adzzatxperts/backend/internal/assignment"
+	"github.com/adzzatxperts/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func GetAssignmentSettings(c *gin.Context) {
+	settings, err := services.GetAssignmentSettings()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch assignment settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"settings":   settings,
+		"strategies": assignment.Available(),
+	})
+}
+
+func UpdateAssignmentSetting(c *gin.Context) {
+	pipeline := c.Param("pipeline")
+
+	var req struct {
+		Strategy string          `json:"strategy" binding:"required"`
+		Params   json.RawMessage `json:"params"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Strategy is required"})
+		return
+	}
+
+	uid, _ := uuid.Parse(c.GetString("userId"))
+	setting, err := services.UpdateAssignmentSetting(pipeline, req.Strategy, req.Params, &uid)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownPipeline):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Unknown pipeline"})
+		case errors.Is(err, assignment.ErrUnknownStrategy), errors.Is(err, assignment.ErrInvalidParams):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update assignment setting"})
+		}
+		return
+	}
+
+	userName := c.GetString("userEmail")
+	userRole := c.GetString("userRole")
+	services.LogActivity(services.LogActivityParams{
+		Action:      "UPDATE_ASSIGNMENT_STRATEGY",
+		Description: "Admin set " + pipeline + " assignment strategy to " + req.Strategy,
+		UserID:      &uid,
+		UserName:    &userName,
+		UserRole:    &userRole,
+		Metadata: map[string]interface{}{
+			"pipeline": pipeline,
+			"strategy": req.Strategy,
+			"params":   setting.Params,
+		},
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Assignment setting updated", "setting": setting})
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/adzzatxperts/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func GetSLAPolicies(c *gin.Context) {
+	policies, err := services.ListSLAPolicies()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch SLA policies"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"policies": policies})
+}
+
+func CreateSLAPolicy(c *gin.Context) {
+	var req services.SLAPolicyInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	uid, _ := uuid.Parse(c.GetString("userId"))
+	policy, err := services.CreateSLAPolicy(req, &uid)
+	if err != nil {
+		respondSLAError(c, err)
+		return
+	}
+
+	logSLAPolicyChange(c, "CREATE_SLA_POLICY", "Admin created SLA policy \""+policy.Name+"\"", policy)
+	c.JSON(http.StatusCreated, gin.H{"message": "SLA policy created", "policy": policy})
+}
+
+func UpdateSLAPolicy(c *gin.Context) {
+	policyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SLA policy ID"})
+		return
+	}
+
+	var req services.SLAPolicyInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	policy, err := services.UpdateSLAPolicy(policyID, req)
+	if err != nil {
+		respondSLAError(c, err)
+		return
+	}
+
+	logSLAPolicyChange(c, "UPDATE_SLA_POLICY", "Admin updated SLA policy \""+policy.Name+"\"", policy)
+	c.JSON(http.StatusOK, gin.H{"message": "SLA policy updated", "policy": policy})
+}
+
+func DeleteSLAPolicy(c *gin.Context) {
+	policyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SLA policy ID"})
+		return
+	}
+
+	policy, err := services.DeleteSLAPolicy(policyID)
+	if err != nil {
+		respondSLAError(c, err)
+		return
+	}
+
+	logSLAPolicyChange(c, "DELETE_SLA_POLICY", "Admin deleted SLA policy \""+policy.Name+"\"", policy)
+	c.JSON(http.StatusOK, gin.H{"message": "SLA policy deleted"})
+}
+
+func GetSLABreaches(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit < 1 || limit > 500 {
+		limit = 100
+	}
+	openOnly := c.DefaultQuery("state", "open") != "all"
+
+	breaches, err := services.ListSLABreaches(openOnly, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch SLA breaches"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"breaches": breaches})
+}
+
+func respondSLAError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrSLAPolicyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "SLA policy not found"})
+	case errors.Is(err, services.ErrSLAPolicyExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidSLAPolicy):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save SLA policy"})
+	}
+}
+
+func logSLAPolicyChange(c *gin.Context, action, description string, policy *models.SLAPolicy) {
+	uid, _ := uuid.Parse(c.GetString("userId"))
+	userName := c.GetString("userEmail")
+	userRole := c.GetString("userRole")
+	services.LogActivity(services.LogActivityParams{
+		Action:      action,
+		Description: description,
+		UserID:      &uid,
+		UserName:    &userName,
+		UserRole:    &userRole,
+		Metadata: map[string]interface{}{
+			"policyId":             policy.ID.String(),
+			"pipeline":             policy.Pipeline,
+			"status":               policy.Status,
+			"thresholdMinutes":     policy.ThresholdMinutes,
+			"escalateAfterMinutes": policy.EscalateAfterMinutes,
+			"reassign":             policy.Reassign,
+			"active":               policy.Active,
+		},
+	})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"io"
 	"net/http"
 	"strings"
@@ -71,6 +72,8 @@ func UploadSubmission(c *gin.Context) {
 		return
 	}
 
+	recordStatusChange(c, models.TransitionSubjectSubmission, submission.ID, "", string(models.StatusPending), "UPLOAD", "")
+
 	userName, _ := c.Get("userEmail")
 	userRoleStr := userRole.(string)
 	targetType := "submission"
@@ -363,15 +366,20 @@ func SubmitFeedback(c *gin.Context) {
 		return
 	}
 
-	if req.MarkAsEligible {
-		database.DB.Model(&models.Submission{}).
-			Where("id = ?", sid).
-			Update("status", models.StatusEligible)
-	}
-
 	var submission models.Submission
 	database.DB.Preload("Contributor").First(&submission, sid)
 
+	if req.MarkAsEligible && submission.Status != models.StatusEligible {
+		previous := submission.Status
+		err := database.DB.Model(&models.Submission{}).
+			Where("id = ?", sid).
+			Update("status", models.StatusEligible).Error
+		if err == nil {
+			submission.Status = models.StatusEligible
+			recordStatusChange(c, models.TransitionSubjectSubmission, sid, string(previous), string(models.StatusEligible), "REVIEW", req.Feedback)
+		}
+	}
+
 	userName, _ := c.Get("userEmail")
 	userRoleStr := userRole.(string)
 	userNameStr := userName.(string)
@@ -421,6 +429,7 @@ func ApproveSubmission(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to approve submission"})
 		return
 	}
+	recordStatusChange(c, models.TransitionSubjectSubmission, sid, string(models.StatusEligible), string(models.StatusApproved), "APPROVE", "")
 
 	userID, _ := c.Get("userId")
 	userName, _ := c.Get("userEmail")
@@ -464,23 +473,25 @@ func ClaimSubmission(c *gin.Context) {
 
 	uid, _ := uuid.Parse(userID.(string))
 
-	var submission models.Submission
-	if err := database.DB.Preload("Contributor").First(&submission, sid).Error; err != nil {
+	submission, err := services.ClaimSubmission(sid, services.StatusChange{
+		Action:    "MANUAL_CLAIM",
+		ActorID:   &uid,
+		ActorName: c.GetString("userEmail"),
+		ActorRole: c.GetString("userRole"),
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
 		return
 	}
-
-	if submission.Status != models.StatusPending && submission.Status != models.StatusClaimed {
+	if errors.Is(err, services.ErrSubmissionNotClaimable) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Can only claim pending or claimed tasks"})
 		return
 	}
-
-	submission.ClaimedByID = &uid
-	submission.Status = models.StatusClaimed
-	if err := database.DB.Save(&submission).Error; err != nil {
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to claim submission"})
 		return
 	}
+	database.DB.Select("name").First(&submission.Contributor, submission.ContributorID)
 
 	userName, _ := c.Get("userEmail")
 	userNameStr := userName.(string)
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/adzzatxperts/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func GetProjectVTimeline(c *gin.Context) {
+	submission, ok := loadRunSubmission(c)
+	if !ok {
+		return
+	}
+
+	timeline, err := services.GetStatusTimeline(models.TransitionSubjectProjectV, submission.ID, string(submission.Status))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch status timeline"})
+		return
+	}
+
+	c.JSON(http.StatusOK, timeline)
+}
+
+func GetSubmissionTimeline(c *gin.Context) {
+	sid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+		return
+	}
+
+	var submission models.Submission
+	if err := database.DB.First(&submission, sid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+		return
+	}
+
+	if c.GetString("userRole") == string(models.RoleContributor) && submission.ContributorID.String() != c.GetString("userId") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to view this submission"})
+		return
+	}
+
+	timeline, err := services.GetStatusTimeline(models.TransitionSubjectSubmission, submission.ID, string(submission.Status))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch status timeline"})
+		return
+	}
+
+	c.JSON(http.StatusOK, timeline)
+}
+
+func recordStatusChange(c *gin.Context, subjectType string, subjectID uuid.UUID, from, to, action, reason string) {
+	actorID, _ := uuid.Parse(c.GetString("userId"))
+	services.RecordStatusTransition(services.StatusChange{
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		From:        from,
+		To:          to,
+		Action:      action,
+		ActorID:     &actorID,
+		ActorName:   c.GetString("userEmail"),
+		ActorRole:   c.GetString("userRole"),
+		Reason:      reason,
+	})
+
+	if err := services.SendStatusChangedEmail(subjectType, subjectID, from, to, reason, &actorID); err != nil {
+		log.Printf("Failed to email status change for %s: %v", subjectID, err)
+	}
+}
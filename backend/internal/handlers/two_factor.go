@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/adzzatxperts/backend/internal/services"
+	"github.com/adzzatxperts/backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorChallengeRequest struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+	Code           string `json:"code"`
+}
+
+func VerifyTwoFactorSignin(c *gin.Context) {
+	var req TwoFactorChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "challengeToken and code are required"})
+		return
+	}
+
+	user, usedRecovery, err := services.CompleteTwoFactorSignin(req.ChallengeToken, req.Code)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	if usedRecovery {
+		logTwoFactorChange(user, "RECOVERY_CODE_USED", user.Name+" signed in with a recovery code", &user.ID)
+	}
+
+	tokens, err := services.IssueTokenPair(user, clientInfo(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, authResponse(user, tokens))
+}
+
+func BeginTwoFactorEnrollment(c *gin.Context) {
+	var req TwoFactorChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "challengeToken is required"})
+		return
+	}
+
+	setup, err := services.BeginChallengeSetup(req.ChallengeToken)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, setup)
+}
+
+func ConfirmTwoFactorEnrollment(c *gin.Context) {
+	var req TwoFactorChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "challengeToken and code are required"})
+		return
+	}
+
+	user, codes, err := services.CompleteChallengeSetup(req.ChallengeToken, req.Code)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+	logTwoFactorChange(user, "ENABLE_2FA", user.Name+" enabled two-factor authentication", &user.ID)
+
+	tokens, err := services.IssueTokenPair(user, clientInfo(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	response := authResponse(user, tokens)
+	response["recoveryCodes"] = codes
+	c.JSON(http.StatusOK, response)
+}
+
+func GetMyTwoFactor(c *gin.Context) {
+	user, ok := currentTwoFactorUser(c)
+	if !ok {
+		return
+	}
+
+	status, err := services.GetTwoFactorStatus(user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch two-factor status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+func SetupMyTwoFactor(c *gin.Context) {
+	user, ok := currentTwoFactorUser(c)
+	if !ok {
+		return
+	}
+
+	setup, err := services.BeginTwoFactorSetup(user)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, setup)
+}
+
+func EnableMyTwoFactor(c *gin.Context) {
+	user, ok := currentTwoFactorUser(c)
+	if !ok {
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code is required"})
+		return
+	}
+
+	codes, err := services.EnableTwoFactor(user.ID, req.Code)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+	logTwoFactorChange(user, "ENABLE_2FA", user.Name+" enabled two-factor authentication", &user.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Two-factor authentication enabled",
+		"recoveryCodes": codes,
+	})
+}
+
+func DisableMyTwoFactor(c *gin.Context) {
+	user, ok := currentTwoFactorUser(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Password string `json:"password" binding:"required"`
+		Code     string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "password and code are required"})
+		return
+	}
+
+	if !utils.CheckPassword(req.Password, user.PasswordHash) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid password"})
+		return
+	}
+
+	if err := services.DisableTwoFactor(user, req.Code); err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+	logTwoFactorChange(user, "DISABLE_2FA", user.Name+" disabled two-factor authentication", &user.ID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+func RegenerateMyRecoveryCodes(c *gin.Context) {
+	user, ok := currentTwoFactorUser(c)
+	if !ok {
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code is required"})
+		return
+	}
+
+	codes, err := services.RegenerateRecoveryCodes(user.ID, req.Code)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+	logTwoFactorChange(user, "REGENERATE_RECOVERY_CODES", user.Name+" generated new recovery codes", &user.ID)
+
+	c.JSON(http.StatusOK, gin.H{"recoveryCodes": codes})
+}
+
+func ResetUserTwoFactor(c *gin.Context) {
+	uid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, uid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if err := services.ResetTwoFactor(uid); err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	adminID, _ := uuid.Parse(c.GetString("userId"))
+	admin := models.User{ID: adminID, Name: c.GetString("userEmail"), Role: models.UserRole(c.GetString("userRole"))}
+	logTwoFactorChange(&admin, "RESET_2FA", "Admin reset two-factor authentication for "+user.Name, &user.ID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication reset"})
+}
+
+func GetTwoFactorPolicies(c *gin.Context) {
+	policies, err := services.ListTwoFactorPolicies()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch two-factor policies"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"policies": policies})
+}
+
+func UpdateTwoFactorPolicy(c *gin.Context) {
+	var req struct {
+		Required *bool `json:"required" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "required must be true or false"})
+		return
+	}
+
+	adminID, _ := uuid.Parse(c.GetString("userId"))
+	role := models.UserRole(strings.ToUpper(c.Param("role")))
+	policy, err := services.SetTwoFactorPolicy(role, *req.Required, &adminID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidRole) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update two-factor policy"})
+		return
+	}
+
+	state := "optional"
+	if policy.Required {
+		state = "required"
+	}
+	admin := models.User{ID: adminID, Name: c.GetString("userEmail"), Role: models.UserRole(c.GetString("userRole"))}
+	logTwoFactorChange(&admin, "UPDATE_2FA_POLICY", "Admin made two-factor authentication "+state+" for "+string(role), nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor policy updated", "policy": policy})
+}
+
+func signinResponse(c *gin.Context, user *models.User, status int) {
+	challenge, err := services.NewSigninChallenge(user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check two-factor status"})
+		return
+	}
+	if challenge != nil {
+		c.JSON(status, gin.H{
+			"twoFactorRequired":      challenge.Purpose == services.ChallengeTwoFactor,
+			"twoFactorSetupRequired": challenge.Purpose == services.ChallengeTwoFactorSetup,
+			"challengeToken":         challenge.ChallengeToken,
+			"expiresIn":              challenge.ExpiresIn,
+		})
+		return
+	}
+
+	tokens, err := services.IssueTokenPair(user, clientInfo(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	c.JSON(status, authResponse(user, tokens))
+}
+
+func currentTwoFactorUser(c *gin.Context) (*models.User, bool) {
+	uid, _ := uuid.Parse(c.GetString("userId"))
+
+	var user models.User
+	if err := database.DB.First(&user, uid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return nil, false
+	}
+	return &user, true
+}
+
+func respondTwoFactorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidTwoFactorCode), errors.Is(err, services.ErrInvalidChallenge):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTooManyAttempts):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTwoFactorAlreadyEnabled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTwoFactorRequired):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTwoFactorNotEnabled), errors.Is(err, services.ErrTwoFactorNotStarted):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Two-factor authentication failed"})
+	}
+}
+
+func logTwoFactorChange(actor *models.User, action, description string, targetID *uuid.UUID) {
+	userRole := string(actor.Role)
+	params := services.LogActivityParams{
+		Action:      action,
+		Description: description,
+		UserID:      &actor.ID,
+		UserName:    &actor.Name,
+		UserRole:    &userRole,
+	}
+	if targetID != nil {
+		targetType := "user"
+		params.TargetID = targetID
+		params.TargetType = &targetType
+	}
+	services.LogActivity(params)
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+	"log"
 	"net/http"
 
 	"github.com/adzzatxperts/backend/internal/database"
@@ -78,6 +80,10 @@ func ApproveTester(c *gin.Context) {
 		TargetType:  &targetType,
 	})
 
+	if err := services.SendTesterApprovedEmail(&user); err != nil {
+		log.Printf("Failed to email tester approval to %s: %v", user.Email, err)
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Tester approved successfully"})
 }
 
@@ -260,6 +266,10 @@ func SwitchUserRole(c *gin.Context) {
 		return
 	}
 
+	if err := services.BumpTokenVersion(user.ID); err != nil {
+		log.Printf("Failed to revoke tokens after role switch for %s: %v", user.Email, err)
+	}
+
 	currentUserID, _ := c.Get("userId")
 	currentUserName, _ := c.Get("userEmail")
 	currentUserRole, _ := c.Get("userRole")
@@ -340,13 +350,7 @@ func DeleteUser(c *gin.Context) {
 
 	if user.Role == models.RoleTester {
 
-		database.DB.Model(&models.Submission{}).
-			Where("claimed_by_id = ?", uid).
-			Updates(map[string]interface{}{
-				"claimed_by_id": nil,
-				"assigned_at":   nil,
-				"status":        models.StatusPending,
-			})
+		services.ReleaseTesterSubmissions(uid, "Tester account deleted")
 		deletionSummary["assignmentsUnassigned"] = len(user.ClaimedSubmissions)
 
 		database.DB.Where("tester_id = ?", uid).Delete(&models.Review{})
@@ -357,6 +361,7 @@ func DeleteUser(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user"})
 		return
 	}
+	services.ForgetDeletedUser(uid)
 
 	currentUserName, _ := c.Get("userEmail")
 	currentUserRole, _ := c.Get("userRole")
@@ -387,8 +392,9 @@ func UpdateProfile(c *gin.Context) {
 	uid, _ := uuid.Parse(userID.(string))
 
 	var req struct {
-		Name     string `json:"name"`
-		Password string `json:"password"`
+		Name     string           `json:"name"`
+		Password string           `json:"password"`
+		Skills   *services.Skills `json:"skills"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -419,6 +425,22 @@ func UpdateProfile(c *gin.Context) {
 		user.PasswordHash = hashedPassword
 	}
 
+	var skills *services.Skills
+	var err error
+	if req.Skills != nil {
+		skills, err = services.SaveSkills(user.ID, *req.Skills)
+	} else {
+		skills, err = services.GetSkills(user.ID)
+	}
+	if errors.Is(err, services.ErrInvalidSkills) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update skills"})
+		return
+	}
+
 	if err := database.DB.Save(&user).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
 		return
@@ -433,6 +455,7 @@ func UpdateProfile(c *gin.Context) {
 			"role":       user.Role,
 			"isApproved": user.IsApproved,
 		},
+		"skills": skills,
 	})
 }
 
@@ -476,6 +499,12 @@ func GetProfile(c *gin.Context) {
 		}
 	}
 
+	skills, err := services.GetSkills(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch skills"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"user": gin.H{
 			"id":         user.ID,
@@ -485,7 +514,8 @@ func GetProfile(c *gin.Context) {
 			"isApproved": user.IsApproved,
 			"createdAt":  user.CreatedAt,
 		},
-		"stats": stats,
+		"stats":  stats,
+		"skills": skills,
 	})
 }
 
@@ -538,23 +568,14 @@ func DeleteMyAccount(c *gin.Context) {
 		var projectVSubmissions []models.ProjectVSubmission
 		database.DB.Where("contributor_id = ?", uid).Find(&projectVSubmissions)
 		for _, submission := range projectVSubmissions {
-			storage.DeleteFile(submission.TestPatchURL)
-			storage.DeleteFile(submission.DockerfileURL)
-			storage.DeleteFile(submission.SolutionPatchURL)
-			deletionSummary["filesDeleted"] = deletionSummary["filesDeleted"].(int) + 3
+			deletionSummary["filesDeleted"] = deletionSummary["filesDeleted"].(int) + services.DeleteSubmissionArtifacts(&submission)
 		}
 		database.DB.Where("contributor_id = ?", uid).Delete(&models.ProjectVSubmission{})
 	}
 
 	if user.Role == models.RoleTester {
 
-		database.DB.Model(&models.Submission{}).
-			Where("claimed_by_id = ?", uid).
-			Updates(map[string]interface{}{
-				"claimed_by_id": nil,
-				"assigned_at":   nil,
-				"status":        models.StatusPending,
-			})
+		services.ReleaseTesterSubmissions(uid, "Tester account deleted")
 		deletionSummary["assignmentsUnassigned"] = len(user.ClaimedSubmissions)
 
 		database.DB.Where("tester_id = ?", uid).Delete(&models.Review{})
@@ -572,6 +593,7 @@ func DeleteMyAccount(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete account"})
 		return
 	}
+	services.ForgetDeletedUser(uid)
 
 	targetType := "user"
 	services.LogActivity(services.LogActivityParams{
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/adzzatxperts/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func GetValidationJobs(c *gin.Context) {
+	limitStr := c.DefaultQuery("limit", "100")
+	offsetStr := c.DefaultQuery("offset", "0")
+	statusStr := c.Query("status")
+	submissionIDStr := c.Query("submissionId")
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 1 {
+		limit = 100
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	query := database.DB.Model(&models.ValidationJob{})
+
+	if statusStr != "" {
+		query = query.Where("status = ?", statusStr)
+	}
+
+	if submissionIDStr != "" {
+		submissionID, err := uuid.Parse(submissionIDStr)
+		if err == nil {
+			query = query.Where("submission_id = ?", submissionID)
+		}
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var jobs []models.ValidationJob
+	err = query.Preload("Submission", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "title", "status", "contributor_id")
+	}).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&jobs).Error
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch validation jobs"})
+		return
+	}
+
+	var statusCounts []struct {
+		Status string
+		Count  int64
+	}
+	database.DB.Model(&models.ValidationJob{}).
+		Select("status, COUNT(*) as count").
+		Group("status").
+		Scan(&statusCounts)
+
+	statusCountsMap := make(map[string]int64)
+	for _, sc := range statusCounts {
+		statusCountsMap[sc.Status] = sc.Count
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"jobs":         jobs,
+		"total":        total,
+		"limit":        limit,
+		"offset":       offset,
+		"statusCounts": statusCountsMap,
+	})
+}
+
+func CancelValidationJob(c *gin.Context) {
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	job, err := services.CancelValidationJob(jobID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrValidationJobNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Validation job not found"})
+		case errors.Is(err, services.ErrValidationJobNotActive):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Only pending or running jobs can be cancelled"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel validation job"})
+		}
+		return
+	}
+
+	logValidationJobActivity(c, "CANCEL_VALIDATION_JOB", "Admin cancelled validation job", job)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Validation job cancelled successfully", "job": job})
+}
+
+func RerunValidationJob(c *gin.Context) {
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	adminID, _ := uuid.Parse(c.GetString("userId"))
+
+	job, err := services.RerunValidationJob(jobID, &adminID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrValidationJobNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Validation job not found"})
+		case errors.Is(err, services.ErrValidationJobStillActive):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Job is still pending or running"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to re-run validation job"})
+		}
+		return
+	}
+
+	logValidationJobActivity(c, "RERUN_VALIDATION_JOB", "Admin re-queued validation", job)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Validation job queued successfully", "job": job})
+}
+
+func logValidationJobActivity(c *gin.Context, action, description string, job *models.ValidationJob) {
+	uid, _ := uuid.Parse(c.GetString("userId"))
+	userName := c.GetString("userEmail")
+	userRole := c.GetString("userRole")
+	targetType := "projectv_submission"
+
+	services.LogActivity(services.LogActivityParams{
+		Action:      action,
+		Description: description + " for submission " + job.SubmissionID.String(),
+		UserID:      &uid,
+		UserName:    &userName,
+		UserRole:    &userRole,
+		TargetID:    &job.SubmissionID,
+		TargetType:  &targetType,
+		Metadata: map[string]interface{}{
+			"jobId":  job.ID.String(),
+			"status": job.Status,
+		},
+	})
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/adzzatxperts/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func GetValidationRuns(c *gin.Context) {
+	submission, ok := loadRunSubmission(c)
+	if !ok {
+		return
+	}
+
+	runs, err := services.GetValidationRuns(submission.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch validation runs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"runs":  runs,
+		"total": len(runs),
+	})
+}
+
+func GetValidationRun(c *gin.Context) {
+	submission, ok := loadRunSubmission(c)
+	if !ok {
+		return
+	}
+
+	runID, err := uuid.Parse(c.Param("runId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid run ID"})
+		return
+	}
+
+	run, err := services.GetValidationRun(submission.ID, runID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Validation run not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+func loadRunSubmission(c *gin.Context) (*models.ProjectVSubmission, bool) {
+	userID := c.GetString("userId")
+	userRole := c.GetString("userRole")
+
+	submissionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+		return nil, false
+	}
+
+	var submission models.ProjectVSubmission
+	if err := database.DB.First(&submission, submissionID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+		return nil, false
+	}
+
+	if userRole == "CONTRIBUTOR" && submission.ContributorID.String() != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to view this submission"})
+		return nil, false
+	}
+
+	return &submission, true
+}
@@ -56,6 +56,7 @@ func HandleWebSocket(c *gin.Context) {
 	client := &websocket.Client{
 		ID:     uuid.New(),
 		UserID: userID,
+		Role:   c.GetString("userRole"),
 		Send:   make(chan []byte, 256),
 		Hub:    WSHub,
 	}
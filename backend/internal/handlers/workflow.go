@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/adzzatxperts/backend/internal/workflow"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func GetProjectVWorkflow(c *gin.Context) {
+	c.JSON(http.StatusOK, workflow.Describe())
+}
+
+func workflowActor(c *gin.Context) workflow.Actor {
+	userID, _ := uuid.Parse(c.GetString("userId"))
+	return workflow.Actor{ID: userID, Name: c.GetString("userEmail"), Role: c.GetString("userRole")}
+}
+
+func applyProjectVTransition(c *gin.Context, action string, params workflow.Params) (*models.ProjectVSubmission, bool) {
+	submissionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+		return nil, false
+	}
+
+	var submission models.ProjectVSubmission
+	if err := database.DB.First(&submission, submissionID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+		return nil, false
+	}
+
+	if err := workflow.Apply(&submission, action, workflowActor(c), params); err != nil {
+		respondWorkflowError(c, err)
+		return nil, false
+	}
+	return &submission, true
+}
+
+func respondWorkflowError(c *gin.Context, err error) {
+	var wfErr *workflow.Error
+	if !errors.As(err, &wfErr) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update submission"})
+		return
+	}
+
+	switch {
+	case errors.Is(err, workflow.ErrForbidden):
+		response := gin.H{"error": wfErr.Message, "receivedRole": c.GetString("userRole")}
+		if len(wfErr.Roles) > 0 {
+			response["allowedRoles"] = wfErr.Roles
+		}
+		c.JSON(http.StatusForbidden, response)
+	case errors.Is(err, workflow.ErrInvalidTransition):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":         wfErr.Message,
+			"currentStatus": wfErr.Current,
+			"allowedStates": wfErr.Allowed,
+		})
+	case errors.Is(err, workflow.ErrGuardFailed), errors.Is(err, workflow.ErrUnknownAction):
+		c.JSON(http.StatusBadRequest, gin.H{"error": wfErr.Message, "guard": wfErr.Guard})
+	case errors.Is(err, workflow.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": wfErr.Message, "currentStatus": wfErr.Current})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": wfErr.Message})
+	}
+}
@@ -0,0 +1,43 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type FileMailer struct {
+	dir string
+}
+
+func NewFileMailer(dir string) *FileMailer {
+	return &FileMailer{dir: dir}
+}
+
+func (f *FileMailer) Name() string {
+	if f.dir == "" {
+		return "log"
+	}
+	return "file " + f.dir
+}
+
+func (f *FileMailer) Send(ctx context.Context, msg Message) error {
+	if f.dir == "" {
+		log.Printf("📧 Mail to %s: %s", strings.Join(msg.To, ", "), msg.Subject)
+		return nil
+	}
+
+	data, err := msg.Bytes(From())
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), randomToken()[:8])
+	return os.WriteFile(filepath.Join(f.dir, name), data, 0o600)
+}
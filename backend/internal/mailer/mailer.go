@@ -0,0 +1,88 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+var ErrNoRecipients = errors.New("message has no recipients")
+
+type Message struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+type Mailer interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+var (
+	current Mailer
+	mu      sync.RWMutex
+)
+
+func Init() (Mailer, error) {
+	m, err := New(os.Getenv("MAIL_DRIVER"))
+	if err != nil {
+		return nil, err
+	}
+
+	Set(m)
+	return m, nil
+}
+
+func New(name string) (Mailer, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "log":
+		return NewFileMailer(""), nil
+	case "file":
+		dir := os.Getenv("MAIL_FILE_DIR")
+		if dir == "" {
+			dir = "mail"
+		}
+		return NewFileMailer(dir), nil
+	case "smtp":
+		return NewSMTPMailer(SMTPConfigFromEnv())
+	case "", "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q (expected smtp, file, log or none)", name)
+	}
+}
+
+func Set(m Mailer) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = m
+}
+
+func Current() Mailer {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+func Send(ctx context.Context, msg Message) error {
+	m := Current()
+	if m == nil {
+		return nil
+	}
+	if len(msg.To) == 0 {
+		return ErrNoRecipients
+	}
+	return m.Send(ctx, msg)
+}
+
+func From() string {
+	if from := os.Getenv("MAIL_FROM"); from != "" {
+		return from
+	}
+	return "Adzzat <no-reply@localhost>"
+}
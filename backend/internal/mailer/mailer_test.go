@@ -0,0 +1,44 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestNewWithoutDriverDisablesMail(t *testing.T) {
+	m, err := New("")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if m != nil {
+		t.Fatalf("an empty MAIL_DRIVER selected %s, want mail disabled", m.Name())
+	}
+}
+
+func TestLogMailerOmitsBody(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	msg := Message{
+		To:      []string{"someone@example.com"},
+		Subject: "Reset your password",
+		Text:    "https://app.example.com/reset-password?token=secret-token\n",
+		HTML:    `<a href="https://app.example.com/reset-password?token=secret-token">Reset</a>`,
+	}
+	if err := NewFileMailer("").Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	logged := buf.String()
+	if !strings.Contains(logged, "someone@example.com") || !strings.Contains(logged, "Reset your password") {
+		t.Errorf("log %q is missing the recipient or subject", logged)
+	}
+	if strings.Contains(logged, "secret-token") {
+		t.Errorf("log %q contains the message body", logged)
+	}
+}
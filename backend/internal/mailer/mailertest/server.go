@@ -0,0 +1,358 @@
+package mailertest
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math/big"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Message struct {
+	From     string
+	To       []string
+	Subject  string
+	Text     string
+	HTML     string
+	Raw      []byte
+	TLS      bool
+	Username string
+}
+
+type Options struct {
+	StartTLS bool
+	TLS      bool
+	Username string
+	Password string
+}
+
+type Server struct {
+	Host string
+	Port int
+
+	options  Options
+	cert     tls.Certificate
+	roots    *x509.CertPool
+	listener net.Listener
+	mu       sync.Mutex
+	messages []Message
+	received chan struct{}
+	wg       sync.WaitGroup
+}
+
+func NewServer() (*Server, error) {
+	return NewServerWithOptions(Options{})
+}
+
+func NewServerWithOptions(options Options) (*Server, error) {
+	s := &Server{
+		Host:     "127.0.0.1",
+		options:  options,
+		received: make(chan struct{}, 1024),
+	}
+	if options.StartTLS || options.TLS {
+		if err := s.generateCertificate(); err != nil {
+			return nil, err
+		}
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	if options.TLS {
+		listener = tls.NewListener(listener, s.serverTLSConfig())
+	}
+	s.listener = listener
+	s.Port = listener.Addr().(*net.TCPAddr).Port
+
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+func (s *Server) ClientTLSConfig() *tls.Config {
+	return &tls.Config{RootCAs: s.roots, ServerName: s.Host}
+}
+
+func (s *Server) Close() {
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message{}, s.messages...)
+}
+
+func (s *Server) WaitFor(count int, timeout time.Duration) ([]Message, error) {
+	deadline := time.After(timeout)
+	for {
+		if messages := s.Messages(); len(messages) >= count {
+			return messages, nil
+		}
+		select {
+		case <-s.received:
+		case <-deadline:
+			return s.Messages(), fmt.Errorf("timed out waiting for %d messages, got %d", count, len(s.Messages()))
+		}
+	}
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer func() { conn.Close() }()
+	conn.SetDeadline(time.Now().Add(time.Minute))
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) {
+		fmt.Fprintf(conn, "%s\r\n", line)
+	}
+
+	_, secure := conn.(*tls.Conn)
+	var from, username string
+	var to []string
+	reply("220 mailertest ESMTP ready")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch verb {
+		case "EHLO", "HELO":
+			reply("250-mailertest")
+			if s.options.StartTLS && !secure {
+				reply("250-STARTTLS")
+			}
+			if s.options.Username != "" {
+				reply("250-AUTH PLAIN")
+			}
+			reply("250 8BITMIME")
+		case "STARTTLS":
+			if !s.options.StartTLS || secure {
+				reply("502 Command not implemented")
+				continue
+			}
+			reply("220 Ready to start TLS")
+			tlsConn := tls.Server(conn, s.serverTLSConfig())
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn, reader, secure = tlsConn, bufio.NewReader(tlsConn), true
+			from, username, to = "", "", nil
+		case "AUTH":
+			if s.options.Username == "" {
+				reply("502 Command not implemented")
+				continue
+			}
+			user, ok := s.authenticate(line, reader, reply)
+			if !ok {
+				reply("535 5.7.8 Authentication credentials invalid")
+				continue
+			}
+			username = user
+			reply("235 2.7.0 Authentication successful")
+		case "MAIL":
+			if s.options.Username != "" && username == "" {
+				reply("530 5.7.0 Authentication required")
+				continue
+			}
+			from = extractAddress(line)
+			to = nil
+			reply("250 OK")
+		case "RCPT":
+			to = append(to, extractAddress(line))
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			raw, err := readData(reader)
+			if err != nil {
+				return
+			}
+			s.store(Message{From: from, To: to, Raw: raw, TLS: secure, Username: username})
+			reply("250 OK: queued")
+		case "RSET":
+			from, to = "", nil
+			reply("250 OK")
+		case "NOOP":
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func (s *Server) authenticate(line string, reader *bufio.Reader, reply func(string)) (string, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || !strings.EqualFold(fields[1], "PLAIN") {
+		return "", false
+	}
+
+	response := ""
+	if len(fields) > 2 {
+		response = fields[2]
+	} else {
+		reply("334 ")
+		next, err := reader.ReadString('\n')
+		if err != nil {
+			return "", false
+		}
+		response = strings.TrimRight(next, "\r\n")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(response)
+	if err != nil {
+		return "", false
+	}
+	parts := strings.Split(string(decoded), "\x00")
+	if len(parts) != 3 || parts[1] != s.options.Username || parts[2] != s.options.Password {
+		return "", false
+	}
+	return parts[1], true
+}
+
+func (s *Server) generateCertificate() error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "mailertest"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.ParseIP(s.Host)},
+		DNSNames:              []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return err
+	}
+
+	s.cert = tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}
+	s.roots = x509.NewCertPool()
+	s.roots.AddCert(cert)
+	return nil
+}
+
+func (s *Server) serverTLSConfig() *tls.Config {
+	return &tls.Config{Certificates: []tls.Certificate{s.cert}}
+}
+
+func (s *Server) store(message Message) {
+	if parsed, err := mail.ReadMessage(bytes.NewReader(message.Raw)); err == nil {
+		decoder := new(mime.WordDecoder)
+		message.Subject, _ = decoder.DecodeHeader(parsed.Header.Get("Subject"))
+		readParts(parsed.Header.Get("Content-Type"), parsed.Header.Get("Content-Transfer-Encoding"), parsed.Body, &message)
+	}
+
+	s.mu.Lock()
+	s.messages = append(s.messages, message)
+	s.mu.Unlock()
+
+	select {
+	case s.received <- struct{}{}:
+	default:
+	}
+}
+
+func readParts(contentType, encoding string, body io.Reader, message *Message) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err != nil {
+				return
+			}
+			readParts(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part, message)
+		}
+	}
+
+	if strings.EqualFold(encoding, "quoted-printable") {
+		body = quotedprintable.NewReader(body)
+	}
+	content, _ := io.ReadAll(body)
+	switch mediaType {
+	case "text/plain":
+		message.Text = string(content)
+	case "text/html":
+		message.HTML = string(content)
+	}
+}
+
+func readData(reader *bufio.Reader) ([]byte, error) {
+	var buf bytes.Buffer
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		if line == ".\r\n" || line == ".\n" {
+			return buf.Bytes(), nil
+		}
+		buf.WriteString(strings.TrimPrefix(line, "."))
+	}
+}
+
+func extractAddress(line string) string {
+	start := strings.Index(line, "<")
+	end := strings.LastIndex(line, ">")
+	if start < 0 || end <= start {
+		return ""
+	}
+	return line[start+1 : end]
+}
+
+func (s *Server) Address() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+}
@@ -0,0 +1,189 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SecurityStartTLS = "starttls"
+	SecurityTLS      = "tls"
+	SecurityNone     = "none"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	Security string
+	Timeout  time.Duration
+
+	TLSConfig *tls.Config
+}
+
+type SMTPMailer struct {
+	config SMTPConfig
+}
+
+func SMTPConfigFromEnv() SMTPConfig {
+	config := SMTPConfig{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     587,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     From(),
+		Security: strings.ToLower(os.Getenv("SMTP_SECURITY")),
+		Timeout:  30 * time.Second,
+	}
+	if port, err := strconv.Atoi(os.Getenv("SMTP_PORT")); err == nil && port > 0 {
+		config.Port = port
+	}
+	if timeout, err := time.ParseDuration(os.Getenv("SMTP_TIMEOUT")); err == nil && timeout > 0 {
+		config.Timeout = timeout
+	}
+	return config
+}
+
+func NewSMTPMailer(config SMTPConfig) (*SMTPMailer, error) {
+	if config.Host == "" {
+		return nil, errors.New("SMTP_HOST must be set when MAIL_DRIVER=smtp")
+	}
+	if config.Port == 0 {
+		config.Port = 587
+	}
+	if config.From == "" {
+		config.From = From()
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	switch config.Security {
+	case "":
+		config.Security = SecurityStartTLS
+	case SecurityStartTLS, SecurityTLS, SecurityNone:
+	default:
+		return nil, fmt.Errorf("unknown SMTP_SECURITY %q (expected starttls, tls or none)", config.Security)
+	}
+	if _, err := mail.ParseAddress(config.From); err != nil {
+		return nil, fmt.Errorf("invalid MAIL_FROM %q: %w", config.From, err)
+	}
+	if config.Security == SecurityNone && config.Username != "" && !isLocalhost(config.Host) {
+		return nil, errors.New("SMTP_USERNAME requires SMTP_SECURITY=starttls or tls, credentials are never sent unencrypted")
+	}
+	return &SMTPMailer{config: config}, nil
+}
+
+func (s *SMTPMailer) Name() string {
+	return fmt.Sprintf("smtp %s:%d (%s)", s.config.Host, s.config.Port, s.config.Security)
+}
+
+func (s *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	data, err := msg.Bytes(s.config.From)
+	if err != nil {
+		return err
+	}
+	sender, _ := mail.ParseAddress(s.config.From)
+
+	client, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if s.config.Security == SecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("smtp server %s does not support STARTTLS", s.config.Host)
+		}
+		if err := client.StartTLS(s.tlsConfig()); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+
+	if s.config.Username != "" {
+		auth := smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	if err := client.Mail(sender.Address); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	for _, to := range msg.To {
+		address, err := mail.ParseAddress(to)
+		if err != nil {
+			return err
+		}
+		if err := client.Rcpt(address.Address); err != nil {
+			return fmt.Errorf("smtp rcpt to %s: %w", address.Address, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	return client.Quit()
+}
+
+func (s *SMTPMailer) dial(ctx context.Context) (*smtp.Client, error) {
+	address := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+	dialer := &net.Dialer{Timeout: s.config.Timeout}
+
+	var conn net.Conn
+	var err error
+	if s.config.Security == SecurityTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: s.tlsConfig()}).DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("smtp dial %s: %w", address, err)
+	}
+
+	deadline := time.Now().Add(s.config.Timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("smtp handshake: %w", err)
+	}
+	return client, nil
+}
+
+func (s *SMTPMailer) tlsConfig() *tls.Config {
+	config := &tls.Config{}
+	if s.config.TLSConfig != nil {
+		config = s.config.TLSConfig.Clone()
+	}
+	if config.ServerName == "" {
+		config.ServerName = s.config.Host
+	}
+	return config
+}
+
+func isLocalhost(host string) bool {
+	return host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
@@ -0,0 +1,176 @@
+package mailer
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adzzatxperts/backend/internal/mailer/mailertest"
+)
+
+func TestSMTPMailerDelivers(t *testing.T) {
+	cases := []struct {
+		name     string
+		options  mailertest.Options
+		security string
+		username string
+		password string
+		to       []string
+	}{
+		{
+			name:     "plain",
+			security: SecurityNone,
+			to:       []string{"plain@example.com"},
+		},
+		{
+			name:     "starttls",
+			options:  mailertest.Options{StartTLS: true},
+			security: SecurityStartTLS,
+			to:       []string{"starttls@example.com"},
+		},
+		{
+			name:     "implicit tls",
+			options:  mailertest.Options{TLS: true},
+			security: SecurityTLS,
+			to:       []string{"tls@example.com"},
+		},
+		{
+			name:     "auth over starttls",
+			options:  mailertest.Options{StartTLS: true, Username: "mailer", Password: "secret"},
+			security: SecurityStartTLS,
+			username: "mailer",
+			password: "secret",
+			to:       []string{"auth@example.com"},
+		},
+		{
+			name:     "auth on localhost without tls",
+			options:  mailertest.Options{Username: "mailer", Password: "secret"},
+			security: SecurityNone,
+			username: "mailer",
+			password: "secret",
+			to:       []string{"local-auth@example.com"},
+		},
+		{
+			name:     "multiple recipients",
+			options:  mailertest.Options{StartTLS: true},
+			security: SecurityStartTLS,
+			to:       []string{"first@example.com", "Second Person <second@example.com>", "third@example.com"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := startServer(t, tc.options)
+			m := newTestMailer(t, server, tc.security, tc.username, tc.password)
+
+			msg := Message{To: tc.to, Subject: "Hello " + tc.name, Text: "plain body\n", HTML: "<p>html body</p>"}
+			if err := m.Send(context.Background(), msg); err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+
+			received, err := server.WaitFor(1, 5*time.Second)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := received[0]
+
+			var want []string
+			for _, to := range tc.to {
+				if start := strings.Index(to, "<"); start >= 0 {
+					to = strings.TrimSuffix(to[start+1:], ">")
+				}
+				want = append(want, to)
+			}
+			if !reflect.DeepEqual(got.To, want) {
+				t.Errorf("recipients = %v, want %v", got.To, want)
+			}
+			if got.From != "no-reply@example.com" {
+				t.Errorf("envelope sender = %q", got.From)
+			}
+			if got.Subject != msg.Subject {
+				t.Errorf("subject = %q, want %q", got.Subject, msg.Subject)
+			}
+			if !strings.Contains(got.Text, "plain body") || !strings.Contains(got.HTML, "<p>html body</p>") {
+				t.Errorf("body parts were not delivered: text %q, html %q", got.Text, got.HTML)
+			}
+			if wantTLS := tc.security != SecurityNone; got.TLS != wantTLS {
+				t.Errorf("delivered over TLS = %v, want %v", got.TLS, wantTLS)
+			}
+			if got.Username != tc.username {
+				t.Errorf("authenticated as %q, want %q", got.Username, tc.username)
+			}
+		})
+	}
+}
+
+func TestSMTPMailerFailures(t *testing.T) {
+	t.Run("starttls not offered", func(t *testing.T) {
+		server := startServer(t, mailertest.Options{})
+		m := newTestMailer(t, server, SecurityStartTLS, "", "")
+		if err := m.Send(context.Background(), testMessage()); err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+			t.Fatalf("Send = %v, want a STARTTLS error", err)
+		}
+	})
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		server := startServer(t, mailertest.Options{StartTLS: true})
+		m, err := NewSMTPMailer(SMTPConfig{Host: server.Host, Port: server.Port, From: "no-reply@example.com", Timeout: 5 * time.Second})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Send(context.Background(), testMessage()); err == nil {
+			t.Fatal("Send trusted a self-signed certificate")
+		}
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		server := startServer(t, mailertest.Options{StartTLS: true, Username: "mailer", Password: "secret"})
+		m := newTestMailer(t, server, SecurityStartTLS, "mailer", "wrong")
+		if err := m.Send(context.Background(), testMessage()); err == nil || !strings.Contains(err.Error(), "smtp auth") {
+			t.Fatalf("Send = %v, want an auth error", err)
+		}
+	})
+
+	t.Run("credentials without tls to a remote host", func(t *testing.T) {
+		_, err := NewSMTPMailer(SMTPConfig{Host: "smtp.example.com", From: "no-reply@example.com", Security: SecurityNone, Username: "mailer", Password: "secret"})
+		if err == nil {
+			t.Fatal("NewSMTPMailer accepted credentials over an unencrypted connection")
+		}
+	})
+}
+
+func startServer(t *testing.T, options mailertest.Options) *mailertest.Server {
+	t.Helper()
+
+	server, err := mailertest.NewServerWithOptions(options)
+	if err != nil {
+		t.Fatalf("failed to start SMTP server: %v", err)
+	}
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestMailer(t *testing.T, server *mailertest.Server, security, username, password string) *SMTPMailer {
+	t.Helper()
+
+	m, err := NewSMTPMailer(SMTPConfig{
+		Host:      server.Host,
+		Port:      server.Port,
+		Username:  username,
+		Password:  password,
+		From:      "Adzzat <no-reply@example.com>",
+		Security:  security,
+		Timeout:   5 * time.Second,
+		TLSConfig: server.ClientTLSConfig(),
+	})
+	if err != nil {
+		t.Fatalf("NewSMTPMailer: %v", err)
+	}
+	return m
+}
+
+func testMessage() Message {
+	return Message{To: []string{"someone@example.com"}, Subject: "Hello", Text: "body\n"}
+}
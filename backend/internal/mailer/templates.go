@@ -0,0 +1,92 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+)
+
+const (
+	TemplatePasswordReset  = "password_reset"
+	TemplateTesterApproved = "tester_approved"
+	TemplateTaskAssigned   = "task_assigned"
+	TemplateStatusChanged  = "status_changed"
+)
+
+type TemplateData struct {
+	Name        string
+	ActionURL   string
+	ActionLabel string
+	ExpiresIn   string
+	Title       string
+	Project     string
+	Assignment  string
+	From        string
+	To          string
+	Reason      string
+}
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+type emailTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+var (
+	templatesMu sync.Mutex
+	templates   = make(map[string]*emailTemplate)
+)
+
+func Render(name string, to string, data TemplateData) (Message, error) {
+	tmpl, err := loadTemplate(name)
+	if err != nil {
+		return Message{}, err
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, fmt.Errorf("render %s subject: %w", name, err)
+	}
+	if err := tmpl.text.ExecuteTemplate(&text, "text", data); err != nil {
+		return Message{}, fmt.Errorf("render %s text: %w", name, err)
+	}
+	if err := tmpl.html.ExecuteTemplate(&html, "html", data); err != nil {
+		return Message{}, fmt.Errorf("render %s html: %w", name, err)
+	}
+
+	return Message{
+		To:      []string{to},
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    html.String(),
+	}, nil
+}
+
+func loadTemplate(name string) (*emailTemplate, error) {
+	templatesMu.Lock()
+	defer templatesMu.Unlock()
+
+	if tmpl, ok := templates[name]; ok {
+		return tmpl, nil
+	}
+
+	files := []string{"templates/layout.tmpl", "templates/" + name + ".tmpl"}
+	text, err := texttemplate.ParseFS(templateFiles, files...)
+	if err != nil {
+		return nil, fmt.Errorf("unknown email template %q: %w", name, err)
+	}
+	html, err := htmltemplate.ParseFS(templateFiles, files...)
+	if err != nil {
+		return nil, fmt.Errorf("unknown email template %q: %w", name, err)
+	}
+
+	tmpl := &emailTemplate{text: text, html: html}
+	templates[name] = tmpl
+	return tmpl, nil
+}
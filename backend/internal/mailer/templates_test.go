@@ -0,0 +1,70 @@
+package mailer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderTemplates(t *testing.T) {
+	cases := []struct {
+		template string
+		data     TemplateData
+		subject  string
+		contains []string
+	}{
+		{
+			template: TemplatePasswordReset,
+			data:     TemplateData{Name: "Rita", ActionURL: "https://app.example.com/reset-password?token=abc123", ActionLabel: "Reset password", ExpiresIn: "1 hour"},
+			subject:  "Reset your Adzzat password",
+			contains: []string{"Rita", "1 hour"},
+		},
+		{
+			template: TemplateTesterApproved,
+			data:     TemplateData{Name: "Tom", ActionURL: "https://app.example.com/dashboard", ActionLabel: "Open dashboard"},
+			subject:  "approved",
+			contains: []string{"Tom"},
+		},
+		{
+			template: TemplateTaskAssigned,
+			data:     TemplateData{Name: "Ada", ActionURL: "https://app.example.com/project-v/reviewer", ActionLabel: "Open task", Title: "Checkout <fix>", Project: "Project V", Assignment: "review"},
+			subject:  "Checkout <fix>",
+			contains: []string{"Project V", "review"},
+		},
+		{
+			template: TemplateStatusChanged,
+			data:     TemplateData{Name: "Cora", ActionURL: "https://app.example.com/contributor", ActionLabel: "View task", Title: "Login page", Project: "Project X", From: "Claimed", To: "Rejected", Reason: "Steps are missing"},
+			subject:  "Rejected",
+			contains: []string{"Claimed", "Rejected", "Steps are missing"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.template, func(t *testing.T) {
+			msg, err := Render(tc.template, "someone@example.com", tc.data)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			if len(msg.To) != 1 || msg.To[0] != "someone@example.com" {
+				t.Errorf("recipients = %v", msg.To)
+			}
+			if !strings.Contains(msg.Subject, tc.subject) {
+				t.Errorf("subject %q does not mention %q", msg.Subject, tc.subject)
+			}
+			if !strings.Contains(msg.Text, tc.data.ActionURL) || !strings.Contains(msg.HTML, tc.data.ActionURL) {
+				t.Errorf("action link is missing from the body")
+			}
+			for _, want := range tc.contains {
+				if !strings.Contains(msg.Text, want) {
+					t.Errorf("text body does not contain %q", want)
+				}
+			}
+			if strings.Contains(msg.HTML, "<fix>") {
+				t.Errorf("html body is not escaped")
+			}
+		})
+	}
+
+	if _, err := Render("missing", "someone@example.com", TemplateData{}); err == nil {
+		t.Fatal("rendering an unknown template did not fail")
+	}
+}
@@ -1,11 +1,15 @@
 package middleware
 
 import (
+	"errors"
+	"log"
 	"net/http"
 	"strings"
 
+	"github.com/adzzatxperts/backend/internal/services"
 	"github.com/adzzatxperts/backend/internal/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 func AuthMiddleware() gin.HandlerFunc {
@@ -27,21 +31,61 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		tokenString := parts[1]
 
-		claims, err := utils.ValidateJWT(tokenString)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			c.Abort()
+		claims, ok := authenticateToken(c, tokenString)
+		if !ok {
 			return
 		}
 
 		c.Set("userId", claims.UserID)
 		c.Set("userEmail", claims.Email)
 		c.Set("userRole", claims.Role)
+		c.Set("tokenId", claims.ID)
+		c.Set("sessionId", claims.SessionID)
 
 		c.Next()
 	}
 }
 
+func authenticateToken(c *gin.Context, tokenString string) (*utils.Claims, bool) {
+	claims, err := utils.ValidateJWT(tokenString)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		c.Abort()
+		return nil, false
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		c.Abort()
+		return nil, false
+	}
+
+	if err := services.CheckTokenVersion(userID, claims.TokenVersion); err != nil {
+		if errors.Is(err, services.ErrTokenRevoked) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+		} else {
+			log.Printf("Failed to verify token version for %s: %v", claims.UserID, err)
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to verify token"})
+		}
+		c.Abort()
+		return nil, false
+	}
+
+	if err := services.CheckSession(claims.SessionID); err != nil {
+		if errors.Is(err, services.ErrSessionRevoked) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has been terminated"})
+		} else {
+			log.Printf("Failed to verify session for %s: %v", claims.UserID, err)
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to verify token"})
+		}
+		c.Abort()
+		return nil, false
+	}
+
+	return claims, true
+}
+
 func RequireRole(roles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userRole, exists := c.Get("userRole")
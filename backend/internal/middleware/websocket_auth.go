@@ -4,7 +4,6 @@ import (
 	"net/http"
 	"strings"
 
-	"github.com/adzzatxperts/backend/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -30,16 +29,16 @@ func WebSocketAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		claims, err := utils.ValidateJWT(tokenString)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			c.Abort()
+		claims, ok := authenticateToken(c, tokenString)
+		if !ok {
 			return
 		}
 
 		c.Set("userId", claims.UserID)
 		c.Set("userEmail", claims.Email)
 		c.Set("userRole", claims.Role)
+		c.Set("tokenId", claims.ID)
+		c.Set("sessionId", claims.SessionID)
 
 		c.Next()
 	}
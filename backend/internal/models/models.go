@@ -42,13 +42,92 @@ const (
 	ProjectVStatusEligible                ProjectVStatus = "ELIGIBLE_FOR_MANUAL_REVIEW"
 )
 
+const (
+	TransitionSubjectSubmission = "submission"
+	TransitionSubjectProjectV   = "projectv_submission"
+)
+
+type ValidationRunStatus string
+
+const (
+	ValidationRunRunning   ValidationRunStatus = "RUNNING"
+	ValidationRunPassed    ValidationRunStatus = "PASSED"
+	ValidationRunFailed    ValidationRunStatus = "FAILED"
+	ValidationRunErrored   ValidationRunStatus = "ERRORED"
+	ValidationRunCancelled ValidationRunStatus = "CANCELLED"
+)
+
+type ValidationStepStatus string
+
+const (
+	ValidationStepPending ValidationStepStatus = "PENDING"
+	ValidationStepRunning ValidationStepStatus = "RUNNING"
+	ValidationStepPassed  ValidationStepStatus = "PASSED"
+	ValidationStepFailed  ValidationStepStatus = "FAILED"
+	ValidationStepSkipped ValidationStepStatus = "SKIPPED"
+)
+
+const (
+	StepClone           = "clone"
+	StepPatchAnalysis   = "patch_analysis"
+	StepTestPatch       = "test_patch"
+	StepDockerfile      = "dockerfile"
+	StepDockerBuild     = "docker_build"
+	StepBaseTests       = "base_tests"
+	StepNewTests        = "new_tests"
+	StepSolutionPatch   = "solution_patch"
+	StepDockerRebuild   = "docker_rebuild"
+	StepFinalBaseTests  = "final_base_tests"
+	StepFinalNewTests   = "final_new_tests"
+	StepTestTransitions = "test_transitions"
+)
+
+var ValidationStepNames = []string{
+	StepClone,
+	StepPatchAnalysis,
+	StepTestPatch,
+	StepDockerfile,
+	StepDockerBuild,
+	StepBaseTests,
+	StepNewTests,
+	StepSolutionPatch,
+	StepDockerRebuild,
+	StepFinalBaseTests,
+	StepFinalNewTests,
+	StepTestTransitions,
+}
+
+type ValidationJobStatus string
+
+const (
+	ValidationJobPending   ValidationJobStatus = "PENDING"
+	ValidationJobRunning   ValidationJobStatus = "RUNNING"
+	ValidationJobSucceeded ValidationJobStatus = "SUCCEEDED"
+	ValidationJobFailed    ValidationJobStatus = "FAILED"
+	ValidationJobCancelled ValidationJobStatus = "CANCELLED"
+)
+
+const (
+	DifficultyEasy   = "Easy"
+	DifficultyMedium = "Medium"
+	DifficultyHard   = "Hard"
+)
+
+var Difficulties = []string{DifficultyEasy, DifficultyMedium, DifficultyHard}
+
 type RefreshToken struct {
-	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"userId"`
-	Token     string     `gorm:"type:text;uniqueIndex;not null" json:"token"`
-	ExpiresAt time.Time  `gorm:"not null;index" json:"expiresAt"`
-	CreatedAt time.Time  `gorm:"index" json:"createdAt"`
-	RevokedAt *time.Time `gorm:"index" json:"revokedAt,omitempty"`
+	ID           uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID       uuid.UUID  `gorm:"type:uuid;not null;index" json:"userId"`
+	Token        string     `gorm:"type:text;uniqueIndex;not null" json:"-"`
+	FamilyID     *uuid.UUID `gorm:"type:uuid;index" json:"familyId,omitempty"`
+	ReplacedByID *uuid.UUID `gorm:"type:uuid" json:"replacedById,omitempty"`
+	UserAgent    string     `gorm:"type:text" json:"userAgent"`
+	IPAddress    string     `gorm:"type:varchar(64)" json:"ipAddress"`
+	ExpiresAt    time.Time  `gorm:"not null;index" json:"expiresAt"`
+	CreatedAt    time.Time  `gorm:"index" json:"createdAt"`
+	StartedAt    *time.Time `json:"startedAt,omitempty"`
+	LastUsedAt   *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt    *time.Time `gorm:"index" json:"revokedAt,omitempty"`
 
 	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
 }
@@ -61,6 +140,7 @@ type User struct {
 	Role         UserRole  `gorm:"type:varchar(20);not null;default:'CONTRIBUTOR';index" json:"role"`
 	IsApproved   bool      `gorm:"default:false;index" json:"isApproved"`
 	IsGreenLight bool      `gorm:"default:true;index" json:"isGreenLight"`
+	TokenVersion int       `gorm:"not null;default:0;<-:false" json:"-"`
 	CreatedAt    time.Time `gorm:"index" json:"createdAt"`
 	UpdatedAt    time.Time `json:"updatedAt"`
 
@@ -68,6 +148,132 @@ type User struct {
 	ClaimedSubmissions []Submission   `gorm:"foreignKey:ClaimedByID" json:"claimedSubmissions,omitempty"`
 	Reviews            []Review       `gorm:"foreignKey:TesterID" json:"reviews,omitempty"`
 	RefreshTokens      []RefreshToken `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"refreshTokens,omitempty"`
+	SkillProfile       *SkillProfile  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"skillProfile,omitempty"`
+	Availability       *Availability  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"availability,omitempty"`
+	OutOfOffice        []OutOfOffice  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"outOfOffice,omitempty"`
+	TwoFactor          *TwoFactorAuth `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	RecoveryCodes      []RecoveryCode `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+type Availability struct {
+	ID                 uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID             uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"userId"`
+	MaxConcurrentTasks int       `gorm:"not null;default:0" json:"maxConcurrentTasks"`
+	TimeZone           string    `gorm:"type:varchar(64);not null;default:'UTC'" json:"timeZone"`
+	WorkingHours       *string   `gorm:"type:jsonb" json:"workingHours,omitempty"`
+	CreatedAt          time.Time `json:"createdAt"`
+	UpdatedAt          time.Time `json:"updatedAt"`
+}
+
+type OutOfOffice struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
+	StartsAt  time.Time `gorm:"not null;index" json:"startsAt"`
+	EndsAt    time.Time `gorm:"not null;index" json:"endsAt"`
+	Reason    string    `gorm:"type:text" json:"reason,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type TwoFactorAuth struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"userId"`
+	Secret         string     `gorm:"type:text;not null" json:"-"`
+	EnabledAt      *time.Time `json:"enabledAt,omitempty"`
+	LastUsedStep   int64      `gorm:"not null;default:0" json:"-"`
+	FailedAttempts int        `gorm:"not null;default:0" json:"-"`
+	LockedUntil    *time.Time `json:"lockedUntil,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+type RecoveryCode struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"userId"`
+	CodeHash  string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	UsedAt    *time.Time `json:"usedAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+type TwoFactorPolicy struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Role        UserRole   `gorm:"type:varchar(20);not null;uniqueIndex" json:"role"`
+	Required    bool       `gorm:"not null;default:false" json:"required"`
+	UpdatedByID *uuid.UUID `gorm:"type:uuid" json:"updatedById,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+
+	UpdatedBy *User `gorm:"foreignKey:UpdatedByID;constraint:OnDelete:SET NULL" json:"-"`
+}
+
+const (
+	PipelineProjectX        = "project_x"
+	PipelineProjectVTesting = "projectv_testing"
+	PipelineProjectVReview  = "projectv_review"
+)
+
+var Pipelines = []string{PipelineProjectX, PipelineProjectVTesting, PipelineProjectVReview}
+
+type AssignmentSetting struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Pipeline     string     `gorm:"type:varchar(30);not null;uniqueIndex" json:"pipeline"`
+	Strategy     string     `gorm:"type:varchar(30);not null" json:"strategy"`
+	Params       *string    `gorm:"type:jsonb" json:"params,omitempty"`
+	LastAssignee *uuid.UUID `gorm:"type:uuid" json:"lastAssignee,omitempty"`
+	UpdatedByID  *uuid.UUID `gorm:"type:uuid" json:"updatedById,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+
+	UpdatedBy *User `gorm:"foreignKey:UpdatedByID;constraint:OnDelete:SET NULL" json:"-"`
+}
+
+type SLAPolicy struct {
+	ID                   uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name                 string     `gorm:"not null" json:"name"`
+	Pipeline             string     `gorm:"type:varchar(30);not null;uniqueIndex:idx_sla_policies_pipeline_status" json:"pipeline"`
+	Status               string     `gorm:"type:varchar(50);not null;uniqueIndex:idx_sla_policies_pipeline_status" json:"status"`
+	ThresholdMinutes     int        `gorm:"not null" json:"thresholdMinutes"`
+	EscalateAfterMinutes int        `gorm:"not null;default:0" json:"escalateAfterMinutes"`
+	Reassign             bool       `gorm:"default:false" json:"reassign"`
+	Active               bool       `gorm:"default:true;index" json:"active"`
+	CreatedByID          *uuid.UUID `gorm:"type:uuid" json:"createdById,omitempty"`
+	CreatedAt            time.Time  `json:"createdAt"`
+	UpdatedAt            time.Time  `json:"updatedAt"`
+
+	CreatedBy *User `gorm:"foreignKey:CreatedByID;constraint:OnDelete:SET NULL" json:"-"`
+}
+
+type SLABreach struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	PolicyID       uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_sla_breaches_stint" json:"policyId"`
+	Pipeline       string     `gorm:"type:varchar(30);not null;index" json:"pipeline"`
+	SubjectType    string     `gorm:"type:varchar(30);not null" json:"subjectType"`
+	SubjectID      uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_sla_breaches_stint" json:"subjectId"`
+	Title          string     `json:"title"`
+	Status         string     `gorm:"type:varchar(50);not null" json:"status"`
+	AssigneeID     *uuid.UUID `gorm:"type:uuid;index" json:"assigneeId,omitempty"`
+	StartedAt      time.Time  `gorm:"not null;uniqueIndex:idx_sla_breaches_stint" json:"startedAt"`
+	BreachedAt     time.Time  `gorm:"not null;index" json:"breachedAt"`
+	NotifiedAt     *time.Time `json:"notifiedAt,omitempty"`
+	EscalatedAt    *time.Time `json:"escalatedAt,omitempty"`
+	ReassignedAt   *time.Time `json:"reassignedAt,omitempty"`
+	ReassignedToID *uuid.UUID `gorm:"type:uuid" json:"reassignedToId,omitempty"`
+	ResolvedAt     *time.Time `gorm:"index" json:"resolvedAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+
+	Policy       *SLAPolicy `gorm:"foreignKey:PolicyID;constraint:OnDelete:CASCADE" json:"policy,omitempty"`
+	Assignee     *User      `gorm:"foreignKey:AssigneeID;constraint:OnDelete:SET NULL" json:"assignee,omitempty"`
+	ReassignedTo *User      `gorm:"foreignKey:ReassignedToID;constraint:OnDelete:SET NULL" json:"-"`
+}
+
+type SkillProfile struct {
+	ID            uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID        uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"userId"`
+	Languages     *string   `gorm:"type:jsonb" json:"languages,omitempty"`
+	Categories    *string   `gorm:"type:jsonb" json:"categories,omitempty"`
+	MaxDifficulty string    `gorm:"type:varchar(20)" json:"maxDifficulty,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
 }
 
 type Submission struct {
@@ -171,31 +377,224 @@ type ProjectVSubmission struct {
 	RejectionReason     *string `gorm:"type:text" json:"rejectionReason,omitempty"`
 	AccountPostedIn     *string `gorm:"type:text" json:"accountPostedIn,omitempty"`
 
-	ProcessingLogs       string `gorm:"type:text" json:"processingLogs,omitempty"`
-	ProcessingComplete   bool   `gorm:"default:false" json:"processingComplete"`
-	CloneSuccess         bool   `gorm:"default:false" json:"cloneSuccess"`
-	CloneError           string `gorm:"type:text" json:"cloneError,omitempty"`
-	TestPatchSuccess     bool   `gorm:"default:false" json:"testPatchSuccess"`
-	TestPatchError       string `gorm:"type:text" json:"testPatchError,omitempty"`
-	DockerBuildSuccess   bool   `gorm:"default:false" json:"dockerBuildSuccess"`
-	DockerBuildError     string `gorm:"type:text" json:"dockerBuildError,omitempty"`
-	BaseTestSuccess      bool   `gorm:"default:false" json:"baseTestSuccess"`
-	BaseTestError        string `gorm:"type:text" json:"baseTestError,omitempty"`
-	NewTestSuccess       bool   `gorm:"default:false" json:"newTestSuccess"`
-	NewTestError         string `gorm:"type:text" json:"newTestError,omitempty"`
-	SolutionPatchSuccess bool   `gorm:"default:false" json:"solutionPatchSuccess"`
-	SolutionPatchError   string `gorm:"type:text" json:"solutionPatchError,omitempty"`
-	FinalBaseTestSuccess bool   `gorm:"default:false" json:"finalBaseTestSuccess"`
-	FinalBaseTestError   string `gorm:"type:text" json:"finalBaseTestError,omitempty"`
-	FinalNewTestSuccess  bool   `gorm:"default:false" json:"finalNewTestSuccess"`
-	FinalNewTestError    string `gorm:"type:text" json:"finalNewTestError,omitempty"`
+	ProcessingLogs     string  `gorm:"type:text" json:"processingLogs,omitempty"`
+	ProcessingComplete bool    `gorm:"default:false" json:"processingComplete"`
+	FailToPass         *string `gorm:"type:jsonb" json:"failToPass,omitempty"`
+	PassToPass         *string `gorm:"type:jsonb" json:"passToPass,omitempty"`
+	PassToFail         *string `gorm:"type:jsonb" json:"passToFail,omitempty"`
+	PatchAnalysis      *string `gorm:"type:jsonb" json:"patchAnalysis,omitempty"`
 
 	CreatedAt time.Time `gorm:"index" json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 
-	Contributor *User `gorm:"foreignKey:ContributorID" json:"contributor,omitempty"`
-	Tester      *User `gorm:"foreignKey:TesterID" json:"tester,omitempty"`
-	Reviewer    *User `gorm:"foreignKey:ReviewerID" json:"reviewer,omitempty"`
+	Contributor    *User           `gorm:"foreignKey:ContributorID" json:"contributor,omitempty"`
+	Tester         *User           `gorm:"foreignKey:TesterID" json:"tester,omitempty"`
+	Reviewer       *User           `gorm:"foreignKey:ReviewerID" json:"reviewer,omitempty"`
+	ValidationRuns []ValidationRun `gorm:"foreignKey:SubmissionID;constraint:OnDelete:CASCADE" json:"validationRuns,omitempty"`
+	LatestRun      *ValidationRun  `gorm:"-" json:"latestRun,omitempty"`
+
+	ValidationStepResults `gorm:"-"`
+}
+
+type ValidationStepResults struct {
+	CloneSuccess         bool   `json:"cloneSuccess"`
+	CloneError           string `json:"cloneError,omitempty"`
+	TestPatchSuccess     bool   `json:"testPatchSuccess"`
+	TestPatchError       string `json:"testPatchError,omitempty"`
+	DockerBuildSuccess   bool   `json:"dockerBuildSuccess"`
+	DockerBuildError     string `json:"dockerBuildError,omitempty"`
+	BaseTestSuccess      bool   `json:"baseTestSuccess"`
+	BaseTestError        string `json:"baseTestError,omitempty"`
+	NewTestSuccess       bool   `json:"newTestSuccess"`
+	NewTestError         string `json:"newTestError,omitempty"`
+	SolutionPatchSuccess bool   `json:"solutionPatchSuccess"`
+	SolutionPatchError   string `json:"solutionPatchError,omitempty"`
+	FinalBaseTestSuccess bool   `json:"finalBaseTestSuccess"`
+	FinalBaseTestError   string `json:"finalBaseTestError,omitempty"`
+	FinalNewTestSuccess  bool   `json:"finalNewTestSuccess"`
+	FinalNewTestError    string `json:"finalNewTestError,omitempty"`
+}
+
+type ValidationRun struct {
+	ID               uuid.UUID           `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SubmissionID     uuid.UUID           `gorm:"type:uuid;not null;index" json:"submissionId"`
+	JobID            *uuid.UUID          `gorm:"type:uuid;index" json:"jobId,omitempty"`
+	RunNumber        int                 `gorm:"not null" json:"runNumber"`
+	Trigger          string              `gorm:"type:varchar(20)" json:"trigger,omitempty"`
+	Status           ValidationRunStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	CommitHash       string              `json:"commitHash"`
+	TestPatchKey     string              `json:"testPatchKey"`
+	DockerfileKey    string              `json:"dockerfileKey"`
+	SolutionPatchKey string              `json:"solutionPatchKey"`
+	Error            string              `gorm:"type:text" json:"error,omitempty"`
+	StartedAt        time.Time           `json:"startedAt"`
+	FinishedAt       *time.Time          `json:"finishedAt,omitempty"`
+	DurationMs       int64               `json:"durationMs"`
+	CreatedAt        time.Time           `gorm:"index" json:"createdAt"`
+
+	Steps []ValidationStep `gorm:"foreignKey:RunID;constraint:OnDelete:CASCADE" json:"steps,omitempty"`
+}
+
+type ValidationStep struct {
+	ID              uuid.UUID            `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	RunID           uuid.UUID            `gorm:"type:uuid;not null;index" json:"runId"`
+	Position        int                  `gorm:"not null" json:"position"`
+	Name            string               `gorm:"type:varchar(50);not null" json:"name"`
+	Status          ValidationStepStatus `gorm:"type:varchar(20);not null" json:"status"`
+	StartedAt       *time.Time           `json:"startedAt,omitempty"`
+	FinishedAt      *time.Time           `json:"finishedAt,omitempty"`
+	DurationMs      int64                `json:"durationMs"`
+	ExitCode        *int                 `json:"exitCode,omitempty"`
+	Output          string               `gorm:"type:text" json:"output,omitempty"`
+	OutputTruncated bool                 `gorm:"default:false" json:"outputTruncated"`
+	Error           string               `gorm:"type:text" json:"error,omitempty"`
+	Limits          *string              `gorm:"type:jsonb" json:"limits,omitempty"`
+	TestSummary     *string              `gorm:"type:jsonb" json:"testSummary,omitempty"`
+}
+
+type ValidationJob struct {
+	ID            uuid.UUID           `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SubmissionID  uuid.UUID           `gorm:"type:uuid;not null;index" json:"submissionId"`
+	Status        ValidationJobStatus `gorm:"type:varchar(20);not null;default:'PENDING';index" json:"status"`
+	Trigger       string              `gorm:"type:varchar(20);not null" json:"trigger"`
+	Attempts      int                 `gorm:"default:0" json:"attempts"`
+	MaxAttempts   int                 `gorm:"default:3" json:"maxAttempts"`
+	RunAfter      time.Time           `gorm:"not null;index" json:"runAfter"`
+	LockedBy      *string             `gorm:"type:varchar(100)" json:"lockedBy,omitempty"`
+	LockedAt      *time.Time          `gorm:"index" json:"lockedAt,omitempty"`
+	StartedAt     *time.Time          `json:"startedAt,omitempty"`
+	FinishedAt    *time.Time          `json:"finishedAt,omitempty"`
+	LastError     string              `gorm:"type:text" json:"lastError,omitempty"`
+	RequestedByID *uuid.UUID          `gorm:"type:uuid" json:"requestedById,omitempty"`
+	CreatedAt     time.Time           `gorm:"index" json:"createdAt"`
+	UpdatedAt     time.Time           `json:"updatedAt"`
+
+	Submission  *ProjectVSubmission `gorm:"foreignKey:SubmissionID;constraint:OnDelete:CASCADE" json:"submission,omitempty"`
+	RequestedBy *User               `gorm:"foreignKey:RequestedByID" json:"requestedBy,omitempty"`
+}
+
+const (
+	ArtifactTestPatch     = "test_patch"
+	ArtifactDockerfile    = "dockerfile"
+	ArtifactSolutionPatch = "solution_patch"
+)
+
+var ArtifactNames = []string{ArtifactTestPatch, ArtifactDockerfile, ArtifactSolutionPatch}
+
+type SubmissionRevision struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SubmissionID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_submission_revisions_version,priority:1" json:"submissionId"`
+	Artifact     string     `gorm:"type:varchar(30);not null;uniqueIndex:idx_submission_revisions_version,priority:2" json:"artifact"`
+	Version      int        `gorm:"not null;uniqueIndex:idx_submission_revisions_version,priority:3" json:"version"`
+	StorageKey   string     `gorm:"type:text;not null" json:"-"`
+	FileName     string     `gorm:"not null" json:"fileName"`
+	ContentType  string     `gorm:"type:varchar(100)" json:"contentType"`
+	SizeBytes    int64      `json:"sizeBytes"`
+	SHA256       string     `gorm:"type:varchar(64)" json:"sha256"`
+	UploadedByID *uuid.UUID `gorm:"type:uuid" json:"uploadedById,omitempty"`
+	CreatedAt    time.Time  `gorm:"index" json:"createdAt"`
+
+	DownloadURL string              `gorm:"-" json:"downloadUrl,omitempty"`
+	Submission  *ProjectVSubmission `gorm:"foreignKey:SubmissionID;constraint:OnDelete:CASCADE" json:"-"`
+	UploadedBy  *User               `gorm:"foreignKey:UploadedByID;constraint:OnDelete:SET NULL" json:"uploadedBy,omitempty"`
+}
+
+const (
+	CommentPatchTest     = "test"
+	CommentPatchSolution = "solution"
+)
+
+type Comment struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SubmissionID uuid.UUID  `gorm:"type:uuid;not null;index" json:"submissionId"`
+	ParentID     *uuid.UUID `gorm:"type:uuid;index" json:"parentId,omitempty"`
+	AuthorID     *uuid.UUID `gorm:"type:uuid;index" json:"authorId,omitempty"`
+	AuthorRole   string     `gorm:"type:varchar(20);not null" json:"authorRole"`
+	Body         string     `gorm:"type:text;not null" json:"body"`
+	Blocking     bool       `gorm:"default:false" json:"blocking"`
+	Resolved     bool       `gorm:"default:false" json:"resolved"`
+	ResolvedByID *uuid.UUID `gorm:"type:uuid" json:"resolvedById,omitempty"`
+	ResolvedAt   *time.Time `json:"resolvedAt,omitempty"`
+	AnchorPatch  *string    `gorm:"type:varchar(20)" json:"anchorPatch,omitempty"`
+	AnchorPath   *string    `gorm:"type:text" json:"anchorPath,omitempty"`
+	AnchorLine   *int       `json:"anchorLine,omitempty"`
+	CreatedAt    time.Time  `gorm:"index" json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+
+	Submission *ProjectVSubmission `gorm:"foreignKey:SubmissionID;constraint:OnDelete:CASCADE" json:"-"`
+	Author     *User               `gorm:"foreignKey:AuthorID;constraint:OnDelete:SET NULL" json:"author,omitempty"`
+	Replies    []Comment           `gorm:"foreignKey:ParentID;constraint:OnDelete:CASCADE" json:"replies,omitempty"`
+}
+
+type StatusTransition struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SubjectType string     `gorm:"type:varchar(30);not null" json:"subjectType"`
+	SubjectID   uuid.UUID  `gorm:"type:uuid;not null" json:"subjectId"`
+	FromStatus  *string    `gorm:"type:varchar(50)" json:"fromStatus,omitempty"`
+	ToStatus    string     `gorm:"type:varchar(50);not null;index" json:"toStatus"`
+	Action      *string    `gorm:"type:varchar(50)" json:"action,omitempty"`
+	ActorID     *uuid.UUID `gorm:"type:uuid;index" json:"actorId,omitempty"`
+	ActorName   *string    `json:"actorName,omitempty"`
+	ActorRole   string     `gorm:"type:varchar(20);not null" json:"actorRole"`
+	Reason      *string    `gorm:"type:text" json:"reason,omitempty"`
+	CreatedAt   time.Time  `gorm:"index" json:"createdAt"`
+}
+
+type Rubric struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name        string     `gorm:"not null" json:"name"`
+	Description string     `gorm:"type:text" json:"description,omitempty"`
+	Category    string     `gorm:"not null;default:'';index" json:"category"`
+	Difficulty  string     `gorm:"not null;default:'';index" json:"difficulty"`
+	Active      bool       `gorm:"default:true;index" json:"active"`
+	CreatedByID *uuid.UUID `gorm:"type:uuid" json:"createdById,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+
+	Criteria  []RubricCriterion `gorm:"foreignKey:RubricID;constraint:OnDelete:CASCADE" json:"criteria"`
+	CreatedBy *User             `gorm:"foreignKey:CreatedByID;constraint:OnDelete:SET NULL" json:"-"`
+}
+
+type RubricCriterion struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	RubricID    uuid.UUID `gorm:"type:uuid;not null;index" json:"rubricId"`
+	Position    int       `gorm:"not null" json:"position"`
+	Name        string    `gorm:"not null" json:"name"`
+	Description string    `gorm:"type:text" json:"description,omitempty"`
+	Weight      float64   `gorm:"not null;default:1" json:"weight"`
+	MinScore    int       `gorm:"not null;default:0" json:"minScore"`
+	MaxScore    int       `gorm:"not null;default:5" json:"maxScore"`
+}
+
+type RubricEvaluation struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SubmissionID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"submissionId"`
+	RubricID      *uuid.UUID `gorm:"type:uuid;index" json:"rubricId,omitempty"`
+	RubricName    string     `gorm:"not null" json:"rubricName"`
+	ReviewerID    *uuid.UUID `gorm:"type:uuid;index" json:"reviewerId,omitempty"`
+	Action        string     `gorm:"type:varchar(50);not null" json:"action"`
+	WeightedScore float64    `gorm:"not null" json:"weightedScore"`
+	Comment       string     `gorm:"type:text" json:"comment,omitempty"`
+	CreatedAt     time.Time  `gorm:"index" json:"createdAt"`
+
+	Scores     []RubricScore       `gorm:"foreignKey:EvaluationID;constraint:OnDelete:CASCADE" json:"scores"`
+	Submission *ProjectVSubmission `gorm:"foreignKey:SubmissionID;constraint:OnDelete:CASCADE" json:"-"`
+	Rubric     *Rubric             `gorm:"foreignKey:RubricID;constraint:OnDelete:SET NULL" json:"-"`
+	Reviewer   *User               `gorm:"foreignKey:ReviewerID;constraint:OnDelete:SET NULL" json:"reviewer,omitempty"`
+}
+
+type RubricScore struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	EvaluationID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"evaluationId"`
+	CriterionID   *uuid.UUID `gorm:"type:uuid;index" json:"criterionId,omitempty"`
+	CriterionName string     `gorm:"not null" json:"criterionName"`
+	Weight        float64    `gorm:"not null" json:"weight"`
+	MinScore      int        `gorm:"not null" json:"minScore"`
+	MaxScore      int        `gorm:"not null" json:"maxScore"`
+	Score         int        `gorm:"not null" json:"score"`
+	Note          string     `gorm:"type:text" json:"note,omitempty"`
+
+	Criterion *RubricCriterion `gorm:"foreignKey:CriterionID;constraint:OnDelete:SET NULL" json:"-"`
 }
 
 func (u *User) BeforeCreate(tx *gorm.DB) error {
@@ -250,3 +649,136 @@ func (p *ProjectVSubmission) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+func (j *ValidationJob) BeforeCreate(tx *gorm.DB) error {
+	if j.ID == uuid.Nil {
+		j.ID = uuid.New()
+	}
+	return nil
+}
+
+func (r *ValidationRun) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+func (s *ValidationStep) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+func (t *StatusTransition) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+func (c *Comment) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+func (r *SubmissionRevision) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+func (r *Rubric) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+func (c *RubricCriterion) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+func (e *RubricEvaluation) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+func (s *RubricScore) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+func (p *SkillProfile) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+func (a *AssignmentSetting) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+func (a *Availability) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+func (o *OutOfOffice) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == uuid.Nil {
+		o.ID = uuid.New()
+	}
+	return nil
+}
+
+func (p *SLAPolicy) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+func (b *SLABreach) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return nil
+}
+
+func (t *TwoFactorAuth) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+func (r *RecoveryCode) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+func (p *TwoFactorPolicy) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package patch
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	FindingParseError           = "PARSE_ERROR"
+	FindingEmptyPatch           = "EMPTY_PATCH"
+	FindingSolutionTouchesTests = "SOLUTION_TOUCHES_TESTS"
+	FindingTestTouchesNonTests  = "TEST_PATCH_TOUCHES_NON_TESTS"
+	FindingPatchesOverlap       = "PATCHES_OVERLAP"
+	FindingBinaryHunk           = "BINARY_HUNK"
+	FindingSolutionTooLarge     = "SOLUTION_TOO_LARGE"
+)
+
+const (
+	SeverityWarning = "WARNING"
+	SeverityError   = "ERROR"
+)
+
+type Finding struct {
+	Code     string   `json:"code"`
+	Severity string   `json:"severity"`
+	Patch    string   `json:"patch,omitempty"`
+	Message  string   `json:"message"`
+	Files    []string `json:"files,omitempty"`
+}
+
+type Report struct {
+	TestPatch     *Patch    `json:"testPatch,omitempty"`
+	SolutionPatch *Patch    `json:"solutionPatch,omitempty"`
+	Findings      []Finding `json:"findings"`
+}
+
+type Rules struct {
+	MaxSolutionLines int
+}
+
+func Analyze(testPatch, solutionPatch []byte, rules Rules) *Report {
+	report := &Report{Findings: []Finding{}}
+
+	report.TestPatch = parseForReport(report, "test", testPatch)
+	report.SolutionPatch = parseForReport(report, "solution", solutionPatch)
+
+	if report.TestPatch != nil {
+		var nonTests []string
+		for _, f := range report.TestPatch.Files {
+			if !f.IsTest {
+				nonTests = append(nonTests, f.Path())
+			}
+		}
+		if len(nonTests) > 0 {
+			report.add(Finding{
+				Code:     FindingTestTouchesNonTests,
+				Severity: SeverityWarning,
+				Patch:    "test",
+				Message:  fmt.Sprintf("Test patch modifies %d non-test files", len(nonTests)),
+				Files:    nonTests,
+			})
+		}
+	}
+
+	if report.SolutionPatch != nil {
+		var tests []string
+		for _, f := range report.SolutionPatch.Files {
+			if f.IsTest {
+				tests = append(tests, f.Path())
+			}
+		}
+		if len(tests) > 0 {
+			report.add(Finding{
+				Code:     FindingSolutionTouchesTests,
+				Severity: SeverityError,
+				Patch:    "solution",
+				Message:  fmt.Sprintf("Solution patch modifies %d test files", len(tests)),
+				Files:    tests,
+			})
+		}
+
+		changed := report.SolutionPatch.Added + report.SolutionPatch.Removed
+		if rules.MaxSolutionLines > 0 && changed > rules.MaxSolutionLines {
+			report.add(Finding{
+				Code:     FindingSolutionTooLarge,
+				Severity: SeverityWarning,
+				Patch:    "solution",
+				Message:  fmt.Sprintf("Solution patch changes %d lines (limit %d)", changed, rules.MaxSolutionLines),
+			})
+		}
+	}
+
+	if report.TestPatch != nil && report.SolutionPatch != nil {
+		testPaths := make(map[string]bool)
+		for _, p := range report.TestPatch.Paths() {
+			testPaths[p] = true
+		}
+		var shared []string
+		for _, p := range report.SolutionPatch.Paths() {
+			if testPaths[p] {
+				shared = append(shared, p)
+			}
+		}
+		if len(shared) > 0 {
+			sort.Strings(shared)
+			report.add(Finding{
+				Code:     FindingPatchesOverlap,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("Test and solution patches both modify %d files", len(shared)),
+				Files:    shared,
+			})
+		}
+	}
+
+	for name, p := range map[string]*Patch{"test": report.TestPatch, "solution": report.SolutionPatch} {
+		if p == nil {
+			continue
+		}
+		var binary []string
+		for _, f := range p.Files {
+			if f.Binary {
+				binary = append(binary, f.Path())
+			}
+		}
+		if len(binary) > 0 {
+			report.add(Finding{
+				Code:     FindingBinaryHunk,
+				Severity: SeverityWarning,
+				Patch:    name,
+				Message:  fmt.Sprintf("%s patch contains %d binary files", title(name), len(binary)),
+				Files:    binary,
+			})
+		}
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return report.Findings[i].Code < report.Findings[j].Code
+	})
+	return report
+}
+
+func parseForReport(report *Report, name string, data []byte) *Patch {
+	parsed, err := Parse(data)
+	switch {
+	case err == ErrEmptyPatch:
+		report.add(Finding{
+			Code:     FindingEmptyPatch,
+			Severity: SeverityError,
+			Patch:    name,
+			Message:  fmt.Sprintf("%s patch contains no file changes", title(name)),
+		})
+		return nil
+	case err != nil:
+		report.add(Finding{
+			Code:     FindingParseError,
+			Severity: SeverityError,
+			Patch:    name,
+			Message:  fmt.Sprintf("Failed to parse %s patch: %v", name, err),
+		})
+		return nil
+	}
+	return parsed
+}
+
+func title(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func (r *Report) add(finding Finding) {
+	r.Findings = append(r.Findings, finding)
+}
+
+func (r *Report) Matching(codes map[string]bool) []Finding {
+	var matched []Finding
+	for _, finding := range r.Findings {
+		if codes[finding.Code] {
+			matched = append(matched, finding)
+		}
+	}
+	return matched
+}
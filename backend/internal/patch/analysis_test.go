@@ -0,0 +1,125 @@
+package patch
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	cases := []struct {
+		name     string
+		test     string
+		solution string
+		rules    Rules
+		want     map[string][]string
+	}{
+		{
+			name:     "clean patches",
+			test:     modifyPatch("tests/test_app.py", 3),
+			solution: modifyPatch("app.py", 3),
+			want:     map[string][]string{},
+		},
+		{
+			name:     "empty patches",
+			test:     "",
+			solution: "not a diff\n",
+			want:     map[string][]string{FindingEmptyPatch: nil},
+		},
+		{
+			name:     "unparseable patch",
+			test:     modifyPatch("tests/test_app.py", 1),
+			solution: "diff --git a/app.py b/app.py\n" + strings.Repeat("x", 17<<20) + "\n",
+			want:     map[string][]string{FindingParseError: nil},
+		},
+		{
+			name:     "solution touches tests",
+			test:     modifyPatch("tests/test_app.py", 1),
+			solution: modifyPatch("app.py", 1) + modifyPatch("tests/test_other.py", 1),
+			want:     map[string][]string{FindingSolutionTouchesTests: {"tests/test_other.py"}},
+		},
+		{
+			name:     "test patch touches non-tests",
+			test:     modifyPatch("tests/test_app.py", 1) + modifyPatch("setup.cfg", 1),
+			solution: modifyPatch("app.py", 1),
+			want:     map[string][]string{FindingTestTouchesNonTests: {"setup.cfg"}},
+		},
+		{
+			name:     "patches overlap",
+			test:     modifyPatch("tests/test_app.py", 1) + modifyPatch("conftest.py", 1),
+			solution: modifyPatch("conftest.py", 1),
+			want: map[string][]string{
+				FindingTestTouchesNonTests: {"conftest.py"},
+				FindingPatchesOverlap:      {"conftest.py"},
+			},
+		},
+		{
+			name:     "binary hunks",
+			test:     modifyPatch("tests/test_app.py", 1),
+			solution: modifyPatch("app.py", 1) + "diff --git a/logo.png b/logo.png\nBinary files a/logo.png and b/logo.png differ\n",
+			want:     map[string][]string{FindingBinaryHunk: {"logo.png"}},
+		},
+		{
+			name:     "solution over the line limit",
+			test:     modifyPatch("tests/test_app.py", 1),
+			solution: modifyPatch("app.py", 6),
+			rules:    Rules{MaxSolutionLines: 10},
+			want:     map[string][]string{FindingSolutionTooLarge: nil},
+		},
+		{
+			name:     "solution at the line limit",
+			test:     modifyPatch("tests/test_app.py", 1),
+			solution: modifyPatch("app.py", 5),
+			rules:    Rules{MaxSolutionLines: 10},
+			want:     map[string][]string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			report := Analyze([]byte(tc.test), []byte(tc.solution), tc.rules)
+
+			got := make(map[string][]string, len(report.Findings))
+			for _, finding := range report.Findings {
+				if _, seen := got[finding.Code]; seen && finding.Code != FindingEmptyPatch {
+					t.Errorf("duplicate %s finding", finding.Code)
+				}
+				got[finding.Code] = finding.Files
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("findings = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeSortsFindingsAndMatches(t *testing.T) {
+	report := Analyze(nil, []byte(modifyPatch("tests/test_app.py", 1)), Rules{})
+
+	codes := make([]string, 0, len(report.Findings))
+	for _, finding := range report.Findings {
+		codes = append(codes, finding.Code)
+	}
+	want := []string{FindingEmptyPatch, FindingSolutionTouchesTests}
+	if !reflect.DeepEqual(codes, want) {
+		t.Fatalf("codes = %v, want %v", codes, want)
+	}
+
+	matched := report.Matching(map[string]bool{FindingSolutionTouchesTests: true})
+	if len(matched) != 1 || matched[0].Severity != SeverityError || matched[0].Patch != "solution" {
+		t.Fatalf("Matching = %+v", matched)
+	}
+}
+
+func modifyPatch(filePath string, changes int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "diff --git a/%[1]s b/%[1]s\n--- a/%[1]s\n+++ b/%[1]s\n@@ -1,%[2]d +1,%[2]d @@\n", filePath, changes)
+	for i := 0; i < changes; i++ {
+		fmt.Fprintf(&b, "-old %d\n", i)
+	}
+	for i := 0; i < changes; i++ {
+		fmt.Fprintf(&b, "+new %d\n", i)
+	}
+	return b.String()
+}
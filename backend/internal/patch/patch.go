@@ -0,0 +1,195 @@
+package patch
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var ErrEmptyPatch = errors.New("patch contains no file changes")
+
+type File struct {
+	OldPath   string `json:"oldPath,omitempty"`
+	NewPath   string `json:"newPath,omitempty"`
+	Added     int    `json:"added"`
+	Removed   int    `json:"removed"`
+	Hunks     int    `json:"hunks"`
+	Binary    bool   `json:"binary"`
+	IsNew     bool   `json:"isNew,omitempty"`
+	IsDeleted bool   `json:"isDeleted,omitempty"`
+	IsRename  bool   `json:"isRename,omitempty"`
+	IsTest    bool   `json:"isTest"`
+}
+
+func (f File) Path() string {
+	if f.NewPath != "" {
+		return f.NewPath
+	}
+	return f.OldPath
+}
+
+type Patch struct {
+	Files   []File `json:"files"`
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+}
+
+var (
+	diffGitHeader = regexp.MustCompile(`^diff --git (?:"?a/)?(.+?)"? (?:"?b/)?(.+?)"?$`)
+	hunkHeader    = regexp.MustCompile(`^@@ -\d+(?:,(\d+))? \+\d+(?:,(\d+))? @@`)
+)
+
+func Parse(data []byte) (*Patch, error) {
+	result := &Patch{}
+	var current *File
+	oldRemaining, newRemaining := 0, 0
+
+	flush := func() {
+		if current != nil {
+			current.IsTest = IsTestFile(current.Path())
+			result.Files = append(result.Files, *current)
+			result.Added += current.Added
+			result.Removed += current.Removed
+		}
+		current = nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+
+		if oldRemaining > 0 || newRemaining > 0 {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				current.Added++
+				newRemaining--
+				continue
+			case strings.HasPrefix(line, "-"):
+				current.Removed++
+				oldRemaining--
+				continue
+			case strings.HasPrefix(line, " "), line == "":
+				oldRemaining--
+				newRemaining--
+				continue
+			case strings.HasPrefix(line, `\`):
+				continue
+			}
+			oldRemaining, newRemaining = 0, 0
+		}
+
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flush()
+			current = &File{}
+			if match := diffGitHeader.FindStringSubmatch(line); match != nil {
+				current.OldPath, current.NewPath = match[1], match[2]
+			}
+		case strings.HasPrefix(line, "--- "):
+			if current == nil || current.Hunks > 0 {
+				flush()
+				current = &File{}
+			}
+			current.OldPath = stripPrefix(line[4:], "a/")
+		case strings.HasPrefix(line, "+++ "):
+			if current == nil {
+				current = &File{}
+			}
+			current.NewPath = stripPrefix(line[4:], "b/")
+		case current == nil:
+			continue
+		case strings.HasPrefix(line, "new file mode"):
+			current.IsNew = true
+		case strings.HasPrefix(line, "deleted file mode"):
+			current.IsDeleted = true
+		case strings.HasPrefix(line, "rename from "):
+			current.IsRename = true
+			current.OldPath = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "rename to "):
+			current.IsRename = true
+			current.NewPath = strings.TrimPrefix(line, "rename to ")
+		case strings.HasPrefix(line, "Binary files "), line == "GIT binary patch":
+			current.Binary = true
+		case strings.HasPrefix(line, "@@"):
+			match := hunkHeader.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			current.Hunks++
+			oldRemaining, newRemaining = hunkLength(match[1]), hunkLength(match[2])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	if len(result.Files) == 0 {
+		return result, ErrEmptyPatch
+	}
+	return result, nil
+}
+
+func (p *Patch) Paths() []string {
+	paths := make([]string, 0, len(p.Files))
+	for _, f := range p.Files {
+		paths = append(paths, f.Path())
+	}
+	return paths
+}
+
+func stripPrefix(value, prefix string) string {
+	if i := strings.IndexByte(value, '\t'); i >= 0 {
+		value = value[:i]
+	}
+	value = strings.Trim(value, `"`)
+	if value == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(value, prefix)
+}
+
+func hunkLength(value string) int {
+	if value == "" {
+		return 1
+	}
+	n := 0
+	for _, r := range value {
+		n = n*10 + int(r-'0')
+	}
+	return n
+}
+
+var testDirectories = map[string]bool{
+	"test": true, "tests": true, "__tests__": true, "spec": true, "specs": true,
+	"testing": true, "testdata": true, "e2e": true, "fixtures": true,
+}
+
+func IsTestFile(filePath string) bool {
+	original := path.Base(filePath)
+	originalName := strings.TrimSuffix(original, path.Ext(original))
+	if strings.HasSuffix(originalName, "Test") || strings.HasSuffix(originalName, "Tests") {
+		return true
+	}
+
+	filePath = strings.ToLower(filePath)
+	for _, dir := range strings.Split(path.Dir(filePath), "/") {
+		if testDirectories[dir] {
+			return true
+		}
+	}
+
+	base := path.Base(filePath)
+	name := strings.TrimSuffix(base, path.Ext(base))
+	return name == "test" ||
+		strings.HasPrefix(base, "test_") ||
+		strings.HasSuffix(name, "_test") ||
+		strings.HasSuffix(name, "_spec") ||
+		strings.HasSuffix(name, ".test") ||
+		strings.HasSuffix(name, ".spec")
+}
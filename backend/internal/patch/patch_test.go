@@ -0,0 +1,179 @@
+package patch
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name  string
+		patch string
+		want  []File
+	}{
+		{
+			name: "modified file",
+			patch: `diff --git a/src/app.go b/src/app.go
+index 1111111..2222222 100644
+--- a/src/app.go
++++ b/src/app.go
+@@ -1,3 +1,4 @@
+ package app
+-var a = 1
++var a = 2
++var b = 3
+
+`,
+			want: []File{{OldPath: "src/app.go", NewPath: "src/app.go", Added: 2, Removed: 1, Hunks: 1}},
+		},
+		{
+			name: "rename without content changes",
+			patch: `diff --git a/old/name.go b/new/name.go
+similarity index 100%
+rename from old/name.go
+rename to new/name.go
+`,
+			want: []File{{OldPath: "old/name.go", NewPath: "new/name.go", IsRename: true}},
+		},
+		{
+			name: "new and deleted files",
+			patch: `diff --git a/tests/new_test.py b/tests/new_test.py
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/tests/new_test.py
+@@ -0,0 +1,2 @@
++def test_one():
++    assert True
+diff --git a/legacy.py b/legacy.py
+deleted file mode 100644
+index 4444444..0000000
+--- a/legacy.py
++++ /dev/null
+@@ -1 +0,0 @@
+-print("bye")
+`,
+			want: []File{
+				{NewPath: "tests/new_test.py", Added: 2, Hunks: 1, IsNew: true, IsTest: true},
+				{OldPath: "legacy.py", Removed: 1, Hunks: 1, IsDeleted: true},
+			},
+		},
+		{
+			name: "binary files",
+			patch: `diff --git a/logo.png b/logo.png
+index 5555555..6666666 100644
+Binary files a/logo.png and b/logo.png differ
+diff --git a/font.woff b/font.woff
+new file mode 100644
+index 0000000..7777777
+GIT binary patch
+literal 4
+LcmZQzWMT#Y01f~L
+`,
+			want: []File{
+				{OldPath: "logo.png", NewPath: "logo.png", Binary: true},
+				{OldPath: "font.woff", NewPath: "font.woff", Binary: true, IsNew: true},
+			},
+		},
+		{
+			name: "hunk lines that look like file headers",
+			patch: `diff --git a/notes.md b/notes.md
+--- a/notes.md
++++ b/notes.md
+@@ -1,3 +1,3 @@
+ # Notes
+---- a/removed.txt
+++++ b/added.txt
+ end
+\ No newline at end of file
+diff --git a/other.md b/other.md
+--- a/other.md
++++ b/other.md
+@@ -1 +1 @@
+-old
++new
+`,
+			want: []File{
+				{OldPath: "notes.md", NewPath: "notes.md", Added: 1, Removed: 1, Hunks: 1},
+				{OldPath: "other.md", NewPath: "other.md", Added: 1, Removed: 1, Hunks: 1},
+			},
+		},
+		{
+			name: "plain unified diff without git headers",
+			patch: `--- a/one.txt	2026-01-01 00:00:00
++++ b/one.txt	2026-01-02 00:00:00
+@@ -1,2 +1,2 @@
+-a
++b
+ c
+--- a/two.txt
++++ b/two.txt
+@@ -1 +1,2 @@
+ x
++y
+`,
+			want: []File{
+				{OldPath: "one.txt", NewPath: "one.txt", Added: 1, Removed: 1, Hunks: 1},
+				{OldPath: "two.txt", NewPath: "two.txt", Added: 1, Hunks: 1},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			parsed, err := Parse([]byte(tc.patch))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if !reflect.DeepEqual(parsed.Files, tc.want) {
+				t.Fatalf("files = %+v\nwant %+v", parsed.Files, tc.want)
+			}
+			added, removed := 0, 0
+			for _, f := range tc.want {
+				added += f.Added
+				removed += f.Removed
+			}
+			if parsed.Added != added || parsed.Removed != removed {
+				t.Errorf("totals = +%d -%d, want +%d -%d", parsed.Added, parsed.Removed, added, removed)
+			}
+		})
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	for _, input := range []string{"", "just some text\nwithout a diff\n"} {
+		if _, err := Parse([]byte(input)); !errors.Is(err, ErrEmptyPatch) {
+			t.Errorf("Parse(%q) error = %v, want ErrEmptyPatch", input, err)
+		}
+	}
+}
+
+func TestIsTestFile(t *testing.T) {
+	cases := map[string]bool{
+		"pkg/server_test.go":           true,
+		"tests/helpers.py":             true,
+		"src/__tests__/App.jsx":        true,
+		"spec/models/user_spec.rb":     true,
+		"test_parser.py":               true,
+		"web/button.test.tsx":          true,
+		"web/button.spec.ts":           true,
+		"src/main/java/FooTest.java":   true,
+		"src/main/java/FooTests.kt":    true,
+		"internal/testdata/input.json": true,
+		"e2e/login.ts":                 true,
+		"Tests/Unit/Helper.cs":         true,
+		"test.sh":                      true,
+		"src/app.go":                   false,
+		"src/contest/score.go":         false,
+		"docs/testing-guide.md":        false,
+		"src/attestation.py":           false,
+		"src/main/java/Testable.java":  false,
+		"lib/latest.js":                false,
+	}
+	for filePath, want := range cases {
+		if got := IsTestFile(filePath); got != want {
+			t.Errorf("IsTestFile(%q) = %v, want %v", filePath, got, want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AssignmentSchedulerConfig struct {
+	Interval time.Duration
+}
+
+var assignmentKick chan struct{}
+
+func LoadAssignmentSchedulerConfig() AssignmentSchedulerConfig {
+	return AssignmentSchedulerConfig{
+		Interval: envDuration("ASSIGNMENT_SCHEDULER_INTERVAL", time.Minute),
+	}
+}
+
+func StartAssignmentScheduler(config AssignmentSchedulerConfig) {
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+	assignmentKick = make(chan struct{}, 1)
+
+	go func() {
+		ticker := time.NewTicker(config.Interval)
+		defer ticker.Stop()
+
+		for {
+			RunAssignmentCycle()
+
+			select {
+			case <-ticker.C:
+			case <-assignmentKick:
+			}
+		}
+	}()
+}
+
+func KickAssignmentScheduler() {
+	if assignmentKick == nil {
+		return
+	}
+	select {
+	case assignmentKick <- struct{}{}:
+	default:
+	}
+}
+
+func RunAssignmentCycle() {
+	queued, err := AssignQueuedTasks()
+	if err != nil {
+		log.Printf("Assignment scheduler failed to assign queued tasks: %v", err)
+	}
+	testing, err := ReassignPendingProjectVTasks()
+	if err != nil {
+		log.Printf("Assignment scheduler failed to assign Project V testers: %v", err)
+	}
+	reviews, err := AssignPendingReviews()
+	if err != nil {
+		log.Printf("Assignment scheduler failed to assign Project V reviewers: %v", err)
+	}
+
+	if queued+testing+reviews > 0 {
+		log.Printf("Assignment scheduler assigned %d queued tasks, %d Project V tests, %d Project V reviews", queued, testing, reviews)
+	}
+}
+
+func AssignPendingReviews() (int, error) {
+	assignedCount := 0
+	var skipped []uuid.UUID
+
+	for {
+		var submission models.ProjectVSubmission
+		var reviewerID *uuid.UUID
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := lockAssignmentPipeline(tx, models.PipelineProjectVReview); err != nil {
+				return err
+			}
+
+			query := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Select("id", "title").
+				Where("status = ? AND reviewer_id IS NULL", models.ProjectVStatusPendingReview)
+			if len(skipped) > 0 {
+				query = query.Where("id NOT IN ?", skipped)
+			}
+			err := query.Order("updated_at ASC").Take(&submission).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			reviewerID, err = AutoAssignReviewer(tx, submission.ID)
+			if err != nil || reviewerID == nil {
+				return err
+			}
+
+			return tx.Model(&models.ProjectVSubmission{}).
+				Where("id = ?", submission.ID).
+				UpdateColumn("reviewer_id", *reviewerID).Error
+		})
+		if err != nil {
+			return assignedCount, err
+		}
+		if submission.ID == uuid.Nil {
+			return assignedCount, nil
+		}
+		if reviewerID == nil {
+			skipped = append(skipped, submission.ID)
+			continue
+		}
+		assignedCount++
+
+		if err := SendTaskAssignedEmail(*reviewerID, models.PipelineProjectVReview, submission.Title, ""); err != nil {
+			log.Printf("Failed to email assignment of %s: %v", submission.ID, err)
+		}
+	}
+}
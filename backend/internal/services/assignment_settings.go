@@ -0,0 +1,292 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adzzatxperts/backend/internal/assignment"
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrUnknownPipeline = errors.New("unknown assignment pipeline")
+
+type assignmentPipeline struct {
+	role     models.UserRole
+	model    interface{}
+	table    string
+	subject  string
+	column   string
+	statuses []string
+	strategy string
+}
+
+var assignmentPipelines = map[string]assignmentPipeline{
+	models.PipelineProjectX: {
+		role:    models.RoleTester,
+		model:   &models.Submission{},
+		table:   "submissions",
+		subject: models.TransitionSubjectSubmission,
+		column:  "claimed_by_id",
+		statuses: []string{
+			string(models.StatusPending),
+			string(models.StatusClaimed),
+			string(models.StatusEligible),
+		},
+		strategy: assignment.StrategyLeastLoaded,
+	},
+	models.PipelineProjectVTesting: {
+		role:    models.RoleTester,
+		model:   &models.ProjectVSubmission{},
+		table:   "project_v_submissions",
+		subject: models.TransitionSubjectProjectV,
+		column:  "tester_id",
+		statuses: []string{
+			string(models.ProjectVStatusInTesting),
+			string(models.ProjectVStatusTaskSubmittedToPlatform),
+			string(models.ProjectVStatusEligible),
+			string(models.ProjectVStatusRework),
+			string(models.ProjectVStatusReworkDone),
+		},
+		strategy: assignment.StrategySkillMatch,
+	},
+	models.PipelineProjectVReview: {
+		role:    models.RoleReviewer,
+		model:   &models.ProjectVSubmission{},
+		table:   "project_v_submissions",
+		subject: models.TransitionSubjectProjectV,
+		column:  "reviewer_id",
+		statuses: []string{
+			string(models.ProjectVStatusPendingReview),
+			string(models.ProjectVStatusChangesRequested),
+			string(models.ProjectVStatusChangesDone),
+			string(models.ProjectVStatusFinalChecks),
+		},
+		strategy: assignment.StrategySkillMatch,
+	},
+}
+
+type AssignmentSettingView struct {
+	Pipeline    string          `json:"pipeline"`
+	Strategy    string          `json:"strategy"`
+	Params      json.RawMessage `json:"params"`
+	IsDefault   bool            `json:"isDefault"`
+	UpdatedByID *uuid.UUID      `json:"updatedById,omitempty"`
+	UpdatedAt   *time.Time      `json:"updatedAt,omitempty"`
+}
+
+func GetAssignmentSettings() ([]AssignmentSettingView, error) {
+	var stored []models.AssignmentSetting
+	if err := database.DB.Find(&stored).Error; err != nil {
+		return nil, err
+	}
+	byPipeline := make(map[string]*models.AssignmentSetting, len(stored))
+	for i := range stored {
+		byPipeline[stored[i].Pipeline] = &stored[i]
+	}
+
+	views := make([]AssignmentSettingView, 0, len(models.Pipelines))
+	for _, pipeline := range models.Pipelines {
+		if setting, ok := byPipeline[pipeline]; ok {
+			views = append(views, settingView(setting))
+			continue
+		}
+		views = append(views, AssignmentSettingView{
+			Pipeline:  pipeline,
+			Strategy:  assignmentPipelines[pipeline].strategy,
+			Params:    json.RawMessage("{}"),
+			IsDefault: true,
+		})
+	}
+	return views, nil
+}
+
+func UpdateAssignmentSetting(pipeline, strategy string, params json.RawMessage, updatedBy *uuid.UUID) (*AssignmentSettingView, error) {
+	if _, ok := assignmentPipelines[pipeline]; !ok {
+		return nil, ErrUnknownPipeline
+	}
+	if len(params) == 0 || string(params) == "null" {
+		params = json.RawMessage("{}")
+	}
+	if _, err := assignment.New(strategy, params); err != nil {
+		return nil, err
+	}
+
+	paramsJSON := string(params)
+	setting := models.AssignmentSetting{
+		Pipeline:    pipeline,
+		Strategy:    strategy,
+		Params:      &paramsJSON,
+		UpdatedByID: updatedBy,
+	}
+	err := database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "pipeline"}},
+		DoUpdates: clause.AssignmentColumns([]string{"strategy", "params", "updated_by_id", "updated_at"}),
+	}).Create(&setting).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if err := database.DB.Where("pipeline = ?", pipeline).First(&setting).Error; err != nil {
+		return nil, err
+	}
+	view := settingView(&setting)
+	return &view, nil
+}
+
+func settingView(setting *models.AssignmentSetting) AssignmentSettingView {
+	view := AssignmentSettingView{
+		Pipeline:    setting.Pipeline,
+		Strategy:    setting.Strategy,
+		Params:      json.RawMessage("{}"),
+		UpdatedByID: setting.UpdatedByID,
+		UpdatedAt:   &setting.UpdatedAt,
+	}
+	if setting.Params != nil {
+		view.Params = json.RawMessage(*setting.Params)
+	}
+	return view
+}
+
+func loadAssignmentStrategy(tx *gorm.DB, pipeline string) (assignment.Strategy, *uuid.UUID, error) {
+	config, ok := assignmentPipelines[pipeline]
+	if !ok {
+		return nil, nil, ErrUnknownPipeline
+	}
+
+	var setting models.AssignmentSetting
+	err := tx.Where("pipeline = ?", pipeline).First(&setting).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		strategy, err := assignment.New(config.strategy, nil)
+		return strategy, nil, err
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var params json.RawMessage
+	if setting.Params != nil {
+		params = json.RawMessage(*setting.Params)
+	}
+	strategy, err := assignment.New(setting.Strategy, params)
+	if err != nil {
+		return nil, nil, fmt.Errorf("pipeline %s: %w", pipeline, err)
+	}
+	return strategy, setting.LastAssignee, nil
+}
+
+func pipelineLoads(tx *gorm.DB, config assignmentPipeline, userIDs []uuid.UUID) map[uuid.UUID]int64 {
+	var rows []struct {
+		UserID uuid.UUID
+		Count  int64
+	}
+	tx.Model(config.model).
+		Select(config.column+" AS user_id, COUNT(*) AS count").
+		Where(config.column+" IN ? AND status IN ?", userIDs, config.statuses).
+		Group(config.column).
+		Scan(&rows)
+
+	loads := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		loads[row.UserID] = row.Count
+	}
+	return loads
+}
+
+func activeLoads(tx *gorm.DB, userIDs []uuid.UUID, except string) map[uuid.UUID]int64 {
+	loads := make(map[uuid.UUID]int64, len(userIDs))
+	for name, config := range assignmentPipelines {
+		if name == except {
+			continue
+		}
+		for userID, count := range pipelineLoads(tx, config, userIDs) {
+			loads[userID] += count
+		}
+	}
+	return loads
+}
+
+func lockAssignmentPipeline(tx *gorm.DB, pipeline string) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "assignment:"+pipeline).Error
+}
+
+func SelectAssignee(tx *gorm.DB, pipeline string, task assignment.Task, exclude ...uuid.UUID) (*assignment.Decision, error) {
+	config, ok := assignmentPipelines[pipeline]
+	if !ok {
+		return nil, ErrUnknownPipeline
+	}
+	if err := lockAssignmentPipeline(tx, pipeline); err != nil {
+		return nil, err
+	}
+
+	var users []models.User
+	query := tx.Where("role = ? AND is_approved = ? AND is_green_light = ?", config.role, true, true)
+	if len(exclude) > 0 {
+		query = query.Where("id NOT IN ?", exclude)
+	}
+	if err := query.Find(&users).Error; err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	loads := activeLoads(tx, ids, "")
+	profiles := loadSkillProfiles(tx, ids)
+	availability := loadAvailability(tx, ids, time.Now())
+
+	candidates := make([]assignment.Candidate, 0, len(users))
+	for _, user := range users {
+		state := availability[user.ID]
+		if !state.available || (state.capacity > 0 && loads[user.ID] >= int64(state.capacity)) {
+			continue
+		}
+		candidate := assignment.Candidate{
+			UserID:      user.ID,
+			Name:        user.Name,
+			ActiveTasks: loads[user.ID],
+			Capacity:    state.capacity,
+		}
+		if skills, ok := profiles[user.ID]; ok {
+			candidate.HasProfile = true
+			candidate.Languages = skills.Languages
+			candidate.Categories = skills.Categories
+			candidate.MaxDifficulty = skills.MaxDifficulty
+		}
+		candidates = append(candidates, candidate)
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	strategy, lastAssignee, err := loadAssignmentStrategy(tx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	decision, err := strategy.Select(assignment.Request{Task: task, Candidates: candidates, LastAssigned: lastAssignee})
+	if errors.Is(err, assignment.ErrNoCandidate) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Model(&models.AssignmentSetting{}).
+		Where("pipeline = ?", pipeline).
+		UpdateColumn("last_assignee", decision.Candidate.UserID).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return decision, nil
+}
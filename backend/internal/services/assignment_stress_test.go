@@ -0,0 +1,323 @@
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/adzzatxperts/backend/internal/workflow"
+	"github.com/google/uuid"
+)
+
+const (
+	stressTesters  = 6
+	stressCapacity = 3
+	stressTasks    = 40
+	stressWorkers  = 16
+)
+
+type stressFixtures struct {
+	run         string
+	contributor models.User
+	admin       models.User
+	testers     []models.User
+	tasks       []uuid.UUID
+	projectV    []uuid.UUID
+}
+
+func TestAssignmentUnderConcurrency(t *testing.T) {
+	setupTestDB(t)
+	RegisterWorkflowEffects()
+
+	var existing int64
+	database.DB.Model(&models.User{}).
+		Where("role IN ? AND is_approved = ? AND is_green_light = ?",
+			[]models.UserRole{models.RoleTester, models.RoleReviewer}, true, true).
+		Count(&existing)
+	if existing > 0 {
+		t.Fatalf("found %d approved testers or reviewers, the assignment stress test needs a disposable database", existing)
+	}
+
+	f := setupStressFixtures(t)
+	expected := stressTasks
+	if total := stressTesters * stressCapacity; total < expected {
+		expected = total
+	}
+
+	t.Run("auto assignment", func(t *testing.T) {
+		runConcurrently(stressWorkers, func(worker int) {
+			if worker%4 == 0 {
+				if _, err := AssignQueuedTasks(); err != nil {
+					t.Logf("worker %d: assign queued: %v", worker, err)
+				}
+				return
+			}
+			for _, id := range shuffled(f.tasks, int64(worker)) {
+				if _, err := AutoAssignSubmission(id); err != nil {
+					t.Logf("worker %d: auto-assign %s: %v", worker, id, err)
+				}
+			}
+		})
+
+		checkProjectXAssignments(t, f)
+
+		var claims []struct {
+			SubjectID uuid.UUID
+			Count     int64
+		}
+		database.DB.Model(&models.StatusTransition{}).
+			Select("subject_id, COUNT(*) AS count").
+			Where("subject_id IN ? AND to_status = ?", f.tasks, models.StatusClaimed).
+			Group("subject_id").
+			Having("COUNT(*) > 1").
+			Scan(&claims)
+		for _, claim := range claims {
+			t.Errorf("task %s was assigned %d times", claim.SubjectID, claim.Count)
+		}
+
+		var assigned int64
+		database.DB.Model(&models.Submission{}).Where("id IN ? AND status = ?", f.tasks, models.StatusClaimed).Count(&assigned)
+		if assigned != int64(expected) {
+			t.Errorf("expected %d assigned tasks, found %d", expected, assigned)
+		}
+	})
+
+	t.Run("redistribution", func(t *testing.T) {
+		runConcurrently(stressWorkers, func(worker int) {
+			switch worker % 3 {
+			case 0:
+				if _, err := RedistributeTasks(); err != nil {
+					t.Logf("worker %d: redistribute: %v", worker, err)
+				}
+			case 1:
+				for _, id := range shuffled(f.tasks, int64(worker))[:len(f.tasks)/4] {
+					ClaimSubmission(id, StatusChange{
+						Action:    "MANUAL_CLAIM",
+						ActorID:   &f.admin.ID,
+						ActorName: f.admin.Email,
+						ActorRole: string(models.RoleAdmin),
+					})
+				}
+			default:
+				if _, err := AssignQueuedTasks(); err != nil {
+					t.Logf("worker %d: assign queued: %v", worker, err)
+				}
+			}
+		})
+
+		checkProjectXAssignments(t, f)
+
+		var transitions []models.StatusTransition
+		database.DB.Where("subject_id IN ?", f.tasks).Order("created_at ASC, id ASC").Find(&transitions)
+		last := make(map[uuid.UUID]string, len(f.tasks))
+		for _, transition := range transitions {
+			from := ""
+			if transition.FromStatus != nil {
+				from = *transition.FromStatus
+			}
+			if previous, ok := last[transition.SubjectID]; ok && previous != from {
+				t.Errorf("task %s moved %s -> %s but was %s (lost update)", transition.SubjectID, from, transition.ToStatus, previous)
+			}
+			last[transition.SubjectID] = transition.ToStatus
+		}
+
+		var tasks []models.Submission
+		database.DB.Select("id", "status").Where("id IN ?", f.tasks).Find(&tasks)
+		for _, task := range tasks {
+			if last[task.ID] != string(task.Status) {
+				t.Errorf("task %s is %s but its history ends at %s", task.ID, task.Status, last[task.ID])
+			}
+		}
+	})
+
+	t.Run("project v testers", func(t *testing.T) {
+		testerIDs := make([]uuid.UUID, 0, len(f.testers))
+		for _, tester := range f.testers {
+			testerIDs = append(testerIDs, tester.ID)
+		}
+		database.DB.Model(&models.Submission{}).
+			Where("id IN ? AND claimed_by_id IN ?", f.tasks, testerIDs[:stressTesters/2]).
+			Updates(map[string]interface{}{"claimed_by_id": nil, "assigned_at": nil, "status": models.StatusPending})
+
+		var claimed int64
+		database.DB.Model(&models.Submission{}).Where("id IN ? AND claimed_by_id IN ?", f.tasks, testerIDs).Count(&claimed)
+		expected := stressTesters*stressCapacity - int(claimed)
+		if expected > len(f.projectV) {
+			expected = len(f.projectV)
+		}
+
+		runConcurrently(stressWorkers, func(worker int) {
+			if worker%2 == 0 {
+				if _, err := ReassignPendingProjectVTasks(); err != nil {
+					t.Logf("worker %d: reassign pending: %v", worker, err)
+				}
+				return
+			}
+			for _, id := range shuffled(f.projectV, int64(worker)) {
+				var submission models.ProjectVSubmission
+				if err := database.DB.First(&submission, id).Error; err != nil {
+					continue
+				}
+				workflow.Apply(&submission, workflow.ActionStartTesting, workflow.SystemActor, workflow.Params{})
+			}
+		})
+
+		var starts []struct {
+			SubjectID uuid.UUID
+			Count     int64
+		}
+		database.DB.Model(&models.StatusTransition{}).
+			Select("subject_id, COUNT(*) AS count").
+			Where("subject_id IN ? AND action = ?", f.projectV, workflow.ActionStartTesting).
+			Group("subject_id").
+			Having("COUNT(*) > 1").
+			Scan(&starts)
+		for _, start := range starts {
+			t.Errorf("Project V task %s started testing %d times", start.SubjectID, start.Count)
+		}
+
+		var loads []struct {
+			TesterID uuid.UUID
+			Count    int64
+		}
+		database.DB.Model(&models.ProjectVSubmission{}).
+			Select("tester_id, COUNT(*) AS count").
+			Where("id IN ? AND tester_id IS NOT NULL", f.projectV).
+			Group("tester_id").
+			Scan(&loads)
+		var inTesting int64
+		for _, load := range loads {
+			inTesting += load.Count
+			var projectX int64
+			database.DB.Model(&models.Submission{}).Where("id IN ? AND claimed_by_id = ?", f.tasks, load.TesterID).Count(&projectX)
+			if load.Count+projectX > stressCapacity {
+				t.Errorf("tester %s holds %d Project V and %d Project X tasks, capacity is %d", load.TesterID, load.Count, projectX, stressCapacity)
+			}
+		}
+
+		var orphaned int64
+		database.DB.Model(&models.ProjectVSubmission{}).
+			Where("id IN ? AND status = ? AND tester_id IS NULL", f.projectV, models.ProjectVStatusInTesting).
+			Count(&orphaned)
+		if orphaned > 0 {
+			t.Errorf("%d Project V tasks are in testing without a tester", orphaned)
+		}
+
+		if inTesting != int64(expected) {
+			t.Errorf("expected %d Project V tasks in testing, found %d", expected, inTesting)
+		}
+	})
+}
+
+func setupStressFixtures(t *testing.T) *stressFixtures {
+	t.Helper()
+
+	f := &stressFixtures{run: uuid.NewString()[:8]}
+	f.contributor = createTestUser(t, models.RoleContributor, "contributor")
+	f.admin = createTestUser(t, models.RoleAdmin, "admin")
+	for i := 0; i < stressTesters; i++ {
+		tester := createTestUser(t, models.RoleTester, fmt.Sprintf("tester-%d", i))
+		limit := stressCapacity
+		if _, err := SaveAvailability(tester.ID, AvailabilityInput{MaxConcurrentTasks: &limit}); err != nil {
+			t.Fatalf("failed to set tester capacity: %v", err)
+		}
+		f.testers = append(f.testers, tester)
+	}
+	t.Cleanup(func() {
+		subjects := append(append([]uuid.UUID{}, f.tasks...), f.projectV...)
+		if len(subjects) > 0 {
+			database.DB.Where("subject_id IN ?", subjects).Delete(&models.StatusTransition{})
+			database.DB.Where("target_id IN ?", subjects).Delete(&models.ActivityLog{})
+			database.DB.Where("id IN ?", f.tasks).Delete(&models.Submission{})
+			database.DB.Where("id IN ?", f.projectV).Delete(&models.ProjectVSubmission{})
+		}
+	})
+
+	for i := 0; i < stressTasks; i++ {
+		task := models.Submission{
+			Title:         fmt.Sprintf("stress %s task %d", f.run, i),
+			Domain:        "stress",
+			Language:      "go",
+			FileURL:       "-",
+			FileName:      "-",
+			Status:        models.StatusPending,
+			ContributorID: f.contributor.ID,
+		}
+		if err := database.DB.Create(&task).Error; err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+		RecordStatusTransition(SystemStatusChange(models.TransitionSubjectSubmission, task.ID,
+			"", string(models.StatusPending), "stress fixture"))
+		f.tasks = append(f.tasks, task.ID)
+
+		submission := models.ProjectVSubmission{
+			Title:            fmt.Sprintf("stress %s project v %d", f.run, i),
+			Language:         "go",
+			Category:         "stress",
+			Difficulty:       models.DifficultyEasy,
+			Description:      "-",
+			GithubRepo:       "https://example.test/stress/" + f.run,
+			CommitHash:       fmt.Sprintf("%040x", i),
+			TestPatchURL:     "-",
+			DockerfileURL:    "-",
+			SolutionPatchURL: "-",
+			Status:           models.ProjectVStatusSubmitted,
+			ContributorID:    f.contributor.ID,
+		}
+		if err := database.DB.Create(&submission).Error; err != nil {
+			t.Fatalf("failed to create Project V task: %v", err)
+		}
+		f.projectV = append(f.projectV, submission.ID)
+	}
+	return f
+}
+
+func checkProjectXAssignments(t *testing.T, f *stressFixtures) {
+	t.Helper()
+
+	var loads []struct {
+		ClaimedByID uuid.UUID
+		Count       int64
+	}
+	database.DB.Model(&models.Submission{}).
+		Select("claimed_by_id, COUNT(*) AS count").
+		Where("id IN ? AND claimed_by_id IS NOT NULL AND claimed_by_id <> ?", f.tasks, f.admin.ID).
+		Group("claimed_by_id").
+		Scan(&loads)
+	for _, load := range loads {
+		if load.Count > stressCapacity {
+			t.Errorf("tester %s holds %d tasks, capacity is %d", load.ClaimedByID, load.Count, stressCapacity)
+		}
+	}
+
+	var inconsistent int64
+	database.DB.Model(&models.Submission{}).
+		Where("id IN ? AND ((status = ? AND claimed_by_id IS NULL) OR (status = ? AND claimed_by_id IS NOT NULL))",
+			f.tasks, models.StatusClaimed, models.StatusPending).
+		Count(&inconsistent)
+	if inconsistent > 0 {
+		t.Errorf("%d tasks have a claimant that does not match their status", inconsistent)
+	}
+}
+
+func runConcurrently(n int, work func(worker int)) {
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			work(worker)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func shuffled(ids []uuid.UUID, seed int64) []uuid.UUID {
+	out := append([]uuid.UUID{}, ids...)
+	r := rand.New(rand.NewSource(seed))
+	r.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+	return out
+}
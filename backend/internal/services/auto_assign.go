@@ -1,435 +1,403 @@
 package services
 
 import (
+	"errors"
+	"log"
+	"strings"
 	"time"
 
+	"github.com/adzzatxperts/backend/internal/assignment"
 	"github.com/adzzatxperts/backend/internal/database"
 	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/adzzatxperts/backend/internal/workflow"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-func AutoAssignSubmission(submissionID uuid.UUID) (*uuid.UUID, error) {
-
-	var testers []models.User
-	err := database.DB.Where("role = ? AND is_approved = ? AND is_green_light = ?",
-		models.RoleTester, true, true).Find(&testers).Error
-	if err != nil {
-		return nil, err
-	}
-
-	if len(testers) == 0 {
-
-		return nil, nil
-	}
-
-	type TesterTaskCount struct {
-		TesterID uuid.UUID
-		Count    int64
-	}
-
-	var testerCounts []TesterTaskCount
-	for _, tester := range testers {
-		var count int64
-		database.DB.Model(&models.Submission{}).
-			Where("claimed_by_id = ? AND status IN ?", tester.ID, []string{
-				string(models.StatusPending),
-				string(models.StatusClaimed),
-				string(models.StatusEligible),
-			}).
-			Count(&count)
+var ErrSubmissionNotClaimable = errors.New("only pending or claimed tasks can be claimed")
 
-		testerCounts = append(testerCounts, TesterTaskCount{
-			TesterID: tester.ID,
-			Count:    count,
-		})
-	}
-
-	minCount := testerCounts[0].Count
-	selectedTesterID := testerCounts[0].TesterID
-
-	for _, rc := range testerCounts {
-		if rc.Count < minCount {
-			minCount = rc.Count
-			selectedTesterID = rc.TesterID
-		}
-	}
-
-	now := time.Now()
-	err = database.DB.Model(&models.Submission{}).
-		Where("id = ?", submissionID).
-		Updates(map[string]interface{}{
-			"claimed_by_id": selectedTesterID,
-			"assigned_at":   now,
-			"status":        models.StatusClaimed,
-		}).Error
+func AutoAssignSubmission(submissionID uuid.UUID) (*uuid.UUID, error) {
 
-	if err != nil {
+	submission, decision, err := assignQueuedSubmission(&submissionID, nil, "Auto-assigned: ")
+	if err != nil || decision == nil {
 		return nil, err
 	}
+	selectedTesterID := decision.Candidate.UserID
 
-	var submission models.Submission
-	database.DB.Preload("Contributor").First(&submission, submissionID)
-
-	var tester models.User
-	database.DB.First(&tester, selectedTesterID)
+	var contributor models.User
+	database.DB.Select("name").First(&contributor, submission.ContributorID)
 
 	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
 	userName := "System"
 	userRole := "SYSTEM"
 	targetType := "submission"
 
+	metadata := decision.Metadata()
+	metadata["testerId"] = selectedTesterID.String()
+	metadata["testerName"] = decision.Candidate.Name
+	metadata["contributorName"] = contributor.Name
+
 	LogActivity(LogActivityParams{
 		Action:      "AUTO_ASSIGN",
-		Description: "Task \"" + submission.Title + "\" auto-assigned to " + tester.Name,
+		Description: "Task \"" + submission.Title + "\" auto-assigned to " + decision.Candidate.Name,
 		UserID:      &userID,
 		UserName:    &userName,
 		UserRole:    &userRole,
 		TargetID:    &submissionID,
 		TargetType:  &targetType,
-		Metadata: map[string]interface{}{
-			"testerId":        selectedTesterID.String(),
-			"testerName":      tester.Name,
-			"contributorName": submission.Contributor.Name,
-		},
+		Metadata:    metadata,
 	})
 
+	if err := SendTaskAssignedEmail(selectedTesterID, models.PipelineProjectX, submission.Title, ""); err != nil {
+		log.Printf("Failed to email assignment of %s: %v", submission.ID, err)
+	}
+
 	return &selectedTesterID, nil
 }
 
 func AssignQueuedTasks() (int, error) {
 
-	var pendingSubmissions []models.Submission
-	err := database.DB.Where("status = ?", models.StatusPending).Order("created_at ASC").Find(&pendingSubmissions).Error
-	if err != nil {
-		return 0, err
-	}
-
-	if len(pendingSubmissions) == 0 {
-		return 0, nil
-	}
-
-	var testers []models.User
-	err = database.DB.Where("role = ? AND is_approved = ? AND is_green_light = ?",
-		models.RoleTester, true, true).Find(&testers).Error
-	if err != nil {
-		return 0, err
-	}
-
-	if len(testers) == 0 {
-		return 0, nil
-	}
-
-	testerTaskCounts := make(map[uuid.UUID]int64)
-	for _, tester := range testers {
-		var count int64
-		database.DB.Model(&models.Submission{}).
-			Where("claimed_by_id = ? AND status IN ?", tester.ID, []string{
-				string(models.StatusPending),
-				string(models.StatusClaimed),
-				string(models.StatusEligible),
-			}).
-			Count(&count)
-		testerTaskCounts[tester.ID] = count
-	}
-
 	assignedCount := 0
+	var skipped []uuid.UUID
 
-	for _, submission := range pendingSubmissions {
-
-		var selectedTesterID uuid.UUID
-		minCount := int64(-1)
-
-		for _, tester := range testers {
-			count := testerTaskCounts[tester.ID]
-			if minCount == -1 || count < minCount {
-				minCount = count
-				selectedTesterID = tester.ID
-			}
-		}
-
-		now := time.Now()
-		err = database.DB.Model(&models.Submission{}).
-			Where("id = ?", submission.ID).
-			Updates(map[string]interface{}{
-				"claimed_by_id": selectedTesterID,
-				"assigned_at":   now,
-				"status":        models.StatusClaimed,
-			}).Error
-
+	for {
+		submission, decision, err := assignQueuedSubmission(nil, skipped, "Queued task assigned: ")
 		if err != nil {
+			return assignedCount, err
+		}
+		if submission.ID == uuid.Nil {
+			break
+		}
+		if decision == nil {
+			skipped = append(skipped, submission.ID)
 			continue
 		}
+		selectedTesterID := decision.Candidate.UserID
 
-		testerTaskCounts[selectedTesterID]++
 		assignedCount++
 
-		var tester models.User
-		database.DB.First(&tester, selectedTesterID)
-
 		userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
 		userName := "System"
 		userRole := "SYSTEM"
 		targetType := "submission"
 
+		metadata := decision.Metadata()
+		metadata["testerId"] = selectedTesterID.String()
+		metadata["testerName"] = decision.Candidate.Name
+		metadata["wasQueued"] = true
+
 		LogActivity(LogActivityParams{
 			Action:      "AUTO_ASSIGN",
-			Description: "Queued task \"" + submission.Title + "\" assigned to " + tester.Name,
+			Description: "Queued task \"" + submission.Title + "\" assigned to " + decision.Candidate.Name,
 			UserID:      &userID,
 			UserName:    &userName,
 			UserRole:    &userRole,
 			TargetID:    &submission.ID,
 			TargetType:  &targetType,
-			Metadata: map[string]interface{}{
-				"testerId":   selectedTesterID.String(),
-				"testerName": tester.Name,
-				"wasQueued":  true,
-			},
+			Metadata:    metadata,
 		})
+
+		if err := SendTaskAssignedEmail(selectedTesterID, models.PipelineProjectX, submission.Title, ""); err != nil {
+			log.Printf("Failed to email assignment of %s: %v", submission.ID, err)
+		}
 	}
 
 	return assignedCount, nil
 }
 
-func RedistributeTasks() (int, error) {
+func assignQueuedSubmission(submissionID *uuid.UUID, skip []uuid.UUID, reason string) (*models.Submission, *assignment.Decision, error) {
+	var submission models.Submission
+	var decision *assignment.Decision
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockAssignmentPipeline(tx, models.PipelineProjectX); err != nil {
+			return err
+		}
+
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", models.StatusPending)
+		if submissionID != nil {
+			query = query.Where("id = ?", *submissionID)
+		}
+		if len(skip) > 0 {
+			query = query.Where("id NOT IN ?", skip)
+		}
+		err := query.Order("created_at ASC").Take(&submission).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		decision, err = SelectAssignee(tx, models.PipelineProjectX, projectXTask(&submission))
+		if err != nil || decision == nil {
+			return err
+		}
+
+		err = tx.Model(&models.Submission{}).
+			Where("id = ?", submission.ID).
+			Updates(map[string]interface{}{
+				"claimed_by_id": decision.Candidate.UserID,
+				"assigned_at":   time.Now(),
+				"status":        models.StatusClaimed,
+			}).Error
+		if err != nil {
+			return err
+		}
 
-	var testers []models.User
-	err := database.DB.Where("role = ? AND is_approved = ? AND is_green_light = ?",
-		models.RoleTester, true, true).Find(&testers).Error
+		return RecordStatusTransitionTx(tx, SystemStatusChange(models.TransitionSubjectSubmission, submission.ID,
+			string(submission.Status), string(models.StatusClaimed), reason+decision.Reason))
+	})
 	if err != nil {
-		return 0, err
+		return nil, nil, err
 	}
+	return &submission, decision, nil
+}
 
-	if len(testers) == 0 {
-		return 0, nil
-	}
+func ClaimSubmission(submissionID uuid.UUID, actor StatusChange) (*models.Submission, error) {
+	var submission models.Submission
 
-	var allTasks []models.Submission
-	err = database.DB.Where("status IN ?", []string{
-		string(models.StatusPending),
-		string(models.StatusClaimed),
-		string(models.StatusEligible),
-	}).Order("created_at ASC").Find(&allTasks).Error
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&submission, submissionID).Error
+		if err != nil {
+			return err
+		}
+		if submission.Status != models.StatusPending && submission.Status != models.StatusClaimed {
+			return ErrSubmissionNotClaimable
+		}
+
+		err = tx.Model(&models.Submission{}).
+			Where("id = ?", submissionID).
+			Updates(map[string]interface{}{
+				"claimed_by_id": actor.ActorID,
+				"status":        models.StatusClaimed,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		actor.SubjectType = models.TransitionSubjectSubmission
+		actor.SubjectID = submissionID
+		actor.From = string(submission.Status)
+		actor.To = string(models.StatusClaimed)
+		return RecordStatusTransitionTx(tx, actor)
+	})
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	if len(allTasks) == 0 {
-		return 0, nil
-	}
+	submission.ClaimedByID = actor.ActorID
+	submission.Status = models.StatusClaimed
+	return &submission, nil
+}
 
-	tasksPerTester := len(allTasks) / len(testers)
-	remainder := len(allTasks) % len(testers)
+func projectXTask(submission *models.Submission) assignment.Task {
+	return assignment.Task{ID: submission.ID, Language: submission.Language, Category: submission.Domain}
+}
 
-	testerTaskAssignments := make(map[uuid.UUID]int)
-	for _, tester := range testers {
-		testerTaskAssignments[tester.ID] = 0
+func projectVTask(submission *models.ProjectVSubmission) assignment.Task {
+	return assignment.Task{
+		ID:         submission.ID,
+		Language:   submission.Language,
+		Category:   submission.Category,
+		Difficulty: submission.Difficulty,
 	}
+}
 
-	redistributedCount := 0
+func RedistributeTasks() (int, error) {
 
-	testerIndex := 0
-	for _, task := range allTasks {
-		tester := testers[testerIndex]
+	redistributedCount := 0
+	queuedCount := 0
+	testerCount := 0
 
-		now := time.Now()
-		err = database.DB.Model(&models.Submission{}).
-			Where("id = ?", task.ID).
-			Updates(map[string]interface{}{
-				"claimed_by_id": tester.ID,
-				"assigned_at":   now,
-				"status":        models.StatusClaimed,
-			}).Error
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockAssignmentPipeline(tx, models.PipelineProjectX); err != nil {
+			return err
+		}
 
+		var testers []models.User
+		err := tx.Where("role = ? AND is_approved = ? AND is_green_light = ?",
+			models.RoleTester, true, true).Find(&testers).Error
 		if err != nil {
-			continue
+			return err
 		}
 
-		testerTaskAssignments[tester.ID]++
-		redistributedCount++
-
-		currentTesterQuota := tasksPerTester
-		if testerIndex < remainder {
-			currentTesterQuota++
+		ids := make([]uuid.UUID, 0, len(testers))
+		for _, tester := range testers {
+			ids = append(ids, tester.ID)
 		}
+		states := loadAvailability(tx, ids, time.Now())
+		otherLoads := activeLoads(tx, ids, models.PipelineProjectX)
 
-		if testerTaskAssignments[tester.ID] >= currentTesterQuota {
-			testerIndex++
-			if testerIndex >= len(testers) {
-				testerIndex = 0
+		available := make([]models.User, 0, len(testers))
+		for _, tester := range testers {
+			if states[tester.ID].available {
+				available = append(available, tester)
 			}
 		}
-	}
-
-	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
-	userName := "System"
-	userRole := "SYSTEM"
-
-	LogActivity(LogActivityParams{
-		Action:      "REDISTRIBUTE",
-		Description: "Redistributed tasks fairly among active testers",
-		UserID:      &userID,
-		UserName:    &userName,
-		UserRole:    &userRole,
-		Metadata: map[string]interface{}{
-			"taskCount":   redistributedCount,
-			"testerCount": len(testers),
-		},
-	})
 
-	return redistributedCount, nil
-}
-
-func AutoAssignTester(submissionID uuid.UUID) (*uuid.UUID, error) {
+		if len(available) == 0 {
+			return nil
+		}
+		testerCount = len(available)
 
-	var testers []models.User
-	err := database.DB.Where("role = ? AND is_approved = ? AND is_green_light = ?",
-		models.RoleTester, true, true).Find(&testers).Error
-	if err != nil {
-		return nil, err
-	}
+		var allTasks []models.Submission
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ?", []string{
+				string(models.StatusPending),
+				string(models.StatusClaimed),
+				string(models.StatusEligible),
+			}).Order("created_at ASC").Find(&allTasks).Error
+		if err != nil {
+			return err
+		}
 
-	if len(testers) == 0 {
+		quotas := make([]int, len(available))
+		for remaining := len(allTasks); remaining > 0; {
+			progressed := false
+			for i, tester := range available {
+				if remaining == 0 {
+					break
+				}
+				if capacity := states[tester.ID].capacity; capacity > 0 && int64(quotas[i])+otherLoads[tester.ID] >= int64(capacity) {
+					continue
+				}
+				quotas[i]++
+				remaining--
+				progressed = true
+			}
+			if !progressed {
+				break
+			}
+		}
 
-		return nil, nil
-	}
+		assigned := make([]int, len(available))
 
-	type TesterTaskCount struct {
-		TesterID uuid.UUID
-		Count    int64
-	}
+		testerIndex := 0
+		for _, task := range allTasks {
+			for testerIndex < len(available) && assigned[testerIndex] >= quotas[testerIndex] {
+				testerIndex++
+			}
 
-	var testerCounts []TesterTaskCount
-	for _, tester := range testers {
-		var count int64
-		database.DB.Model(&models.ProjectVSubmission{}).
-			Where("tester_id = ? AND status IN ?", tester.ID, []string{
-				string(models.ProjectVStatusInTesting),
-				string(models.ProjectVStatusTaskSubmittedToPlatform),
-				string(models.ProjectVStatusEligible),
-				string(models.ProjectVStatusRework),
-				string(models.ProjectVStatusReworkDone),
-			}).
-			Count(&count)
-
-		testerCounts = append(testerCounts, TesterTaskCount{
-			TesterID: tester.ID,
-			Count:    count,
-		})
-	}
+			if testerIndex >= len(available) {
+				if task.Status == models.StatusPending && task.ClaimedByID == nil {
+					continue
+				}
+				err = tx.Model(&models.Submission{}).
+					Where("id = ?", task.ID).
+					Updates(map[string]interface{}{
+						"claimed_by_id": nil,
+						"assigned_at":   nil,
+						"status":        models.StatusPending,
+					}).Error
+				if err != nil {
+					return err
+				}
+				queuedCount++
+				err = RecordStatusTransitionTx(tx, SystemStatusChange(models.TransitionSubjectSubmission, task.ID,
+					string(task.Status), string(models.StatusPending), "Queued until an active tester has capacity"))
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			tester := available[testerIndex]
+
+			err = tx.Model(&models.Submission{}).
+				Where("id = ?", task.ID).
+				Updates(map[string]interface{}{
+					"claimed_by_id": tester.ID,
+					"assigned_at":   time.Now(),
+					"status":        models.StatusClaimed,
+				}).Error
+			if err != nil {
+				return err
+			}
 
-	minCount := testerCounts[0].Count
-	selectedTesterID := testerCounts[0].TesterID
+			assigned[testerIndex]++
+			redistributedCount++
 
-	for _, tc := range testerCounts {
-		if tc.Count < minCount {
-			minCount = tc.Count
-			selectedTesterID = tc.TesterID
+			err = RecordStatusTransitionTx(tx, SystemStatusChange(models.TransitionSubjectSubmission, task.ID,
+				string(task.Status), string(models.StatusClaimed), "Tasks redistributed among active testers"))
+			if err != nil {
+				return err
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	var submission models.ProjectVSubmission
-	database.DB.Preload("Contributor").First(&submission, submissionID)
-
-	var tester models.User
-	database.DB.First(&tester, selectedTesterID)
+	if redistributedCount == 0 && queuedCount == 0 {
+		return 0, nil
+	}
 
 	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
 	userName := "System"
 	userRole := "SYSTEM"
-	targetType := "projectv_submission"
 
 	LogActivity(LogActivityParams{
-		Action:      "AUTO_ASSIGN_TESTER",
-		Description: "Project V task \"" + submission.Title + "\" auto-assigned to tester " + tester.Name,
+		Action:      "REDISTRIBUTE",
+		Description: "Redistributed tasks fairly among active testers",
 		UserID:      &userID,
 		UserName:    &userName,
 		UserRole:    &userRole,
-		TargetID:    &submissionID,
-		TargetType:  &targetType,
 		Metadata: map[string]interface{}{
-			"testerId":        selectedTesterID.String(),
-			"testerName":      tester.Name,
-			"contributorName": submission.Contributor.Name,
+			"taskCount":   redistributedCount,
+			"queuedCount": queuedCount,
+			"testerCount": testerCount,
 		},
 	})
 
-	return &selectedTesterID, nil
+	return redistributedCount, nil
 }
 
-func AutoAssignReviewer(submissionID uuid.UUID) (*uuid.UUID, error) {
-
-	var reviewers []models.User
-	err := database.DB.Where("role = ? AND is_approved = ? AND is_green_light = ?",
-		models.RoleReviewer, true, true).Find(&reviewers).Error
-	if err != nil {
-		return nil, err
-	}
+func AutoAssignTester(tx *gorm.DB, submissionID uuid.UUID) (*uuid.UUID, error) {
+	return autoAssignProjectV(tx, submissionID, models.PipelineProjectVTesting, "tester")
+}
 
-	if len(reviewers) == 0 {
+func AutoAssignReviewer(tx *gorm.DB, submissionID uuid.UUID) (*uuid.UUID, error) {
+	return autoAssignProjectV(tx, submissionID, models.PipelineProjectVReview, "reviewer")
+}
 
-		return nil, nil
-	}
+func autoAssignProjectV(tx *gorm.DB, submissionID uuid.UUID, pipeline, label string) (*uuid.UUID, error) {
 
-	type ReviewerTaskCount struct {
-		ReviewerID uuid.UUID
-		Count      int64
-	}
-
-	var reviewerCounts []ReviewerTaskCount
-	for _, reviewer := range reviewers {
-		var count int64
-		database.DB.Model(&models.ProjectVSubmission{}).
-			Where("reviewer_id = ? AND status IN ?", reviewer.ID, []string{
-				string(models.ProjectVStatusPendingReview),
-				string(models.ProjectVStatusChangesRequested),
-				string(models.ProjectVStatusChangesDone),
-				string(models.ProjectVStatusFinalChecks),
-			}).
-			Count(&count)
-
-		reviewerCounts = append(reviewerCounts, ReviewerTaskCount{
-			ReviewerID: reviewer.ID,
-			Count:      count,
-		})
+	var submission models.ProjectVSubmission
+	if err := tx.Preload("Contributor").First(&submission, submissionID).Error; err != nil {
+		return nil, err
 	}
 
-	minCount := reviewerCounts[0].Count
-	selectedReviewerID := reviewerCounts[0].ReviewerID
-
-	for _, rc := range reviewerCounts {
-		if rc.Count < minCount {
-			minCount = rc.Count
-			selectedReviewerID = rc.ReviewerID
-		}
+	decision, err := SelectAssignee(tx, pipeline, projectVTask(&submission))
+	if err != nil || decision == nil {
+		return nil, err
 	}
-
-	var submission models.ProjectVSubmission
-	database.DB.Preload("Contributor").First(&submission, submissionID)
-
-	var reviewer models.User
-	database.DB.First(&reviewer, selectedReviewerID)
+	selectedID := decision.Candidate.UserID
 
 	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
 	userName := "System"
 	userRole := "SYSTEM"
 	targetType := "projectv_submission"
 
+	metadata := decision.Metadata()
+	metadata[label+"Id"] = selectedID.String()
+	metadata[label+"Name"] = decision.Candidate.Name
+	metadata["contributorName"] = submission.Contributor.Name
+
 	LogActivity(LogActivityParams{
-		Action:      "AUTO_ASSIGN_REVIEWER",
-		Description: "Project V task \"" + submission.Title + "\" auto-assigned to reviewer " + reviewer.Name,
+		Action:      "AUTO_ASSIGN_" + strings.ToUpper(label),
+		Description: "Project V task \"" + submission.Title + "\" auto-assigned to " + label + " " + decision.Candidate.Name,
 		UserID:      &userID,
 		UserName:    &userName,
 		UserRole:    &userRole,
 		TargetID:    &submissionID,
 		TargetType:  &targetType,
-		Metadata: map[string]interface{}{
-			"reviewerId":      selectedReviewerID.String(),
-			"reviewerName":    reviewer.Name,
-			"contributorName": submission.Contributor.Name,
-		},
+		Metadata:    metadata,
 	})
 
-	return &selectedReviewerID, nil
+	return &selectedID, nil
 }
 
 func ReassignPendingProjectVTasks() (int, error) {
@@ -443,16 +411,56 @@ func ReassignPendingProjectVTasks() (int, error) {
 
 	assignedCount := 0
 	for _, submission := range pendingSubmissions {
-		testerID, err := AutoAssignTester(submission.ID)
-		if err == nil && testerID != nil {
+		if err := workflow.Apply(&submission, workflow.ActionStartTesting, workflow.SystemActor, workflow.Params{}); err == nil {
+			assignedCount++
+		}
+	}
+
+	return assignedCount, nil
+}
+
+func ReleaseTesterSubmissions(testerID uuid.UUID, reason string) (int, error) {
+	var claimed []models.Submission
 
-			submission.TesterID = testerID
-			submission.Status = models.ProjectVStatusInTesting
-			if err := database.DB.Save(&submission).Error; err == nil {
-				assignedCount++
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "status").
+			Where("claimed_by_id = ?", testerID).
+			Find(&claimed).Error
+		if err != nil || len(claimed) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, 0, len(claimed))
+		for _, submission := range claimed {
+			ids = append(ids, submission.ID)
+		}
+		err = tx.Model(&models.Submission{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"claimed_by_id": nil,
+				"assigned_at":   nil,
+				"status":        models.StatusPending,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		for _, submission := range claimed {
+			err := RecordStatusTransitionTx(tx, SystemStatusChange(models.TransitionSubjectSubmission, submission.ID,
+				string(submission.Status), string(models.StatusPending), reason))
+			if err != nil {
+				return err
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	return assignedCount, nil
+	if len(claimed) > 0 {
+		KickAssignmentScheduler()
+	}
+	return len(claimed), nil
 }
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidAvailability = errors.New("invalid availability")
+	ErrOutOfOfficeNotFound = errors.New("out of office entry not found")
+)
+
+type WorkingWindow struct {
+	Day   int    `json:"day"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type AvailabilityInput struct {
+	MaxConcurrentTasks *int             `json:"maxConcurrentTasks"`
+	TimeZone           *string          `json:"timeZone"`
+	WorkingHours       *[]WorkingWindow `json:"workingHours"`
+}
+
+type UserAvailability struct {
+	MaxConcurrentTasks int                  `json:"maxConcurrentTasks"`
+	TimeZone           string               `json:"timeZone"`
+	WorkingHours       []WorkingWindow      `json:"workingHours"`
+	OutOfOffice        []models.OutOfOffice `json:"outOfOffice"`
+	AvailableNow       bool                 `json:"availableNow"`
+	UnavailableReason  string               `json:"unavailableReason,omitempty"`
+}
+
+type availabilityState struct {
+	capacity  int
+	available bool
+	reason    string
+}
+
+func GetAvailability(userID uuid.UUID) (*UserAvailability, error) {
+	var record models.Availability
+	err := database.DB.Where("user_id = ?", userID).First(&record).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		record = models.Availability{UserID: userID, TimeZone: "UTC"}
+	}
+
+	var outOfOffice []models.OutOfOffice
+	err = database.DB.
+		Where("user_id = ? AND ends_at > ?", userID, time.Now()).
+		Order("starts_at ASC").
+		Find(&outOfOffice).Error
+	if err != nil {
+		return nil, err
+	}
+
+	state := evaluateAvailability(&record, outOfOffice, time.Now())
+	return &UserAvailability{
+		MaxConcurrentTasks: record.MaxConcurrentTasks,
+		TimeZone:           record.TimeZone,
+		WorkingHours:       workingWindows(&record),
+		OutOfOffice:        outOfOffice,
+		AvailableNow:       state.available,
+		UnavailableReason:  state.reason,
+	}, nil
+}
+
+func SaveAvailability(userID uuid.UUID, input AvailabilityInput) (*UserAvailability, error) {
+	var record models.Availability
+	err := database.DB.Where("user_id = ?", userID).First(&record).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		record = models.Availability{UserID: userID, TimeZone: "UTC"}
+	}
+
+	if input.MaxConcurrentTasks != nil {
+		if *input.MaxConcurrentTasks < 0 {
+			return nil, fmt.Errorf("%w: maxConcurrentTasks cannot be negative", ErrInvalidAvailability)
+		}
+		record.MaxConcurrentTasks = *input.MaxConcurrentTasks
+	}
+	if input.TimeZone != nil {
+		zone := strings.TrimSpace(*input.TimeZone)
+		if zone == "" {
+			zone = "UTC"
+		}
+		if _, err := time.LoadLocation(zone); err != nil {
+			return nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidAvailability, zone)
+		}
+		record.TimeZone = zone
+	}
+	if input.WorkingHours != nil {
+		for _, window := range *input.WorkingHours {
+			if err := validateWorkingWindow(window); err != nil {
+				return nil, err
+			}
+		}
+		hours, _ := json.Marshal(*input.WorkingHours)
+		hoursJSON := string(hours)
+		record.WorkingHours = &hoursJSON
+	}
+
+	err = database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"max_concurrent_tasks", "time_zone", "working_hours", "updated_at"}),
+	}).Create(&record).Error
+	if err != nil {
+		return nil, err
+	}
+
+	KickAssignmentScheduler()
+	return GetAvailability(userID)
+}
+
+func AddOutOfOffice(userID uuid.UUID, startsAt, endsAt time.Time, reason string) (*models.OutOfOffice, error) {
+	if !endsAt.After(startsAt) {
+		return nil, fmt.Errorf("%w: out of office must end after it starts", ErrInvalidAvailability)
+	}
+	if endsAt.Before(time.Now()) {
+		return nil, fmt.Errorf("%w: out of office range is already in the past", ErrInvalidAvailability)
+	}
+
+	entry := models.OutOfOffice{
+		UserID:   userID,
+		StartsAt: startsAt,
+		EndsAt:   endsAt,
+		Reason:   strings.TrimSpace(reason),
+	}
+	if err := database.DB.Create(&entry).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func DeleteOutOfOffice(userID, entryID uuid.UUID) error {
+	result := database.DB.Where("id = ? AND user_id = ?", entryID, userID).Delete(&models.OutOfOffice{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOutOfOfficeNotFound
+	}
+
+	KickAssignmentScheduler()
+	return nil
+}
+
+func validateWorkingWindow(window WorkingWindow) error {
+	if window.Day < 0 || window.Day > 6 {
+		return fmt.Errorf("%w: day must be between 0 (Sunday) and 6 (Saturday)", ErrInvalidAvailability)
+	}
+	start, err := parseClock(window.Start)
+	if err != nil {
+		return err
+	}
+	end, err := parseClock(window.End)
+	if err != nil {
+		return err
+	}
+	if end == start {
+		return fmt.Errorf("%w: working window %s-%s is empty", ErrInvalidAvailability, window.Start, window.End)
+	}
+	return nil
+}
+
+func parseClock(value string) (int, error) {
+	if value == "24:00" {
+		return 24 * 60, nil
+	}
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: time %q must use HH:MM", ErrInvalidAvailability, value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+func workingWindows(record *models.Availability) []WorkingWindow {
+	windows := []WorkingWindow{}
+	if record.WorkingHours != nil {
+		json.Unmarshal([]byte(*record.WorkingHours), &windows)
+	}
+	return windows
+}
+
+func evaluateAvailability(record *models.Availability, outOfOffice []models.OutOfOffice, now time.Time) availabilityState {
+	state := availabilityState{capacity: record.MaxConcurrentTasks, available: true}
+
+	for _, entry := range outOfOffice {
+		if !now.Before(entry.StartsAt) && now.Before(entry.EndsAt) {
+			state.available = false
+			state.reason = "Out of office until " + entry.EndsAt.UTC().Format(time.RFC3339)
+			return state
+		}
+	}
+
+	windows := workingWindows(record)
+	if len(windows) == 0 {
+		return state
+	}
+
+	location, err := time.LoadLocation(record.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+	local := now.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	today := int(local.Weekday())
+	yesterday := (today + 6) % 7
+	for _, window := range windows {
+		start, err := parseClock(window.Start)
+		if err != nil {
+			continue
+		}
+		end, err := parseClock(window.End)
+		if err != nil {
+			continue
+		}
+		if start < end {
+			if window.Day == today && minute >= start && minute < end {
+				return state
+			}
+			continue
+		}
+		if (window.Day == today && minute >= start) || (window.Day == yesterday && minute < end) {
+			return state
+		}
+	}
+
+	state.available = false
+	state.reason = "Outside working hours (" + record.TimeZone + ")"
+	return state
+}
+
+func loadAvailability(tx *gorm.DB, userIDs []uuid.UUID, now time.Time) map[uuid.UUID]availabilityState {
+	states := make(map[uuid.UUID]availabilityState, len(userIDs))
+	if len(userIDs) == 0 {
+		return states
+	}
+
+	var records []models.Availability
+	tx.Where("user_id IN ?", userIDs).Find(&records)
+	byUser := make(map[uuid.UUID]*models.Availability, len(records))
+	for i := range records {
+		byUser[records[i].UserID] = &records[i]
+	}
+
+	var outOfOffice []models.OutOfOffice
+	tx.Where("user_id IN ? AND starts_at <= ? AND ends_at > ?", userIDs, now, now).Find(&outOfOffice)
+	absences := make(map[uuid.UUID][]models.OutOfOffice)
+	for _, entry := range outOfOffice {
+		absences[entry.UserID] = append(absences[entry.UserID], entry)
+	}
+
+	for _, id := range userIDs {
+		record, ok := byUser[id]
+		if !ok {
+			record = &models.Availability{UserID: id, TimeZone: "UTC"}
+		}
+		states[id] = evaluateAvailability(record, absences[id], now)
+	}
+	return states
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adzzatxperts/backend/internal/models"
+)
+
+func TestEvaluateAvailabilityWorkingWindows(t *testing.T) {
+	hours := `[{"day":1,"start":"09:00","end":"17:00"},{"day":5,"start":"22:00","end":"06:00"}]`
+	record := &models.Availability{TimeZone: "UTC", WorkingHours: &hours}
+
+	cases := []struct {
+		name string
+		at   string
+		want bool
+	}{
+		{"inside a same-day window", "2026-10-12T10:00:00Z", true},
+		{"before a same-day window", "2026-10-12T08:59:00Z", false},
+		{"at the end of a same-day window", "2026-10-12T17:00:00Z", false},
+		{"overnight window before midnight", "2026-10-16T23:30:00Z", true},
+		{"overnight window after midnight", "2026-10-17T05:59:00Z", true},
+		{"overnight window has ended", "2026-10-17T06:00:00Z", false},
+		{"overnight window has not started", "2026-10-16T21:59:00Z", false},
+		{"early morning on the start day", "2026-10-16T03:00:00Z", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			now, err := time.Parse(time.RFC3339, tc.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := evaluateAvailability(record, nil, now).available; got != tc.want {
+				t.Fatalf("available at %s = %v, want %v", tc.at, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidateWorkingWindowAllowsOvernight(t *testing.T) {
+	if err := validateWorkingWindow(WorkingWindow{Day: 5, Start: "22:00", End: "06:00"}); err != nil {
+		t.Fatalf("overnight window rejected: %v", err)
+	}
+	if err := validateWorkingWindow(WorkingWindow{Day: 5, Start: "09:00", End: "09:00"}); err == nil {
+		t.Fatal("empty window accepted")
+	}
+}
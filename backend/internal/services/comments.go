@@ -0,0 +1,242 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/adzzatxperts/backend/internal/patch"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCommentNotFound      = errors.New("comment not found")
+	ErrCommentEmpty         = errors.New("comment body is required")
+	ErrCommentInvalidAnchor = errors.New("invalid comment anchor")
+	ErrCommentReplyToReply  = errors.New("replies can only be added to top-level comments")
+	ErrCommentForbidden     = errors.New("only the comment author, the assigned tester or reviewer, or an admin can resolve this thread")
+)
+
+type CommentAnchor struct {
+	Patch string `json:"patch"`
+	Path  string `json:"path"`
+	Line  int    `json:"line"`
+}
+
+type NewComment struct {
+	SubmissionID uuid.UUID
+	ParentID     *uuid.UUID
+	AuthorID     *uuid.UUID
+	AuthorRole   string
+	Body         string
+	Blocking     bool
+	Anchor       *CommentAnchor
+}
+
+func CreateComment(input NewComment) (*models.Comment, error) {
+	body := strings.TrimSpace(input.Body)
+	if body == "" {
+		return nil, ErrCommentEmpty
+	}
+
+	comment := models.Comment{
+		SubmissionID: input.SubmissionID,
+		AuthorID:     input.AuthorID,
+		AuthorRole:   input.AuthorRole,
+		Body:         body,
+		Blocking:     input.Blocking,
+	}
+
+	if input.ParentID != nil {
+		var parent models.Comment
+		err := database.DB.Where("id = ? AND submission_id = ?", *input.ParentID, input.SubmissionID).First(&parent).Error
+		if err != nil {
+			return nil, ErrCommentNotFound
+		}
+		if parent.ParentID != nil {
+			return nil, ErrCommentReplyToReply
+		}
+		comment.ParentID = &parent.ID
+		comment.Blocking = false
+	}
+
+	if input.Anchor != nil {
+		if err := ValidateCommentAnchor(input.SubmissionID, input.Anchor); err != nil {
+			return nil, err
+		}
+		comment.AnchorPatch = &input.Anchor.Patch
+		comment.AnchorPath = &input.Anchor.Path
+		if input.Anchor.Line > 0 {
+			comment.AnchorLine = &input.Anchor.Line
+		}
+	}
+
+	if err := database.DB.Create(&comment).Error; err != nil {
+		return nil, err
+	}
+
+	notifyCommentAudience(&comment)
+	return &comment, nil
+}
+
+func OpenFeedbackThreads(submissionID uuid.UUID, authorID *uuid.UUID, authorRole, feedback string, anchored []NewComment) ([]models.Comment, error) {
+	threads := make([]models.Comment, 0, len(anchored)+1)
+
+	root, err := CreateComment(NewComment{
+		SubmissionID: submissionID,
+		AuthorID:     authorID,
+		AuthorRole:   authorRole,
+		Body:         feedback,
+		Blocking:     true,
+	})
+	if err != nil {
+		return threads, err
+	}
+	threads = append(threads, *root)
+
+	for _, input := range anchored {
+		input.SubmissionID = submissionID
+		input.AuthorID = authorID
+		input.AuthorRole = authorRole
+		input.ParentID = nil
+		input.Blocking = true
+		comment, err := CreateComment(input)
+		if err != nil {
+			return threads, err
+		}
+		threads = append(threads, *comment)
+	}
+
+	return threads, nil
+}
+
+func GetCommentThreads(submissionID uuid.UUID) ([]models.Comment, error) {
+	var threads []models.Comment
+	err := database.DB.
+		Preload("Author").
+		Preload("Replies", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Preload("Replies.Author").
+		Where("submission_id = ? AND parent_id IS NULL", submissionID).
+		Order("created_at ASC").
+		Find(&threads).Error
+	return threads, err
+}
+
+func SetCommentResolved(submission *models.ProjectVSubmission, commentID uuid.UUID, resolved bool, actorID uuid.UUID, actorRole string) (*models.Comment, error) {
+	var comment models.Comment
+	if err := database.DB.Where("id = ? AND submission_id = ?", commentID, submission.ID).First(&comment).Error; err != nil {
+		return nil, ErrCommentNotFound
+	}
+	if comment.ParentID != nil {
+		if err := database.DB.First(&comment, *comment.ParentID).Error; err != nil {
+			return nil, ErrCommentNotFound
+		}
+	}
+	if !CanResolveComment(submission, &comment, actorID, actorRole) {
+		return nil, ErrCommentForbidden
+	}
+
+	updates := map[string]interface{}{"resolved": resolved}
+	if resolved {
+		now := time.Now()
+		updates["resolved_by_id"] = actorID
+		updates["resolved_at"] = now
+	} else {
+		updates["resolved_by_id"] = nil
+		updates["resolved_at"] = nil
+	}
+
+	if err := database.DB.Model(&comment).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func CanResolveComment(submission *models.ProjectVSubmission, thread *models.Comment, actorID uuid.UUID, actorRole string) bool {
+	switch models.UserRole(actorRole) {
+	case models.RoleAdmin:
+		return true
+	case models.RoleContributor:
+		return false
+	case models.RoleTester:
+		if submission.TesterID != nil && *submission.TesterID == actorID {
+			return true
+		}
+	case models.RoleReviewer:
+		if submission.ReviewerID != nil && *submission.ReviewerID == actorID {
+			return true
+		}
+	}
+	return thread.AuthorID != nil && *thread.AuthorID == actorID
+}
+
+func CountOpenBlockingComments(submissionID uuid.UUID) (int64, error) {
+	var count int64
+	err := database.DB.Model(&models.Comment{}).
+		Where("submission_id = ? AND parent_id IS NULL AND blocking = ? AND resolved = ?", submissionID, true, false).
+		Count(&count).Error
+	return count, err
+}
+
+func ValidateCommentAnchor(submissionID uuid.UUID, anchor *CommentAnchor) error {
+	anchor.Path = strings.TrimSpace(anchor.Path)
+	if anchor.Patch != models.CommentPatchTest && anchor.Patch != models.CommentPatchSolution {
+		return ErrCommentInvalidAnchor
+	}
+	if anchor.Path == "" || anchor.Line < 0 {
+		return ErrCommentInvalidAnchor
+	}
+
+	var submission models.ProjectVSubmission
+	if err := database.DB.Select("id", "patch_analysis").First(&submission, submissionID).Error; err != nil {
+		return err
+	}
+	if submission.PatchAnalysis == nil {
+		return nil
+	}
+
+	var report patch.Report
+	if err := json.Unmarshal([]byte(*submission.PatchAnalysis), &report); err != nil {
+		return nil
+	}
+	target := report.TestPatch
+	if anchor.Patch == models.CommentPatchSolution {
+		target = report.SolutionPatch
+	}
+	if target == nil {
+		return nil
+	}
+	for _, path := range target.Paths() {
+		if path == anchor.Path {
+			return nil
+		}
+	}
+	return ErrCommentInvalidAnchor
+}
+
+func notifyCommentAudience(comment *models.Comment) {
+	if validationHub == nil {
+		return
+	}
+
+	var submission models.ProjectVSubmission
+	if err := database.DB.First(&submission, comment.SubmissionID).Error; err != nil {
+		return
+	}
+
+	recipients := []*uuid.UUID{&submission.ContributorID, submission.TesterID, submission.ReviewerID}
+	for _, recipient := range recipients {
+		if recipient == nil || (comment.AuthorID != nil && *recipient == *comment.AuthorID) {
+			continue
+		}
+		validationHub.BroadcastToUser(*recipient, "comment", map[string]interface{}{
+			"submissionId": submission.ID,
+			"title":        "New comment on " + submission.Title,
+			"comment":      comment,
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestCanResolveComment(t *testing.T) {
+	tester, reviewer, author, other := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	submission := &models.ProjectVSubmission{TesterID: &tester, ReviewerID: &reviewer}
+	thread := &models.Comment{AuthorID: &author}
+
+	cases := []struct {
+		name  string
+		actor uuid.UUID
+		role  models.UserRole
+		want  bool
+	}{
+		{"admin", other, models.RoleAdmin, true},
+		{"assigned tester", tester, models.RoleTester, true},
+		{"assigned reviewer", reviewer, models.RoleReviewer, true},
+		{"author", author, models.RoleReviewer, true},
+		{"unassigned tester", other, models.RoleTester, false},
+		{"unassigned reviewer", other, models.RoleReviewer, false},
+		{"tester acting as reviewer", tester, models.RoleReviewer, false},
+		{"contributor", author, models.RoleContributor, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := CanResolveComment(submission, thread, tc.actor, string(tc.role)); got != tc.want {
+				t.Fatalf("CanResolveComment = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
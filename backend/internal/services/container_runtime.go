@@ -0,0 +1,599 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type ContainerLimits struct {
+	CPUs           float64 `json:"cpus,omitempty"`
+	MemoryMB       int     `json:"memoryMb,omitempty"`
+	PIDs           int     `json:"pidsLimit,omitempty"`
+	DiskMB         int     `json:"diskMb,omitempty"`
+	Network        string  `json:"network,omitempty"`
+	TimeoutSeconds int     `json:"timeoutSeconds,omitempty"`
+}
+
+type ContainerBuildOptions struct {
+	ContextDir string
+	Image      string
+	Builder    string
+	Limits     ContainerLimits
+	Output     io.Writer
+}
+
+type ContainerRunOptions struct {
+	Image   string
+	Name    string
+	Command []string
+	Env     map[string]string
+	Mounts  map[string]string
+	Limits  ContainerLimits
+	Output  io.Writer
+}
+
+type ContainerResult struct {
+	Output          string
+	Stdout          string
+	Stderr          string
+	OutputTruncated bool
+	ExitCode        int
+}
+
+type ContainerExitError struct {
+	ExitCode int
+}
+
+func (e *ContainerExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.ExitCode)
+}
+
+type ContainerRuntime interface {
+	Name() string
+	Build(ctx context.Context, opts ContainerBuildOptions) (ContainerResult, error)
+	Run(ctx context.Context, opts ContainerRunOptions) (ContainerResult, error)
+	RemoveContainer(ctx context.Context, name string) error
+	RemoveImage(ctx context.Context, image string) error
+}
+
+var (
+	containerRuntime   ContainerRuntime
+	containerRuntimeMu sync.RWMutex
+)
+
+func InitContainerRuntime() (ContainerRuntime, error) {
+	runtime, err := NewContainerRuntime(os.Getenv("CONTAINER_RUNTIME"))
+	if err != nil {
+		return nil, err
+	}
+
+	SetContainerRuntime(runtime)
+	return runtime, nil
+}
+
+func NewContainerRuntime(name string) (ContainerRuntime, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "docker":
+		return NewDockerRuntime(os.Getenv("DOCKER_BINARY")), nil
+	case "podman":
+		return NewPodmanRuntime(os.Getenv("PODMAN_BINARY")), nil
+	case "fake":
+		return NewFakeRuntime(), nil
+	default:
+		return nil, fmt.Errorf("unknown container runtime %q (expected docker, podman or fake)", name)
+	}
+}
+
+func SetContainerRuntime(runtime ContainerRuntime) {
+	containerRuntimeMu.Lock()
+	defer containerRuntimeMu.Unlock()
+	containerRuntime = runtime
+}
+
+func getContainerRuntime() ContainerRuntime {
+	containerRuntimeMu.RLock()
+	runtime := containerRuntime
+	containerRuntimeMu.RUnlock()
+
+	if runtime == nil {
+		return NewDockerRuntime("")
+	}
+	return runtime
+}
+
+const (
+	SandboxBuilderIsolated = "isolated"
+	SandboxBuilderDefault  = "default"
+)
+
+type cliRuntime struct {
+	name   string
+	binary string
+
+	buildersMu sync.Mutex
+	builders   map[string]bool
+}
+
+func NewDockerRuntime(binary string) ContainerRuntime {
+	if binary == "" {
+		binary = "docker"
+	}
+	return &cliRuntime{name: "docker", binary: binary}
+}
+
+func NewPodmanRuntime(binary string) ContainerRuntime {
+	if binary == "" {
+		binary = "podman"
+	}
+	return &cliRuntime{name: "podman", binary: binary}
+}
+
+func (r *cliRuntime) Name() string {
+	return r.name
+}
+
+func (r *cliRuntime) Build(ctx context.Context, opts ContainerBuildOptions) (ContainerResult, error) {
+	if r.name == "docker" && opts.Builder != SandboxBuilderDefault {
+		return r.buildIsolated(ctx, opts)
+	}
+
+	args := []string{"build", "-t", opts.Image}
+	args = append(args, buildLimitArgs(opts.Limits)...)
+	args = append(args, opts.ContextDir)
+
+	cmd := exec.CommandContext(ctx, r.binary, args...)
+	cmd.Dir = opts.ContextDir
+	if r.name == "docker" {
+		cmd.Env = append(os.Environ(), "DOCKER_BUILDKIT=0")
+	}
+	return runCLI(cmd, opts.Output)
+}
+
+func (r *cliRuntime) buildIsolated(ctx context.Context, opts ContainerBuildOptions) (ContainerResult, error) {
+	builder, err := r.ensureBuilder(ctx, opts.Limits)
+	if err != nil {
+		return ContainerResult{Output: err.Error(), ExitCode: -1}, err
+	}
+
+	args := []string{"buildx", "build", "--builder", builder, "--load", "--progress", "plain", "-t", opts.Image}
+	if opts.Limits.Network != "" {
+		args = append(args, "--network", opts.Limits.Network)
+	}
+	args = append(args, opts.ContextDir)
+
+	cmd := exec.CommandContext(ctx, r.binary, args...)
+	cmd.Dir = opts.ContextDir
+	return runCLI(cmd, opts.Output)
+}
+
+func (r *cliRuntime) ensureBuilder(ctx context.Context, limits ContainerLimits) (string, error) {
+	name := "projectv-sandbox-" + limitsDigest(limits)
+
+	r.buildersMu.Lock()
+	defer r.buildersMu.Unlock()
+	if r.builders[name] {
+		return name, nil
+	}
+
+	if _, err := runCLI(exec.CommandContext(ctx, r.binary, "buildx", "inspect", name), nil); err != nil {
+		args := []string{"buildx", "create", "--name", name, "--driver", "docker-container"}
+		for _, opt := range builderDriverOpts(limits) {
+			args = append(args, "--driver-opt", opt)
+		}
+		if result, err := runCLI(exec.CommandContext(ctx, r.binary, args...), nil); err != nil {
+			return "", fmt.Errorf("failed to create build sandbox %s (requires docker buildx with the docker-container driver, or SANDBOX_BUILDER=default): %s",
+				name, strings.TrimSpace(result.Output))
+		}
+	}
+
+	if result, err := runCLI(exec.CommandContext(ctx, r.binary, "buildx", "inspect", "--bootstrap", name), nil); err != nil {
+		return "", fmt.Errorf("failed to start build sandbox %s: %s", name, strings.TrimSpace(result.Output))
+	}
+
+	if limits.PIDs > 0 {
+		container := "buildx_buildkit_" + name + "0"
+		result, err := runCLI(exec.CommandContext(ctx, r.binary, "update", "--pids-limit", strconv.Itoa(limits.PIDs), container), nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to apply pids limit to build sandbox %s: %s", name, strings.TrimSpace(result.Output))
+		}
+	}
+
+	if r.builders == nil {
+		r.builders = make(map[string]bool)
+	}
+	r.builders[name] = true
+	return name, nil
+}
+
+func builderDriverOpts(limits ContainerLimits) []string {
+	var opts []string
+	if limits.CPUs > 0 {
+		opts = append(opts, "cpu-period=100000", "cpu-quota="+strconv.Itoa(int(limits.CPUs*100000)))
+	}
+	if limits.MemoryMB > 0 {
+		memory := fmt.Sprintf("%dm", limits.MemoryMB)
+		opts = append(opts, "memory="+memory, "memory-swap="+memory)
+	}
+	return opts
+}
+
+func limitsDigest(limits ContainerLimits) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%g/%d/%d", limits.CPUs, limits.MemoryMB, limits.PIDs)))
+	return hex.EncodeToString(sum[:])[:8]
+}
+
+func (r *cliRuntime) Run(ctx context.Context, opts ContainerRunOptions) (ContainerResult, error) {
+	args := []string{"run", "--rm"}
+	if opts.Name != "" {
+		args = append(args, "--name", opts.Name)
+	}
+	args = append(args, runLimitArgs(opts.Limits)...)
+	for _, target := range sortedKeys(opts.Mounts) {
+		args = append(args, "-v", opts.Mounts[target]+":"+target)
+	}
+	for _, key := range sortedKeys(opts.Env) {
+		args = append(args, "-e", key+"="+opts.Env[key])
+	}
+	args = append(args, opts.Image)
+	args = append(args, opts.Command...)
+
+	result, err := runCLI(exec.CommandContext(ctx, r.binary, args...), opts.Output)
+	if err != nil && opts.Limits.DiskMB > 0 && strings.Contains(result.Output, "storage-opt") {
+		return result, fmt.Errorf("%w: SANDBOX_DISK_MB needs the overlay2 storage driver on XFS mounted with pquota: %s",
+			err, strings.TrimSpace(result.Output))
+	}
+	return result, err
+}
+
+func (r *cliRuntime) RemoveContainer(ctx context.Context, name string) error {
+	_, err := runCLI(exec.CommandContext(ctx, r.binary, "rm", "-f", name), nil)
+	return err
+}
+
+func (r *cliRuntime) RemoveImage(ctx context.Context, image string) error {
+	_, err := runCLI(exec.CommandContext(ctx, r.binary, "rmi", "-f", image), nil)
+	return err
+}
+
+func buildLimitArgs(limits ContainerLimits) []string {
+	var args []string
+	if limits.CPUs > 0 {
+		args = append(args, "--cpu-period", "100000", "--cpu-quota", strconv.Itoa(int(limits.CPUs*100000)))
+	}
+	if limits.MemoryMB > 0 {
+		memory := fmt.Sprintf("%dm", limits.MemoryMB)
+		args = append(args, "--memory", memory, "--memory-swap", memory)
+	}
+	if limits.Network != "" {
+		args = append(args, "--network", limits.Network)
+	}
+	return args
+}
+
+func CheckSandboxSupport(ctx context.Context, runtime ContainerRuntime, config SandboxConfig) error {
+	cli, ok := runtime.(*cliRuntime)
+	if !ok {
+		return nil
+	}
+
+	if cli.name == "docker" && config.Builder != SandboxBuilderDefault {
+		if result, err := runCLI(exec.CommandContext(ctx, cli.binary, "buildx", "version"), nil); err != nil {
+			return fmt.Errorf("SANDBOX_BUILDER=%s needs docker buildx: %s", config.Builder, strings.TrimSpace(result.Output))
+		}
+	}
+
+	if config.Test.DiskMB > 0 && cli.name == "docker" {
+		result, err := runCLI(exec.CommandContext(ctx, cli.binary, "info", "--format", "{{.Driver}} {{json .DriverStatus}}"), nil)
+		if err != nil {
+			return fmt.Errorf("failed to inspect the docker storage driver: %s", strings.TrimSpace(result.Output))
+		}
+		info := strings.TrimSpace(result.Stdout)
+		if !strings.HasPrefix(info, "overlay2 ") || !strings.Contains(info, `"xfs"`) {
+			return fmt.Errorf("SANDBOX_DISK_MB needs the overlay2 storage driver on XFS mounted with pquota, docker reports %s", info)
+		}
+	}
+
+	return nil
+}
+
+func runLimitArgs(limits ContainerLimits) []string {
+	args := []string{"--security-opt", "no-new-privileges"}
+	if limits.CPUs > 0 {
+		args = append(args, "--cpus", strconv.FormatFloat(limits.CPUs, 'f', -1, 64))
+	}
+	if limits.MemoryMB > 0 {
+		memory := fmt.Sprintf("%dm", limits.MemoryMB)
+		args = append(args, "--memory", memory, "--memory-swap", memory)
+	}
+	if limits.PIDs > 0 {
+		args = append(args, "--pids-limit", strconv.Itoa(limits.PIDs))
+	}
+	if limits.DiskMB > 0 {
+		args = append(args, "--storage-opt", fmt.Sprintf("size=%dm", limits.DiskMB))
+	}
+	if limits.Network != "" {
+		args = append(args, "--network", limits.Network)
+	}
+	return args
+}
+
+func runCLI(cmd *exec.Cmd, live io.Writer) (ContainerResult, error) {
+	limit := envInt("CONTAINER_OUTPUT_LIMIT", 1<<20)
+	output := newBoundedBuffer(limit)
+	stdout := newBoundedBuffer(limit)
+	stderr := newBoundedBuffer(limit)
+
+	combined := &syncWriter{w: output}
+	if live != nil {
+		combined.w = io.MultiWriter(output, live)
+	}
+	cmd.Stdout = io.MultiWriter(stdout, combined)
+	cmd.Stderr = io.MultiWriter(stderr, combined)
+	cmd.WaitDelay = 10 * time.Second
+
+	err := cmd.Run()
+	result := ContainerResult{
+		Output:          output.String(),
+		Stdout:          stdout.String(),
+		Stderr:          stderr.String(),
+		OutputTruncated: output.Truncated() || stdout.Truncated() || stderr.Truncated(),
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		result.ExitCode = exitErr.ExitCode()
+		return result, &ContainerExitError{ExitCode: result.ExitCode}
+	}
+	if err != nil {
+		result.ExitCode = -1
+		return result, err
+	}
+
+	return result, nil
+}
+
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
+type boundedBuffer struct {
+	limit   int
+	head    []byte
+	tail    []byte
+	dropped int64
+}
+
+func newBoundedBuffer(limit int) *boundedBuffer {
+	if limit < 2 {
+		limit = 2
+	}
+	return &boundedBuffer{limit: limit}
+}
+
+func (b *boundedBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	headLimit := b.limit / 2
+
+	if room := headLimit - len(b.head); room > 0 {
+		if room > len(p) {
+			room = len(p)
+		}
+		b.head = append(b.head, p[:room]...)
+		p = p[room:]
+	}
+
+	if len(p) > 0 {
+		b.tail = append(b.tail, p...)
+		tailLimit := b.limit - headLimit
+		if excess := len(b.tail) - tailLimit; excess > 0 {
+			b.dropped += int64(excess)
+			b.tail = append(b.tail[:0], b.tail[excess:]...)
+		}
+	}
+
+	return n, nil
+}
+
+func (b *boundedBuffer) Truncated() bool {
+	return b.dropped > 0
+}
+
+func (b *boundedBuffer) String() string {
+	if b.dropped == 0 {
+		return string(b.head) + string(b.tail)
+	}
+	return fmt.Sprintf("%s\n... [%d bytes truncated] ...\n%s", b.head, b.dropped, b.tail)
+}
+
+type FakeImage struct {
+	Name    string
+	Files   map[string][]byte
+	BuiltAt time.Time
+}
+
+func (img *FakeImage) HasFileContaining(path, substr string) bool {
+	data, ok := img.Files[path]
+	return ok && bytes.Contains(data, []byte(substr))
+}
+
+type FakeRuntimeCall struct {
+	Op      string
+	Image   string
+	Name    string
+	Command []string
+	Env     map[string]string
+	Limits  ContainerLimits
+}
+
+type FakeRuntime struct {
+	BuildHandler func(opts ContainerBuildOptions, image *FakeImage) ContainerResult
+	RunHandler   func(opts ContainerRunOptions, image *FakeImage) ContainerResult
+
+	mu     sync.Mutex
+	images map[string]*FakeImage
+	calls  []FakeRuntimeCall
+}
+
+const fakeRuntimeMaxFileSize = 1 << 20
+
+func NewFakeRuntime() *FakeRuntime {
+	return &FakeRuntime{images: make(map[string]*FakeImage)}
+}
+
+func (r *FakeRuntime) Name() string {
+	return "fake"
+}
+
+func (r *FakeRuntime) Build(ctx context.Context, opts ContainerBuildOptions) (ContainerResult, error) {
+	if err := ctx.Err(); err != nil {
+		return ContainerResult{ExitCode: -1}, err
+	}
+
+	r.record(FakeRuntimeCall{Op: "build", Image: opts.Image, Limits: opts.Limits})
+
+	image := &FakeImage{Name: opts.Image, Files: make(map[string][]byte), BuiltAt: time.Now()}
+	err := filepath.WalkDir(opts.ContextDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil || info.Size() > fakeRuntimeMaxFileSize {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(opts.ContextDir, path)
+		image.Files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	if err != nil {
+		return ContainerResult{Output: err.Error(), ExitCode: 1}, &ContainerExitError{ExitCode: 1}
+	}
+
+	result := ContainerResult{Output: fmt.Sprintf("Successfully built %s (%d files)", opts.Image, len(image.Files))}
+	if _, ok := image.Files["Dockerfile"]; !ok {
+		result = ContainerResult{Output: "failed to read Dockerfile: no such file", ExitCode: 1}
+	}
+	if r.BuildHandler != nil {
+		result = r.BuildHandler(opts, image)
+	}
+	if opts.Output != nil {
+		io.WriteString(opts.Output, result.Output)
+	}
+	if result.ExitCode != 0 {
+		return result, &ContainerExitError{ExitCode: result.ExitCode}
+	}
+
+	r.mu.Lock()
+	r.images[opts.Image] = image
+	r.mu.Unlock()
+
+	return result, nil
+}
+
+func (r *FakeRuntime) Run(ctx context.Context, opts ContainerRunOptions) (ContainerResult, error) {
+	if err := ctx.Err(); err != nil {
+		return ContainerResult{ExitCode: -1}, err
+	}
+
+	r.record(FakeRuntimeCall{Op: "run", Image: opts.Image, Name: opts.Name, Command: opts.Command, Env: opts.Env, Limits: opts.Limits})
+
+	r.mu.Lock()
+	image, ok := r.images[opts.Image]
+	r.mu.Unlock()
+	if !ok {
+		return ContainerResult{Output: "Unable to find image '" + opts.Image + "'", ExitCode: 125}, &ContainerExitError{ExitCode: 125}
+	}
+
+	result := ContainerResult{}
+	if r.RunHandler != nil {
+		result = r.RunHandler(opts, image)
+	}
+	if result.Stdout == "" && result.Stderr == "" {
+		result.Stdout = result.Output
+	}
+	if result.Output == "" {
+		result.Output = result.Stdout + result.Stderr
+	}
+	if opts.Output != nil {
+		io.WriteString(opts.Output, result.Output)
+	}
+	if result.ExitCode != 0 {
+		return result, &ContainerExitError{ExitCode: result.ExitCode}
+	}
+
+	return result, nil
+}
+
+func (r *FakeRuntime) RemoveContainer(ctx context.Context, name string) error {
+	r.record(FakeRuntimeCall{Op: "rm", Name: name})
+	return nil
+}
+
+func (r *FakeRuntime) RemoveImage(ctx context.Context, image string) error {
+	r.record(FakeRuntimeCall{Op: "rmi", Image: image})
+
+	r.mu.Lock()
+	delete(r.images, image)
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *FakeRuntime) Image(name string) (*FakeImage, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	image, ok := r.images[name]
+	return image, ok
+}
+
+func (r *FakeRuntime) Calls() []FakeRuntimeCall {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]FakeRuntimeCall(nil), r.calls...)
+}
+
+func (r *FakeRuntime) record(call FakeRuntimeCall) {
+	r.mu.Lock()
+	r.calls = append(r.calls, call)
+	r.mu.Unlock()
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/mailer"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/adzzatxperts/backend/internal/workflow"
+	"github.com/google/uuid"
+)
+
+var assignmentEmails = map[string]struct {
+	project    string
+	assignment string
+	path       string
+}{
+	models.PipelineProjectX:        {"Project X", "testing", "/dashboard"},
+	models.PipelineProjectVTesting: {"Project V", "testing", "/project-v/tester"},
+	models.PipelineProjectVReview:  {"Project V", "review", "/project-v/reviewer"},
+}
+
+func SendPasswordResetEmail(user *models.User, token string, ttl time.Duration) error {
+	return deliverEmail(mailer.TemplatePasswordReset, user, mailer.TemplateData{
+		ActionURL:   appURL("/reset-password?token=" + url.QueryEscape(token)),
+		ActionLabel: "Reset password",
+		ExpiresIn:   humanDuration(ttl),
+	})
+}
+
+func SendTesterApprovedEmail(user *models.User) error {
+	return deliverEmail(mailer.TemplateTesterApproved, user, mailer.TemplateData{
+		ActionURL:   appURL("/dashboard"),
+		ActionLabel: "Open dashboard",
+	})
+}
+
+func SendTaskAssignedEmail(assigneeID uuid.UUID, pipeline, title, reason string) error {
+	config, ok := assignmentEmails[pipeline]
+	if !ok {
+		return ErrUnknownPipeline
+	}
+	if mailer.Current() == nil {
+		return nil
+	}
+
+	var assignee models.User
+	if err := database.DB.Select("id", "name", "email").First(&assignee, assigneeID).Error; err != nil {
+		return err
+	}
+	return deliverEmail(mailer.TemplateTaskAssigned, &assignee, mailer.TemplateData{
+		ActionURL:   appURL(config.path),
+		ActionLabel: "Open task",
+		Title:       title,
+		Project:     config.project,
+		Assignment:  config.assignment,
+		Reason:      reason,
+	})
+}
+
+func SendStatusChangedEmail(subjectType string, subjectID uuid.UUID, from, to, reason string, actorID *uuid.UUID) error {
+	if from == "" || from == to || mailer.Current() == nil {
+		return nil
+	}
+
+	var title, project, path string
+	var contributorID uuid.UUID
+	switch subjectType {
+	case models.TransitionSubjectSubmission:
+		var submission models.Submission
+		if err := database.DB.Select("id", "title", "contributor_id").First(&submission, subjectID).Error; err != nil {
+			return err
+		}
+		title, project, path, contributorID = submission.Title, "Project X", "/contributor", submission.ContributorID
+	case models.TransitionSubjectProjectV:
+		var submission models.ProjectVSubmission
+		if err := database.DB.Select("id", "title", "contributor_id").First(&submission, subjectID).Error; err != nil {
+			return err
+		}
+		title, project, path, contributorID = submission.Title, "Project V", "/project-v/contributor", submission.ContributorID
+	default:
+		return nil
+	}
+	if actorID != nil && *actorID == contributorID {
+		return nil
+	}
+
+	var contributor models.User
+	if err := database.DB.Select("id", "name", "email").First(&contributor, contributorID).Error; err != nil {
+		return err
+	}
+	return deliverEmail(mailer.TemplateStatusChanged, &contributor, mailer.TemplateData{
+		ActionURL:   appURL(path),
+		ActionLabel: "View task",
+		Title:       title,
+		Project:     project,
+		From:        statusLabel(from),
+		To:          statusLabel(to),
+		Reason:      reason,
+	})
+}
+
+func emailWorkflowEvent(event workflow.Event) {
+	if mailer.Current() == nil {
+		return
+	}
+	submission := event.Submission
+	actorID := event.Actor.ID
+
+	if err := SendStatusChangedEmail(models.TransitionSubjectProjectV, submission.ID,
+		string(event.From), string(submission.Status), event.Reason, &actorID); err != nil {
+		log.Printf("Failed to email status change for %s: %v", submission.ID, err)
+	}
+
+	assigned := []struct {
+		pipeline string
+		previous *uuid.UUID
+		current  *uuid.UUID
+	}{
+		{models.PipelineProjectVTesting, event.PreviousTesterID, submission.TesterID},
+		{models.PipelineProjectVReview, event.PreviousReviewerID, submission.ReviewerID},
+	}
+	for _, assignment := range assigned {
+		if assignment.current == nil || *assignment.current == actorID {
+			continue
+		}
+		if assignment.previous != nil && *assignment.previous == *assignment.current {
+			continue
+		}
+		if err := SendTaskAssignedEmail(*assignment.current, assignment.pipeline, submission.Title, ""); err != nil {
+			log.Printf("Failed to email assignment of %s: %v", submission.ID, err)
+		}
+	}
+}
+
+func deliverEmail(template string, user *models.User, data mailer.TemplateData) error {
+	if mailer.Current() == nil {
+		return nil
+	}
+
+	data.Name = user.Name
+	msg, err := mailer.Render(template, user.Email, data)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if err := mailer.Send(ctx, msg); err != nil {
+			log.Printf("Failed to send %s email to %s: %v", template, user.Email, err)
+		}
+	}()
+	return nil
+}
+
+func appURL(path string) string {
+	base := os.Getenv("APP_URL")
+	if base == "" {
+		base = "http://localhost:3000"
+	}
+	return strings.TrimRight(base, "/") + path
+}
+
+func statusLabel(status string) string {
+	label := strings.ToLower(strings.ReplaceAll(status, "_", " "))
+	if label == "" {
+		return label
+	}
+	return strings.ToUpper(label[:1]) + label[1:]
+}
+
+func humanDuration(d time.Duration) string {
+	if d%time.Hour == 0 {
+		return plural(int(d/time.Hour), "hour")
+	}
+	return plural(int(d.Round(time.Minute)/time.Minute), "minute")
+}
+
+func plural(count int, unit string) string {
+	if count == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", count, unit)
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/githost"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var ErrDuplicateSubmission = errors.New("this repository and commit have already been submitted")
+
+const repoCommitIndex = "idx_projectv_repo_commit"
+
+func DuplicateSubmissionError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == repoCommitIndex {
+		return ErrDuplicateSubmission
+	}
+	return err
+}
+
+func VerifySubmissionCommit(ctx context.Context, repoURL, commitHash string, excludeID *uuid.UUID) (string, error) {
+	if !githost.IsCommitHash(commitHash) {
+		return "", githost.ErrCommitNotFound
+	}
+
+	resolved := commitHash
+	if host := githost.Current(); host != nil {
+		ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+		defer cancel()
+
+		if err := host.VerifyRepository(ctx, repoURL); err != nil {
+			return "", err
+		}
+
+		sha, err := host.ResolveCommit(ctx, repoURL, commitHash)
+		if err != nil {
+			return "", err
+		}
+		resolved = sha
+	}
+
+	query := database.DB.Model(&models.ProjectVSubmission{}).
+		Where("LOWER(REGEXP_REPLACE(github_repo, '(\\.git)?/*$', '')) = ?", githost.NormalizeRepoURL(repoURL)).
+		Where("LOWER(commit_hash) IN ?", []string{strings.ToLower(resolved), strings.ToLower(commitHash)})
+	if excludeID != nil {
+		query = query.Where("id <> ?", *excludeID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return "", err
+	}
+	if count > 0 {
+		return "", ErrDuplicateSubmission
+	}
+
+	return resolved, nil
+}
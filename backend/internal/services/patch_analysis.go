@@ -0,0 +1,137 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/adzzatxperts/backend/internal/patch"
+	"github.com/adzzatxperts/backend/internal/workflow"
+	"github.com/google/uuid"
+)
+
+type PatchFeedbackRules struct {
+	Analysis     patch.Rules
+	AutoFeedback map[string]bool
+}
+
+func LoadPatchFeedbackRules() PatchFeedbackRules {
+	codes := os.Getenv("PATCH_FEEDBACK_RULES")
+	if codes == "" {
+		codes = strings.Join([]string{
+			patch.FindingSolutionTouchesTests,
+			patch.FindingPatchesOverlap,
+			patch.FindingEmptyPatch,
+		}, ",")
+	}
+
+	rules := PatchFeedbackRules{
+		Analysis:     patch.Rules{MaxSolutionLines: envInt("PATCH_MAX_SOLUTION_LINES", 0)},
+		AutoFeedback: make(map[string]bool),
+	}
+	for _, code := range strings.Split(codes, ",") {
+		if code = strings.ToUpper(strings.TrimSpace(code)); code != "" && code != "NONE" {
+			rules.AutoFeedback[code] = true
+		}
+	}
+	return rules
+}
+
+func analyzeSubmissionPatches(submission *models.ProjectVSubmission, testPatchPath, solutionPatchPath string, logs *validationLog) (*patch.Report, bool, error) {
+	testPatch, err := os.ReadFile(testPatchPath)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read test patch: %w", err)
+	}
+	solutionPatch, err := os.ReadFile(solutionPatchPath)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read solution patch: %w", err)
+	}
+
+	rules := LoadPatchFeedbackRules()
+	report := patch.Analyze(testPatch, solutionPatch, rules.Analysis)
+
+	for name, parsed := range map[string]*patch.Patch{"Test": report.TestPatch, "Solution": report.SolutionPatch} {
+		if parsed != nil {
+			logs.Add(fmt.Sprintf("  %s patch: %d files, +%d/-%d lines", name, len(parsed.Files), parsed.Added, parsed.Removed))
+		}
+	}
+	for _, finding := range report.Findings {
+		logs.Add(fmt.Sprintf("  [%s] %s: %s", finding.Severity, finding.Code, finding.Message))
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, false, err
+	}
+	encoded := string(data)
+	submission.PatchAnalysis = &encoded
+	database.DB.Model(submission).Update("patch_analysis", submission.PatchAnalysis)
+
+	sentBack := false
+	if matched := report.Matching(rules.AutoFeedback); len(matched) > 0 {
+		sentBack = sendPatchFeedback(submission, matched, logs)
+	}
+
+	return report, sentBack, nil
+}
+
+func sendPatchFeedback(submission *models.ProjectVSubmission, findings []patch.Finding, logs *validationLog) bool {
+	var current models.ProjectVSubmission
+	if err := database.DB.First(&current, submission.ID).Error; err != nil {
+		return false
+	}
+	if current.Status != models.ProjectVStatusSubmitted && current.Status != models.ProjectVStatusInTesting {
+		return false
+	}
+
+	lines := []string{"Automated patch checks found problems with this task:"}
+	codes := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		line := "- " + finding.Message
+		if len(finding.Files) > 0 {
+			line += ": " + strings.Join(finding.Files, ", ")
+		}
+		lines = append(lines, line)
+		codes = append(codes, finding.Code)
+	}
+	feedback := strings.Join(lines, "\n")
+
+	if err := workflow.Apply(&current, workflow.ActionSendFeedback, workflow.SystemActor, workflow.Params{Feedback: feedback}); err != nil {
+		logs.Add(fmt.Sprintf("  Failed to send automated feedback: %v", err))
+		return false
+	}
+	submission.Status = models.ProjectVStatusRework
+	submission.TesterFeedback = feedback
+	if _, err := OpenFeedbackThreads(current.ID, nil, workflow.RoleSystem, feedback, nil); err != nil {
+		logs.Add(fmt.Sprintf("  Failed to open feedback thread: %v", err))
+	}
+	logs.Add("  Sent automated feedback to the contributor and moved the task to rework")
+
+	if validationHub != nil {
+		validationHub.BroadcastToUser(current.ContributorID, "notification", map[string]interface{}{
+			"title":   "Changes needed on " + current.Title,
+			"message": feedback,
+		})
+	}
+
+	systemID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	userName := "System"
+	userRole := "SYSTEM"
+	targetType := "projectv_submission"
+	LogActivity(LogActivityParams{
+		Action:      "AUTO_TESTER_FEEDBACK",
+		Description: fmt.Sprintf("Automated patch checks sent \"%s\" back for rework", current.Title),
+		UserID:      &systemID,
+		UserName:    &userName,
+		UserRole:    &userRole,
+		TargetID:    &current.ID,
+		TargetType:  &targetType,
+		Metadata: map[string]interface{}{
+			"findings": codes,
+		},
+	})
+	return true
+}
@@ -1,6 +1,9 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -12,239 +15,467 @@ import (
 	"github.com/adzzatxperts/backend/internal/database"
 	"github.com/adzzatxperts/backend/internal/models"
 	"github.com/adzzatxperts/backend/internal/storage"
+	"github.com/adzzatxperts/backend/internal/testreport"
 	"github.com/google/uuid"
 )
 
-func ProcessProjectVSubmission(submissionID uuid.UUID) {
+const testReportMount = "/test-reports"
+
+func ProcessProjectVSubmission(ctx context.Context, submissionID uuid.UUID, job *models.ValidationJob) (err error) {
 	var submission models.ProjectVSubmission
 	if err := database.DB.First(&submission, submissionID).Error; err != nil {
 		log.Printf("Failed to find submission %s: %v", submissionID, err)
-		return
+		return fmt.Errorf("failed to find submission: %w", err)
 	}
 
-	logs := []string{}
-	workDir := filepath.Join(os.TempDir(), "projectv", submissionID.String())
+	database.DB.Model(&submission).Updates(map[string]interface{}{
+		"processing_logs":     "",
+		"processing_complete": false,
+		"fail_to_pass":        nil,
+		"pass_to_pass":        nil,
+		"pass_to_fail":        nil,
+		"patch_analysis":      nil,
+	})
+
+	logs := &validationLog{}
+	recorder, err := startValidationRun(&submission, job, logs)
+	if err != nil {
+		return fmt.Errorf("failed to create validation run: %w", err)
+	}
+
+	runTag := recorder.run.ID.String()[:8]
+	workDir := filepath.Join(os.TempDir(), "projectv", submissionID.String()+"-"+runTag)
+	reportsDir := workDir + "-reports"
+	patchesDir := workDir + "-patches"
 
 	defer func() {
+		if ctxErr := ctx.Err(); ctxErr != nil && err == nil {
+			logs.Add(fmt.Sprintf("ERROR: Validation interrupted: %v", ctxErr))
+			err = ctxErr
+		}
+
+		recorder.complete(err)
 
-		submission.ProcessingLogs = strings.Join(logs, "\n")
-		database.DB.Save(&submission)
+		database.DB.Model(&submission).Updates(map[string]interface{}{
+			"processing_complete": validationFinished(ctx, submissionID, job, err),
+			"processing_logs":     logs.String(),
+		})
 
 		os.RemoveAll(workDir)
+		os.RemoveAll(reportsDir)
+		os.RemoveAll(patchesDir)
 	}()
 
 	if err := os.MkdirAll(workDir, 0755); err != nil {
-		logs = append(logs, fmt.Sprintf("ERROR: Failed to create work directory: %v", err))
-		submission.ProcessingComplete = true
-		return
+		logs.Add(fmt.Sprintf("ERROR: Failed to create work directory: %v", err))
+		return fmt.Errorf("failed to create work directory: %w", err)
 	}
 
-	logs = append(logs, "Step 1: Cloning repository...")
-	if err := cloneRepo(submission.GithubRepo, submission.CommitHash, workDir, &logs); err != nil {
-		submission.CloneError = err.Error()
-		submission.ProcessingComplete = true
-		logs = append(logs, fmt.Sprintf("ERROR: Clone failed: %v", err))
-		return
+	recorder.startStep(models.StepClone)
+	logs.Add("Step 1: Cloning repository...")
+	cache := GetRepoCache()
+	if cache != nil {
+		err := cache.Checkout(ctx, submission.GithubRepo, submission.CommitHash, workDir, logs)
+		switch {
+		case errors.Is(err, ErrRepoCacheEntryInUse):
+			logs.Add("  Cache mirror is in use by another validation, cloning directly...")
+			cache = nil
+		case err != nil:
+			logs.Add(fmt.Sprintf("ERROR: Clone failed: %v", err))
+			recorder.failStep(err, err.Error())
+			return nil
+		default:
+			defer cache.Release(submission.GithubRepo, workDir)
+		}
 	}
-	submission.CloneSuccess = true
-	database.DB.Save(&submission)
-	logs = append(logs, "✓ Repository cloned successfully")
-
-	logs = append(logs, "\nStep 2: Applying test patch...")
-	testPatchPath := filepath.Join(workDir, "test.patch")
+	if cache == nil {
+		if err := cloneRepo(ctx, submission.GithubRepo, submission.CommitHash, workDir, logs); err != nil {
+			logs.Add(fmt.Sprintf("ERROR: Clone failed: %v", err))
+			recorder.failStep(err, err.Error())
+			return nil
+		}
+	}
+	logs.Add("✓ Repository cloned successfully")
+	recorder.passStep(nil)
+
+	recorder.startStep(models.StepPatchAnalysis)
+	logs.Add("\nStep 2: Analyzing patches...")
+	if err := os.MkdirAll(patchesDir, 0755); err != nil {
+		recorder.failStep(nil, err.Error())
+		return fmt.Errorf("failed to create patch directory: %w", err)
+	}
+	testPatchPath := filepath.Join(patchesDir, "test.patch")
 	if err := downloadFile(submission.TestPatchURL, testPatchPath); err != nil {
-		submission.TestPatchError = fmt.Sprintf("Failed to download test patch: %v", err)
-		submission.ProcessingComplete = true
-		logs = append(logs, fmt.Sprintf("ERROR: %s", submission.TestPatchError))
-		return
+		message := fmt.Sprintf("Failed to download test patch: %v", err)
+		logs.Add(fmt.Sprintf("ERROR: %s", message))
+		recorder.failStep(nil, message)
+		return fmt.Errorf("failed to download test patch: %w", err)
 	}
-
-	if err := applyPatch(workDir, testPatchPath, &logs); err != nil {
-		submission.TestPatchError = err.Error()
-		submission.ProcessingComplete = true
-		logs = append(logs, fmt.Sprintf("ERROR: Test patch application failed: %v", err))
-		return
+	solutionPatchPath := filepath.Join(patchesDir, "solution.patch")
+	if err := downloadFile(submission.SolutionPatchURL, solutionPatchPath); err != nil {
+		message := fmt.Sprintf("Failed to download solution patch: %v", err)
+		logs.Add(fmt.Sprintf("ERROR: %s", message))
+		recorder.failStep(nil, message)
+		return fmt.Errorf("failed to download solution patch: %w", err)
+	}
+	_, sentBack, err := analyzeSubmissionPatches(&submission, testPatchPath, solutionPatchPath, logs)
+	if err != nil {
+		logs.Add(fmt.Sprintf("ERROR: Patch analysis failed: %v", err))
+		recorder.failStep(nil, err.Error())
+		return nil
+	}
+	if sentBack {
+		message := "Sent back for rework by automated patch checks, remaining steps were not run"
+		logs.Add(fmt.Sprintf("✗ %s", message))
+		recorder.failStep(nil, message)
+		return nil
 	}
-	submission.TestPatchSuccess = true
-	database.DB.Save(&submission)
-	logs = append(logs, "✓ Test patch applied successfully")
+	logs.Add("✓ Patch analysis complete")
+	recorder.passStep(nil)
+
+	recorder.startStep(models.StepTestPatch)
+	logs.Add("\nStep 3: Applying test patch...")
+	if err := applyPatch(ctx, workDir, testPatchPath, logs); err != nil {
+		logs.Add(fmt.Sprintf("ERROR: Test patch application failed: %v", err))
+		recorder.failStep(err, err.Error())
+		return nil
+	}
+	logs.Add("✓ Test patch applied successfully")
+	recorder.passStep(nil)
 
-	logs = append(logs, "\nStep 3: Setting up Dockerfile...")
+	recorder.startStep(models.StepDockerfile)
+	logs.Add("\nStep 4: Setting up Dockerfile...")
 	dockerfilePath := filepath.Join(workDir, "Dockerfile")
 	if err := downloadFile(submission.DockerfileURL, dockerfilePath); err != nil {
-		submission.DockerBuildError = fmt.Sprintf("Failed to download Dockerfile: %v", err)
-		submission.ProcessingComplete = true
-		logs = append(logs, fmt.Sprintf("ERROR: %s", submission.DockerBuildError))
-		return
-	}
-	logs = append(logs, "✓ Dockerfile downloaded successfully")
-
-	logs = append(logs, "\nStep 4: Building Docker image...")
-	imageName := fmt.Sprintf("projectv-%s:initial", submissionID.String())
-	if err := buildDockerImage(workDir, imageName, &logs); err != nil {
-		submission.DockerBuildError = err.Error()
-		submission.ProcessingComplete = true
-		logs = append(logs, fmt.Sprintf("ERROR: Docker build failed: %v", err))
-		return
-	}
-	submission.DockerBuildSuccess = true
-	database.DB.Save(&submission)
-	logs = append(logs, "✓ Docker image built successfully")
-
-	logs = append(logs, "\nStep 5: Running base mode tests...")
-	if err := runDockerTests(imageName, "base", &logs); err != nil {
-		submission.BaseTestError = err.Error()
-		submission.ProcessingComplete = true
-		logs = append(logs, fmt.Sprintf("ERROR: Base tests failed (they should pass): %v", err))
-		return
-	}
-	submission.BaseTestSuccess = true
-	database.DB.Save(&submission)
-	logs = append(logs, "✓ Base tests passed as expected")
-
-	logs = append(logs, "\nStep 6: Running new mode tests...")
-	if err := runDockerTests(imageName, "new", &logs); err == nil {
-
-		submission.NewTestError = "New tests passed but they should have failed"
-		submission.ProcessingComplete = true
-		logs = append(logs, "ERROR: New tests passed but they should have failed")
-		return
-	}
-	submission.NewTestSuccess = true
-	database.DB.Save(&submission)
-	logs = append(logs, "✓ New tests failed as expected")
-
-	logs = append(logs, "\nStep 7: Applying solution patch...")
-	solutionPatchPath := filepath.Join(workDir, "solution.patch")
-	if err := downloadFile(submission.SolutionPatchURL, solutionPatchPath); err != nil {
-		submission.SolutionPatchError = fmt.Sprintf("Failed to download solution patch: %v", err)
-		submission.ProcessingComplete = true
-		logs = append(logs, fmt.Sprintf("ERROR: %s", submission.SolutionPatchError))
-		return
-	}
-
-	if err := applyPatch(workDir, solutionPatchPath, &logs); err != nil {
-		submission.SolutionPatchError = err.Error()
-		submission.ProcessingComplete = true
-		logs = append(logs, fmt.Sprintf("ERROR: Solution patch application failed: %v", err))
-		return
-	}
-	submission.SolutionPatchSuccess = true
-	database.DB.Save(&submission)
-	logs = append(logs, "✓ Solution patch applied successfully")
-
-	logs = append(logs, "\nStep 8: Rebuilding Docker image with solution...")
-	imageNameFinal := fmt.Sprintf("projectv-%s:final", submissionID.String())
-	if err := buildDockerImage(workDir, imageNameFinal, &logs); err != nil {
-		submission.FinalBaseTestError = fmt.Sprintf("Failed to rebuild Docker: %v", err)
-		submission.ProcessingComplete = true
-		logs = append(logs, fmt.Sprintf("ERROR: Docker rebuild failed: %v", err))
-		return
-	}
-	logs = append(logs, "✓ Docker image rebuilt successfully")
-
-	logs = append(logs, "\nStep 9: Running base mode tests after solution...")
-	if err := runDockerTests(imageNameFinal, "base", &logs); err != nil {
-		submission.FinalBaseTestError = err.Error()
-		submission.ProcessingComplete = true
-		logs = append(logs, fmt.Sprintf("ERROR: Final base tests failed: %v", err))
-		return
-	}
-	submission.FinalBaseTestSuccess = true
-	database.DB.Save(&submission)
-	logs = append(logs, "✓ Final base tests passed")
-
-	logs = append(logs, "\nStep 10: Running new mode tests after solution...")
-	if err := runDockerTests(imageNameFinal, "new", &logs); err != nil {
-		submission.FinalNewTestError = err.Error()
-		submission.ProcessingComplete = true
-		logs = append(logs, fmt.Sprintf("ERROR: Final new tests failed: %v", err))
-		return
-	}
-	submission.FinalNewTestSuccess = true
-	database.DB.Save(&submission)
-	logs = append(logs, "✓ Final new tests passed")
-
-	submission.ProcessingComplete = true
-	logs = append(logs, "\n✓✓✓ All validation steps completed successfully! ✓✓✓")
-
-	exec.Command("docker", "rmi", imageName).Run()
-	exec.Command("docker", "rmi", imageNameFinal).Run()
+		message := fmt.Sprintf("Failed to download Dockerfile: %v", err)
+		logs.Add(fmt.Sprintf("ERROR: %s", message))
+		recorder.failStep(nil, message)
+		return fmt.Errorf("failed to download Dockerfile: %w", err)
+	}
+	logs.Add("✓ Dockerfile downloaded successfully")
+	recorder.passStep(nil)
+
+	runtime := getContainerRuntime()
+	sandbox := LoadSandboxConfig()
+	imageName := fmt.Sprintf("projectv-%s-%s:initial", submissionID.String(), runTag)
+	imageNameFinal := fmt.Sprintf("projectv-%s-%s:final", submissionID.String(), runTag)
+	defer func() {
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		runtime.RemoveImage(cleanupCtx, imageName)
+		runtime.RemoveImage(cleanupCtx, imageNameFinal)
+	}()
+
+	recorder.startStep(models.StepDockerBuild)
+	recorder.recordLimits(sandbox.Build)
+	logs.Add(fmt.Sprintf("\nStep 5: Building Docker image (%s runtime)...", runtime.Name()))
+	if err := buildDockerImage(ctx, runtime, workDir, imageName, sandbox, logs); err != nil {
+		logs.Add(fmt.Sprintf("ERROR: Docker build failed: %v", err))
+		recorder.failStep(err, err.Error())
+		return nil
+	}
+	logs.Add("✓ Docker image built successfully")
+	recorder.passStep(nil)
+
+	recorder.startStep(models.StepBaseTests)
+	recorder.recordLimits(sandbox.Test)
+	logs.Add("\nStep 6: Running base mode tests...")
+	baseSummary, err := runDockerTests(ctx, runtime, imageName, "base", filepath.Join(reportsDir, "initial-base"), sandbox, logs)
+	recorder.recordTestSummary(baseSummary)
+	if err != nil {
+		logs.Add(fmt.Sprintf("ERROR: Base tests failed (they should pass): %v", err))
+		recorder.failStep(err, err.Error())
+		return nil
+	}
+	logs.Add("✓ Base tests passed as expected")
+	recorder.passStep(nil)
+
+	recorder.startStep(models.StepNewTests)
+	recorder.recordLimits(sandbox.Test)
+	logs.Add("\nStep 7: Running new mode tests...")
+	newSummary, newTestErr := runDockerTests(ctx, runtime, imageName, "new", filepath.Join(reportsDir, "initial-new"), sandbox, logs)
+	recorder.recordTestSummary(newSummary)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if newTestErr == nil {
+		logs.Add("ERROR: New tests passed but they should have failed")
+		recorder.failStep(nil, "New tests passed but they should have failed")
+		return nil
+	}
+	if newSummary != nil && newSummary.Failed == 0 {
+		message := "New tests exited with an error but the test report contains no failing tests"
+		logs.Add(fmt.Sprintf("ERROR: %s", message))
+		recorder.failStep(newTestErr, message)
+		return nil
+	}
+	logs.Add("✓ New tests failed as expected")
+	recorder.passStep(newTestErr)
+
+	recorder.startStep(models.StepSolutionPatch)
+	logs.Add("\nStep 8: Applying solution patch...")
+	if err := applyPatch(ctx, workDir, solutionPatchPath, logs); err != nil {
+		logs.Add(fmt.Sprintf("ERROR: Solution patch application failed: %v", err))
+		recorder.failStep(err, err.Error())
+		return nil
+	}
+	logs.Add("✓ Solution patch applied successfully")
+	recorder.passStep(nil)
+
+	recorder.startStep(models.StepDockerRebuild)
+	recorder.recordLimits(sandbox.Build)
+	logs.Add("\nStep 9: Rebuilding Docker image with solution...")
+	if err := buildDockerImage(ctx, runtime, workDir, imageNameFinal, sandbox, logs); err != nil {
+		logs.Add(fmt.Sprintf("ERROR: Docker rebuild failed: %v", err))
+		recorder.failStep(err, fmt.Sprintf("Failed to rebuild Docker: %v", err))
+		return nil
+	}
+	logs.Add("✓ Docker image rebuilt successfully")
+	recorder.passStep(nil)
+
+	recorder.startStep(models.StepFinalBaseTests)
+	recorder.recordLimits(sandbox.Test)
+	logs.Add("\nStep 10: Running base mode tests after solution...")
+	finalBaseSummary, err := runDockerTests(ctx, runtime, imageNameFinal, "base", filepath.Join(reportsDir, "final-base"), sandbox, logs)
+	recorder.recordTestSummary(finalBaseSummary)
+	if err != nil {
+		logs.Add(fmt.Sprintf("ERROR: Final base tests failed: %v", err))
+		recorder.failStep(err, err.Error())
+		return nil
+	}
+	logs.Add("✓ Final base tests passed")
+	recorder.passStep(nil)
+
+	recorder.startStep(models.StepFinalNewTests)
+	recorder.recordLimits(sandbox.Test)
+	logs.Add("\nStep 11: Running new mode tests after solution...")
+	finalNewSummary, err := runDockerTests(ctx, runtime, imageNameFinal, "new", filepath.Join(reportsDir, "final-new"), sandbox, logs)
+	recorder.recordTestSummary(finalNewSummary)
+	if err != nil {
+		logs.Add(fmt.Sprintf("ERROR: Final new tests failed: %v", err))
+		recorder.failStep(err, err.Error())
+		return nil
+	}
+	logs.Add("✓ Final new tests passed")
+	recorder.passStep(nil)
+
+	recorder.startStep(models.StepTestTransitions)
+	logs.Add("\nStep 12: Comparing per-test results before and after the solution...")
+	if newSummary == nil || finalNewSummary == nil {
+		message := "No parseable test report: test.sh must emit JUnit XML or TAP so FAIL_TO_PASS can be verified"
+		logs.Add(fmt.Sprintf("ERROR: %s", message))
+		recorder.failStep(nil, message)
+		return nil
+	}
+	transitions := testreport.Compare(
+		testreport.Merge(baseSummary, newSummary),
+		testreport.Merge(finalBaseSummary, finalNewSummary),
+	)
+	logs.Add(fmt.Sprintf("  FAIL_TO_PASS: %d tests, PASS_TO_PASS: %d tests, PASS_TO_FAIL: %d tests",
+		len(transitions.FailToPass), len(transitions.PassToPass), len(transitions.PassToFail)))
+	saveTestTransitions(&submission, transitions)
+
+	if len(transitions.PassToFail) > 0 {
+		message := fmt.Sprintf("Solution breaks previously passing tests: %s", strings.Join(transitions.PassToFail, ", "))
+		logs.Add(fmt.Sprintf("ERROR: %s", message))
+		recorder.failStep(nil, message)
+		return nil
+	}
+	if len(transitions.FailToPass) == 0 {
+		message := "No test flips from failing to passing after applying the solution"
+		logs.Add(fmt.Sprintf("ERROR: %s", message))
+		recorder.failStep(nil, message)
+		return nil
+	}
+	logs.Add("✓ Test transitions verified")
+	recorder.passStep(nil)
+
+	logs.Add("\n✓✓✓ All validation steps completed successfully! ✓✓✓")
+
+	return nil
 }
 
-func cloneRepo(repoURL, commitHash, targetDir string, logs *[]string) error {
-	*logs = append(*logs, fmt.Sprintf("  Cloning %s...", repoURL))
+func validationFinished(ctx context.Context, submissionID uuid.UUID, job *models.ValidationJob, err error) bool {
+	switch {
+	case err == nil, job == nil:
+		return true
+	case ctx.Err() != nil:
+		var active int64
+		database.DB.Model(&models.ValidationJob{}).
+			Where("submission_id = ? AND id <> ? AND status IN ?", submissionID, job.ID, activeJobStatuses).
+			Count(&active)
+		return active == 0
+	default:
+		return job.Attempts >= job.MaxAttempts
+	}
+}
+
+func cloneRepo(ctx context.Context, repoURL, commitHash, targetDir string, logs *validationLog) error {
+	logs.Add(fmt.Sprintf("  Cloning %s...", repoURL))
 
-	cmd := exec.Command("git", "clone", repoURL, targetDir)
+	cmd := exec.CommandContext(ctx, "git", "clone", repoURL, targetDir)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		*logs = append(*logs, fmt.Sprintf("  Git clone output: %s", string(output)))
+		logs.Add(fmt.Sprintf("  Git clone output: %s", string(output)))
 		return fmt.Errorf("git clone failed: %w", err)
 	}
 
-	*logs = append(*logs, fmt.Sprintf("  Checking out commit %s...", commitHash))
-	cmd = exec.Command("git", "-C", targetDir, "checkout", commitHash)
+	logs.Add(fmt.Sprintf("  Checking out commit %s...", commitHash))
+	cmd = exec.CommandContext(ctx, "git", "-C", targetDir, "checkout", commitHash)
 	output, err = cmd.CombinedOutput()
 	if err != nil {
-		*logs = append(*logs, fmt.Sprintf("  Git checkout output: %s", string(output)))
+		logs.Add(fmt.Sprintf("  Git checkout output: %s", string(output)))
 		return fmt.Errorf("git checkout failed: %w", err)
 	}
 
 	return nil
 }
 
-func downloadFile(fileKey, targetPath string) error {
-
-	return storage.DownloadFileToPath(fileKey, targetPath)
-}
+var downloadFile = storage.DownloadFileToPath
 
-func applyPatch(workDir, patchPath string, logs *[]string) error {
-	*logs = append(*logs, fmt.Sprintf("  Applying patch: %s", filepath.Base(patchPath)))
+func applyPatch(ctx context.Context, workDir, patchPath string, logs *validationLog) error {
+	logs.Add(fmt.Sprintf("  Applying patch: %s", filepath.Base(patchPath)))
 
-	cmd := exec.Command("git", "-C", workDir, "apply", patchPath)
+	cmd := exec.CommandContext(ctx, "git", "-C", workDir, "apply", patchPath)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		*logs = append(*logs, fmt.Sprintf("  Patch output: %s", string(output)))
+		logs.Add(fmt.Sprintf("  Patch output: %s", string(output)))
 		return fmt.Errorf("patch application failed: %w", err)
 	}
 
 	return nil
 }
 
-func buildDockerImage(workDir, imageName string, logs *[]string) error {
-	*logs = append(*logs, fmt.Sprintf("  Building image: %s", imageName))
-
-	cmd := exec.Command("docker", "build", "-t", imageName, workDir)
-	cmd.Dir = workDir
+func buildDockerImage(ctx context.Context, runtime ContainerRuntime, workDir, imageName string, sandbox SandboxConfig, logs *validationLog) error {
+	logs.Add(fmt.Sprintf("  Building image: %s", imageName))
+	logs.Add(fmt.Sprintf("  Limits: %s", describeLimits(sandbox.Build)))
+	if runtime.Name() != "docker" || sandbox.Builder == SandboxBuilderDefault {
+		logs.Add(fmt.Sprintf("  (%s build enforces cpu and memory only, pids and disk are not limited)", runtime.Name()))
+	}
 
-	output, err := cmd.CombinedOutput()
-	*logs = append(*logs, fmt.Sprintf("  Build output: %s", string(output)))
+	buildCtx, cancel := context.WithTimeout(ctx, sandbox.BuildTimeout)
+	defer cancel()
+
+	live := logs.LiveWriter()
+	result, err := runtime.Build(buildCtx, ContainerBuildOptions{
+		ContextDir: workDir,
+		Image:      imageName,
+		Builder:    sandbox.Builder,
+		Limits:     sandbox.Build,
+		Output:     live,
+	})
+	live.Flush()
+
+	if ctx.Err() == nil && buildCtx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("build timeout after %s", sandbox.BuildTimeout)
+	}
+	logs.Record(fmt.Sprintf("  Build output: %s", result.Output))
 
 	if err != nil {
-		return fmt.Errorf("docker build failed: %w", err)
+		return fmt.Errorf("%s build failed: %w", runtime.Name(), err)
 	}
 
 	return nil
 }
 
-func runDockerTests(imageName, mode string, logs *[]string) error {
-	*logs = append(*logs, fmt.Sprintf("  Running tests in %s mode...", mode))
+func runDockerTests(ctx context.Context, runtime ContainerRuntime, imageName, mode, reportDir string, sandbox SandboxConfig, logs *validationLog) (*testreport.Summary, error) {
+	logs.Add(fmt.Sprintf("  Running tests in %s mode...", mode))
+	logs.Add(fmt.Sprintf("  Limits: %s", describeLimits(sandbox.Test)))
 
-	cmd := exec.Command("docker", "run", "--rm", imageName, "./test.sh", mode)
-	cmd.Env = append(os.Environ(), fmt.Sprintf("TEST_MODE=%s", mode))
+	if err := os.MkdirAll(reportDir, 0777); err != nil {
+		return nil, fmt.Errorf("failed to create report directory: %w", err)
+	}
+	os.Chmod(reportDir, 0777)
+
+	testCtx, cancel := context.WithTimeout(ctx, sandbox.TestTimeout)
+	defer cancel()
+
+	containerName := strings.NewReplacer(":", "-", "/", "-").Replace(imageName) + "-" + mode
+	live := logs.LiveWriter()
+	result, err := runtime.Run(testCtx, ContainerRunOptions{
+		Image:   imageName,
+		Name:    containerName,
+		Command: []string{"./test.sh", mode},
+		Env:     map[string]string{"TEST_MODE": mode, "TEST_REPORT_DIR": testReportMount},
+		Mounts:  map[string]string{testReportMount: reportDir},
+		Limits:  sandbox.Test,
+		Output:  live,
+	})
+	live.Flush()
+
+	logs.Record(fmt.Sprintf("  Test stdout (%s): %s", mode, result.Stdout))
+	if result.Stderr != "" {
+		logs.Record(fmt.Sprintf("  Test stderr (%s): %s", mode, result.Stderr))
+	}
+	if result.OutputTruncated {
+		logs.Add("  (test output was truncated)")
+	}
 
-	done := make(chan error, 1)
-	go func() {
-		output, err := cmd.CombinedOutput()
-		*logs = append(*logs, fmt.Sprintf("  Test output (%s): %s", mode, string(output)))
-		done <- err
-	}()
+	if testCtx.Err() != nil {
+		cleanupCtx, cleanupCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cleanupCancel()
+		if rmErr := runtime.RemoveContainer(cleanupCtx, containerName); rmErr != nil {
+			logs.Add(fmt.Sprintf("  WARNING: Failed to remove container %s: %v", containerName, rmErr))
+		} else {
+			logs.Add(fmt.Sprintf("  Removed container %s", containerName))
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("test timeout after %s", sandbox.TestTimeout)
+	}
+
+	summary := testreport.ParseJUnitFiles(reportDir)
+	if summary == nil {
+		summary = testreport.Parse(result.Stdout)
+	}
+	if summary != nil {
+		logs.Add(fmt.Sprintf("  Test summary (%s): %s", mode, summary))
+	}
+
+	return summary, err
+}
+
+func describeLimits(limits ContainerLimits) string {
+	parts := []string{}
+	if limits.CPUs > 0 {
+		parts = append(parts, fmt.Sprintf("cpus=%g", limits.CPUs))
+	}
+	if limits.MemoryMB > 0 {
+		parts = append(parts, fmt.Sprintf("memory=%dMB", limits.MemoryMB))
+	}
+	if limits.PIDs > 0 {
+		parts = append(parts, fmt.Sprintf("pids=%d", limits.PIDs))
+	}
+	if limits.DiskMB > 0 {
+		parts = append(parts, fmt.Sprintf("disk=%dMB", limits.DiskMB))
+	}
+	if limits.Network != "" {
+		parts = append(parts, "network="+limits.Network)
+	}
+	if limits.TimeoutSeconds > 0 {
+		parts = append(parts, fmt.Sprintf("timeout=%ds", limits.TimeoutSeconds))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
 
-	select {
-	case err := <-done:
-		return err
-	case <-time.After(5 * time.Minute):
-		cmd.Process.Kill()
-		return fmt.Errorf("test timeout after 5 minutes")
+func saveTestTransitions(submission *models.ProjectVSubmission, transitions testreport.Transitions) {
+	encode := func(ids []string) *string {
+		data, _ := json.Marshal(ids)
+		encoded := string(data)
+		return &encoded
 	}
+
+	submission.FailToPass = encode(transitions.FailToPass)
+	submission.PassToPass = encode(transitions.PassToPass)
+	submission.PassToFail = encode(transitions.PassToFail)
+	database.DB.Model(submission).Updates(map[string]interface{}{
+		"fail_to_pass": submission.FailToPass,
+		"pass_to_pass": submission.PassToPass,
+		"pass_to_fail": submission.PassToFail,
+	})
 }
@@ -0,0 +1,455 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	testDBOnce sync.Once
+	testDBErr  error
+)
+
+func setupTestDB(t *testing.T) {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	testDBOnce.Do(func() {
+		os.Setenv("DATABASE_URL", dsn)
+		os.Setenv("REPO_CACHE_ENABLED", "false")
+		if testDBErr = database.Connect(); testDBErr != nil {
+			return
+		}
+		database.DB.Logger = logger.Default.LogMode(logger.Silent)
+		if testDBErr = database.AutoMigrate(); testDBErr != nil {
+			return
+		}
+		database.DB.Logger = logger.Default.LogMode(logger.Silent)
+	})
+	if testDBErr != nil {
+		t.Fatalf("failed to prepare test database: %v", testDBErr)
+	}
+}
+
+func createTestUser(t *testing.T, role models.UserRole, name string) models.User {
+	t.Helper()
+
+	user := models.User{
+		Email:        fmt.Sprintf("test-%s-%s@example.test", name, uuid.NewString()[:8]),
+		PasswordHash: "-",
+		Name:         "test " + name,
+		Role:         role,
+		IsApproved:   true,
+		IsGreenLight: true,
+	}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create %s user: %v", name, err)
+	}
+	t.Cleanup(func() {
+		database.DB.Delete(&user)
+	})
+	return user
+}
+
+type processorFixture struct {
+	repo       string
+	commit     string
+	artifacts  map[string]string
+	submission models.ProjectVSubmission
+	runtime    *FakeRuntime
+}
+
+const (
+	fixtureSource       = "src/calc.txt"
+	fixtureBrokenSource = "add = broken\n"
+	fixtureFixedSource  = "add = fixed\n"
+)
+
+func newProcessorFixture(t *testing.T) *processorFixture {
+	t.Helper()
+
+	repo := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.test",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.test")
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+		return string(output)
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(repo, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	write(fixtureSource, fixtureBrokenSource)
+	write("tests/test_existing.txt", "existing\n")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+	commit := strings.TrimSpace(git("rev-parse", "HEAD"))
+
+	write("tests/test_calc.txt", "expects add = fixed\n")
+	git("add", "-A")
+	testPatch := git("diff", "--cached")
+	git("reset", "-q", "--hard")
+
+	write(fixtureSource, fixtureFixedSource)
+	solutionPatch := git("diff")
+	git("checkout", "-q", "--", ".")
+
+	f := &processorFixture{
+		repo:   repo,
+		commit: commit,
+		artifacts: map[string]string{
+			"test.patch":     testPatch,
+			"solution.patch": solutionPatch,
+			"Dockerfile":     "FROM alpine\nCOPY . /app\n",
+		},
+		runtime: NewFakeRuntime(),
+	}
+	f.runtime.RunHandler = fixtureTests(nil)
+
+	contributor := createTestUser(t, models.RoleContributor, "contributor")
+	f.submission = models.ProjectVSubmission{
+		Title:            "Processor fixture",
+		Language:         "go",
+		Category:         "bug",
+		Difficulty:       "easy",
+		Description:      "Fixture for validation processor tests",
+		GithubRepo:       repo,
+		CommitHash:       commit,
+		TestPatchURL:     "test.patch",
+		DockerfileURL:    "Dockerfile",
+		SolutionPatchURL: "solution.patch",
+		Status:           models.ProjectVStatusSubmitted,
+		ContributorID:    contributor.ID,
+	}
+	return f
+}
+
+func fixtureTests(override func(mode string, solved bool) (string, int, bool)) func(ContainerRunOptions, *FakeImage) ContainerResult {
+	return func(opts ContainerRunOptions, image *FakeImage) ContainerResult {
+		mode := opts.Env["TEST_MODE"]
+		solved := image.HasFileContaining(fixtureSource, "fixed")
+		if override != nil {
+			if stdout, exitCode, ok := override(mode, solved); ok {
+				return ContainerResult{Stdout: stdout, ExitCode: exitCode}
+			}
+		}
+
+		if mode == "base" {
+			return ContainerResult{Stdout: "1..1\nok 1 - test_existing\n"}
+		}
+		if !image.HasFileContaining("tests/test_calc.txt", "expects") {
+			return ContainerResult{Stdout: "no tests found\n", ExitCode: 1}
+		}
+		if solved {
+			return ContainerResult{Stdout: "1..1\nok 1 - test_calc\n"}
+		}
+		return ContainerResult{Stdout: "1..1\nnot ok 1 - test_calc\n", ExitCode: 1}
+	}
+}
+
+func (f *processorFixture) run(t *testing.T) (*models.ValidationRun, models.ProjectVSubmission, error) {
+	t.Helper()
+
+	originalDownload := downloadFile
+	downloadFile = func(key, target string) error {
+		content, ok := f.artifacts[key]
+		if !ok {
+			return fmt.Errorf("object %q not found", key)
+		}
+		return os.WriteFile(target, []byte(content), 0644)
+	}
+	SetContainerRuntime(f.runtime)
+	t.Cleanup(func() {
+		downloadFile = originalDownload
+		SetContainerRuntime(nil)
+	})
+
+	if err := database.DB.Create(&f.submission).Error; err != nil {
+		t.Fatalf("failed to create submission: %v", err)
+	}
+	t.Cleanup(func() {
+		database.DB.Where("subject_id = ?", f.submission.ID).Delete(&models.StatusTransition{})
+		database.DB.Where("submission_id = ?", f.submission.ID).Delete(&models.Comment{})
+		database.DB.Where("submission_id = ?", f.submission.ID).Delete(&models.ValidationRun{})
+		database.DB.Delete(&f.submission)
+	})
+
+	processErr := ProcessProjectVSubmission(context.Background(), f.submission.ID, nil)
+
+	runs, err := GetValidationRuns(f.submission.ID)
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("expected one validation run, got %d (%v)", len(runs), err)
+	}
+	var submission models.ProjectVSubmission
+	database.DB.First(&submission, f.submission.ID)
+	return &runs[0], submission, processErr
+}
+
+func stepStatuses(run *models.ValidationRun) map[string]models.ValidationStepStatus {
+	statuses := make(map[string]models.ValidationStepStatus)
+	for _, step := range run.Steps {
+		statuses[step.Name] = step.Status
+	}
+	return statuses
+}
+
+func TestProcessProjectVSubmissionPasses(t *testing.T) {
+	setupTestDB(t)
+	f := newProcessorFixture(t)
+
+	run, submission, err := f.run(t)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if run.Status != models.ValidationRunPassed {
+		t.Fatalf("run status = %s (%s), want %s", run.Status, run.Error, models.ValidationRunPassed)
+	}
+	for name, status := range stepStatuses(run) {
+		if status != models.ValidationStepPassed {
+			t.Errorf("step %s = %s, want %s", name, status, models.ValidationStepPassed)
+		}
+	}
+	if !submission.ProcessingComplete {
+		t.Error("processing_complete is false after a successful run")
+	}
+	if submission.FailToPass == nil || !strings.Contains(*submission.FailToPass, "test_calc") {
+		t.Errorf("FAIL_TO_PASS = %v, want test_calc", submission.FailToPass)
+	}
+	if submission.PassToPass == nil || !strings.Contains(*submission.PassToPass, "test_existing") {
+		t.Errorf("PASS_TO_PASS = %v, want test_existing", submission.PassToPass)
+	}
+
+	builds := 0
+	for _, call := range f.runtime.Calls() {
+		if call.Op == "build" {
+			builds++
+		}
+	}
+	if builds != 2 {
+		t.Errorf("expected 2 image builds, got %d", builds)
+	}
+}
+
+func TestProcessProjectVSubmissionStepFailures(t *testing.T) {
+	setupTestDB(t)
+
+	failBuild := func(suffix string) func(ContainerBuildOptions, *FakeImage) ContainerResult {
+		return func(opts ContainerBuildOptions, image *FakeImage) ContainerResult {
+			if strings.HasSuffix(opts.Image, suffix) {
+				return ContainerResult{Output: "RUN make: exit status 2", ExitCode: 2}
+			}
+			return ContainerResult{Output: "built " + opts.Image}
+		}
+	}
+
+	cases := []struct {
+		name    string
+		step    string
+		prepare func(f *processorFixture)
+	}{
+		{
+			name: "unknown commit",
+			step: models.StepClone,
+			prepare: func(f *processorFixture) {
+				f.submission.CommitHash = strings.Repeat("0", 40)
+			},
+		},
+		{
+			name: "missing test patch",
+			step: models.StepPatchAnalysis,
+			prepare: func(f *processorFixture) {
+				delete(f.artifacts, "test.patch")
+			},
+		},
+		{
+			name: "automated feedback sends the task back",
+			step: models.StepPatchAnalysis,
+			prepare: func(f *processorFixture) {
+				f.artifacts["solution.patch"] += f.artifacts["test.patch"]
+			},
+		},
+		{
+			name: "test patch does not apply",
+			step: models.StepTestPatch,
+			prepare: func(f *processorFixture) {
+				f.artifacts["test.patch"] = "diff --git a/tests/test_existing.txt b/tests/test_existing.txt\n" +
+					"--- a/tests/test_existing.txt\n" +
+					"+++ b/tests/test_existing.txt\n" +
+					"@@ -1 +1 @@\n" +
+					"-missing\n" +
+					"+expects add = fixed\n"
+			},
+		},
+		{
+			name: "missing Dockerfile",
+			step: models.StepDockerfile,
+			prepare: func(f *processorFixture) {
+				delete(f.artifacts, "Dockerfile")
+			},
+		},
+		{
+			name: "image build fails",
+			step: models.StepDockerBuild,
+			prepare: func(f *processorFixture) {
+				f.runtime.BuildHandler = failBuild(":initial")
+			},
+		},
+		{
+			name: "base tests fail",
+			step: models.StepBaseTests,
+			prepare: func(f *processorFixture) {
+				f.runtime.RunHandler = fixtureTests(func(mode string, solved bool) (string, int, bool) {
+					return "1..1\nnot ok 1 - test_existing\n", 1, mode == "base" && !solved
+				})
+			},
+		},
+		{
+			name: "new tests pass before the solution",
+			step: models.StepNewTests,
+			prepare: func(f *processorFixture) {
+				f.runtime.RunHandler = fixtureTests(func(mode string, solved bool) (string, int, bool) {
+					return "1..1\nok 1 - test_calc\n", 0, mode == "new"
+				})
+			},
+		},
+		{
+			name: "new tests error without failing cases",
+			step: models.StepNewTests,
+			prepare: func(f *processorFixture) {
+				f.runtime.RunHandler = fixtureTests(func(mode string, solved bool) (string, int, bool) {
+					return "1..1\nok 1 - test_calc\n", 1, mode == "new" && !solved
+				})
+			},
+		},
+		{
+			name: "solution patch does not apply",
+			step: models.StepSolutionPatch,
+			prepare: func(f *processorFixture) {
+				f.artifacts["solution.patch"] = strings.Replace(f.artifacts["solution.patch"], "-add = broken", "-add = missing", 1)
+			},
+		},
+		{
+			name: "image rebuild fails",
+			step: models.StepDockerRebuild,
+			prepare: func(f *processorFixture) {
+				f.runtime.BuildHandler = failBuild(":final")
+			},
+		},
+		{
+			name: "base tests fail after the solution",
+			step: models.StepFinalBaseTests,
+			prepare: func(f *processorFixture) {
+				f.runtime.RunHandler = fixtureTests(func(mode string, solved bool) (string, int, bool) {
+					return "1..1\nnot ok 1 - test_existing\n", 1, mode == "base" && solved
+				})
+			},
+		},
+		{
+			name: "new tests fail after the solution",
+			step: models.StepFinalNewTests,
+			prepare: func(f *processorFixture) {
+				f.runtime.RunHandler = fixtureTests(func(mode string, solved bool) (string, int, bool) {
+					return "1..1\nnot ok 1 - test_calc\n", 1, mode == "new" && solved
+				})
+			},
+		},
+		{
+			name: "solution drops a passing test",
+			step: models.StepTestTransitions,
+			prepare: func(f *processorFixture) {
+				f.runtime.RunHandler = fixtureTests(func(mode string, solved bool) (string, int, bool) {
+					return "1..1\nok 1 - test_renamed\n", 0, mode == "base" && solved
+				})
+			},
+		},
+		{
+			name: "no parseable test report",
+			step: models.StepTestTransitions,
+			prepare: func(f *processorFixture) {
+				f.runtime.RunHandler = fixtureTests(func(mode string, solved bool) (string, int, bool) {
+					if mode == "new" && !solved {
+						return "calc test failed\n", 1, true
+					}
+					return "all good\n", 0, true
+				})
+			},
+		},
+		{
+			name: "no test flips to passing",
+			step: models.StepTestTransitions,
+			prepare: func(f *processorFixture) {
+				f.runtime.RunHandler = fixtureTests(func(mode string, solved bool) (string, int, bool) {
+					return "1..1\nnot ok 1 - test_calc\n", 0, mode == "new" && solved
+				})
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newProcessorFixture(t)
+			tc.prepare(f)
+
+			run, submission, _ := f.run(t)
+			if run.Status != models.ValidationRunFailed && run.Status != models.ValidationRunErrored {
+				t.Fatalf("run status = %s, want %s or %s", run.Status, models.ValidationRunFailed, models.ValidationRunErrored)
+			}
+
+			reached := false
+			statuses := stepStatuses(run)
+			for _, name := range models.ValidationStepNames {
+				status := statuses[name]
+				switch {
+				case name == tc.step:
+					reached = true
+					if status != models.ValidationStepFailed {
+						t.Errorf("step %s = %s, want %s", name, status, models.ValidationStepFailed)
+					}
+				case !reached && status != models.ValidationStepPassed:
+					t.Errorf("step %s before the failure = %s, want %s", name, status, models.ValidationStepPassed)
+				case reached && status != models.ValidationStepSkipped:
+					t.Errorf("step %s after the failure = %s, want %s", name, status, models.ValidationStepSkipped)
+				}
+			}
+
+			if run.Status == models.ValidationRunFailed && !submission.ProcessingComplete {
+				t.Error("processing_complete is false after a failed run")
+			}
+			if tc.step == models.StepPatchAnalysis && run.Status == models.ValidationRunFailed {
+				if submission.Status != models.ProjectVStatusRework {
+					t.Errorf("status = %s, want %s", submission.Status, models.ProjectVStatusRework)
+				}
+				for _, call := range f.runtime.Calls() {
+					t.Errorf("unexpected %s call after the task was sent back", call.Op)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/adzzatxperts/backend/internal/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+)
+
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+type TokenPair struct {
+	AccessToken      string    `json:"accessToken"`
+	RefreshToken     string    `json:"refreshToken"`
+	ExpiresIn        int       `json:"expiresIn"`
+	RefreshExpiresAt time.Time `json:"refreshExpiresAt"`
+}
+
+func IssueTokenPair(user *models.User, client ClientInfo) (*TokenPair, error) {
+	familyID := uuid.New()
+	raw, record, err := newRefreshToken(user.ID, familyID, time.Now(), client)
+	if err != nil {
+		return nil, err
+	}
+	if err := database.DB.Create(record).Error; err != nil {
+		return nil, err
+	}
+	return buildTokenPair(user, raw, record)
+}
+
+func RotateRefreshToken(raw string, client ClientInfo) (*TokenPair, *models.User, error) {
+	var user models.User
+	var next *models.RefreshToken
+	var nextRaw string
+	var reused *models.RefreshToken
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var current models.RefreshToken
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token = ?", utils.HashRefreshToken(raw)).
+			First(&current).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidRefreshToken
+		}
+		if err != nil {
+			return err
+		}
+
+		if current.RevokedAt != nil {
+			reused = &current
+			return revokeTokenFamily(tx, &current)
+		}
+		if !current.ExpiresAt.After(time.Now()) {
+			return ErrInvalidRefreshToken
+		}
+		if err := tx.First(&user, current.UserID).Error; err != nil {
+			return ErrInvalidRefreshToken
+		}
+
+		familyID := current.ID
+		if current.FamilyID != nil {
+			familyID = *current.FamilyID
+		}
+		startedAt := current.CreatedAt
+		if current.StartedAt != nil {
+			startedAt = *current.StartedAt
+		}
+		nextRaw, next, err = newRefreshToken(current.UserID, familyID, startedAt, client)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&current).Updates(map[string]interface{}{
+			"revoked_at":     time.Now(),
+			"replaced_by_id": next.ID,
+		}).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if reused != nil {
+		logRefreshTokenReuse(reused)
+		return nil, nil, ErrRefreshTokenReused
+	}
+
+	pair, err := buildTokenPair(&user, nextRaw, next)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pair, &user, nil
+}
+
+func RevokeRefreshToken(raw string) error {
+	var current models.RefreshToken
+	err := database.DB.Where("token = ?", utils.HashRefreshToken(raw)).First(&current).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return revokeTokenFamily(database.DB, &current)
+}
+
+func RevokeUserRefreshTokens(userID uuid.UUID) error {
+	return database.DB.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func newRefreshToken(userID, familyID uuid.UUID, startedAt time.Time, client ClientInfo) (string, *models.RefreshToken, error) {
+	raw, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return "", nil, err
+	}
+	now := time.Now()
+	return raw, &models.RefreshToken{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Token:      utils.HashRefreshToken(raw),
+		FamilyID:   &familyID,
+		UserAgent:  truncateString(client.UserAgent, 512),
+		IPAddress:  truncateString(client.IPAddress, 64),
+		ExpiresAt:  utils.GetRefreshTokenExpiry(),
+		StartedAt:  &startedAt,
+		LastUsedAt: &now,
+	}, nil
+}
+
+func buildTokenPair(user *models.User, raw string, record *models.RefreshToken) (*TokenPair, error) {
+	accessToken, err := utils.GenerateShortLivedJWT(user.ID.String(), user.Email, string(user.Role), user.TokenVersion, record.FamilyID.String())
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     raw,
+		ExpiresIn:        int(utils.AccessTokenTTL().Seconds()),
+		RefreshExpiresAt: record.ExpiresAt,
+	}, nil
+}
+
+func revokeTokenFamily(tx *gorm.DB, token *models.RefreshToken) error {
+	query := tx.Model(&models.RefreshToken{}).Where("revoked_at IS NULL")
+	if token.FamilyID != nil {
+		query = query.Where("family_id = ?", *token.FamilyID)
+	} else {
+		query = query.Where("id = ?", token.ID)
+	}
+	if err := query.Update("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+	markSessionRevoked(sessionID(token))
+	return nil
+}
+
+func logRefreshTokenReuse(token *models.RefreshToken) {
+	var user models.User
+	if err := database.DB.First(&user, token.UserID).Error; err != nil {
+		return
+	}
+
+	userRole := string(user.Role)
+	targetType := "user"
+	metadata := map[string]interface{}{
+		"tokenId":   token.ID.String(),
+		"revokedAt": token.RevokedAt,
+	}
+	if token.FamilyID != nil {
+		metadata["familyId"] = token.FamilyID.String()
+	}
+	LogActivity(LogActivityParams{
+		Action:      "REFRESH_TOKEN_REUSE",
+		Description: "Revoked refresh token replayed for " + user.Email + ", all sessions in the family were revoked",
+		UserID:      &user.ID,
+		UserName:    &user.Name,
+		UserRole:    &userRole,
+		TargetID:    &user.ID,
+		TargetType:  &targetType,
+		Metadata:    metadata,
+	})
+}
@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrRepoCacheEntryNotFound = errors.New("repository cache entry not found")
+	ErrRepoCacheEntryInUse    = errors.New("repository cache entry is in use")
+)
+
+type RepoCacheEntry struct {
+	Key        string    `json:"key"`
+	URL        string    `json:"url"`
+	SizeBytes  int64     `json:"sizeBytes"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	InUse      int       `json:"inUse"`
+}
+
+type RepoCache struct {
+	root        string
+	budgetBytes int64
+
+	mu     sync.Mutex
+	locks  map[string]*sync.Mutex
+	inUse  map[string]int
+	sizeMu sync.Mutex
+}
+
+var (
+	repoCache     *RepoCache
+	repoCacheOnce sync.Once
+)
+
+func GetRepoCache() *RepoCache {
+	repoCacheOnce.Do(func() {
+		if os.Getenv("REPO_CACHE_ENABLED") == "false" {
+			return
+		}
+
+		root := os.Getenv("REPO_CACHE_DIR")
+		if root == "" {
+			root = filepath.Join(os.TempDir(), "projectv-repo-cache")
+		}
+		repoCache = NewRepoCache(root, int64(envInt("REPO_CACHE_BUDGET_MB", 10240))*1024*1024)
+	})
+	return repoCache
+}
+
+func NewRepoCache(root string, budgetBytes int64) *RepoCache {
+	return &RepoCache{
+		root:        root,
+		budgetBytes: budgetBytes,
+		locks:       make(map[string]*sync.Mutex),
+		inUse:       make(map[string]int),
+	}
+}
+
+func normalizeRepoURL(repoURL string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(strings.ToLower(strings.TrimSpace(repoURL)), "/"), ".git")
+}
+
+func repoCacheKey(repoURL string) string {
+	sum := sha256.Sum256([]byte(normalizeRepoURL(repoURL)))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func (rc *RepoCache) mirrorDir(key string) string {
+	return filepath.Join(rc.root, key+".git")
+}
+
+func (rc *RepoCache) metaPath(key string) string {
+	return filepath.Join(rc.root, key+".json")
+}
+
+func (rc *RepoCache) repoLock(key string) *sync.Mutex {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	lock, ok := rc.locks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		rc.locks[key] = lock
+	}
+	return lock
+}
+
+func (rc *RepoCache) Checkout(ctx context.Context, repoURL, commitHash, targetDir string, logs *validationLog) error {
+	key := repoCacheKey(repoURL)
+	mirror := rc.mirrorDir(key)
+
+	lock := rc.repoLock(key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := os.MkdirAll(rc.root, 0755); err != nil {
+		return fmt.Errorf("failed to create repository cache: %w", err)
+	}
+
+	meta, err := rc.readMeta(key)
+	if err != nil || normalizeRepoURL(meta.URL) != normalizeRepoURL(repoURL) {
+		rc.mu.Lock()
+		inUse := rc.inUse[key]
+		rc.mu.Unlock()
+		if inUse > 0 {
+			return ErrRepoCacheEntryInUse
+		}
+
+		os.RemoveAll(mirror)
+		logs.Add(fmt.Sprintf("  Creating cache mirror for %s...", repoURL))
+		if err := runGit(ctx, logs, "init", "--bare", mirror); err != nil {
+			return err
+		}
+		if err := runGit(ctx, logs, "-C", mirror, "remote", "add", "origin", repoURL); err != nil {
+			return err
+		}
+		meta = RepoCacheEntry{Key: key, URL: repoURL, CreatedAt: time.Now()}
+	}
+
+	if runGit(ctx, nil, "-C", mirror, "cat-file", "-e", commitHash+"^{commit}") == nil {
+		logs.Add(fmt.Sprintf("  Cache hit: commit %s already in mirror", commitHash))
+	} else {
+		logs.Add(fmt.Sprintf("  Fetching commit %s into cache mirror...", commitHash))
+		if err := runGit(ctx, logs, "-C", mirror, "fetch", "--depth", "1", "origin", commitHash); err != nil {
+			logs.Add("  Shallow fetch by commit failed, fetching all branches...")
+			if err := runGit(ctx, logs, "-C", mirror, "fetch", "--tags", "origin", "+refs/heads/*:refs/heads/*"); err != nil {
+				return fmt.Errorf("git fetch failed: %w", err)
+			}
+		}
+	}
+
+	runGit(ctx, nil, "-C", mirror, "worktree", "prune")
+	os.Remove(targetDir)
+	logs.Add(fmt.Sprintf("  Checking out commit %s into worktree...", commitHash))
+	if err := runGit(ctx, logs, "-C", mirror, "worktree", "add", "--detach", "--force", targetDir, commitHash); err != nil {
+		return fmt.Errorf("git worktree add failed: %w", err)
+	}
+
+	rc.mu.Lock()
+	rc.inUse[key]++
+	rc.mu.Unlock()
+
+	meta.LastUsedAt = time.Now()
+	rc.writeMeta(meta)
+
+	return nil
+}
+
+func (rc *RepoCache) Release(repoURL, targetDir string) {
+	key := repoCacheKey(repoURL)
+	mirror := rc.mirrorDir(key)
+
+	lock := rc.repoLock(key)
+	lock.Lock()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	runGit(ctx, nil, "-C", mirror, "worktree", "remove", "--force", targetDir)
+	runGit(ctx, nil, "-C", mirror, "worktree", "prune")
+	cancel()
+	lock.Unlock()
+
+	rc.mu.Lock()
+	if rc.inUse[key] > 0 {
+		rc.inUse[key]--
+	}
+	rc.mu.Unlock()
+
+	rc.Evict()
+}
+
+func (rc *RepoCache) Entries() ([]RepoCacheEntry, error) {
+	paths, err := filepath.Glob(filepath.Join(rc.root, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]RepoCacheEntry, 0, len(paths))
+	for _, path := range paths {
+		key := strings.TrimSuffix(filepath.Base(path), ".json")
+		meta, err := rc.readMeta(key)
+		if err != nil {
+			continue
+		}
+		meta.SizeBytes = dirSize(rc.mirrorDir(key))
+
+		rc.mu.Lock()
+		meta.InUse = rc.inUse[key]
+		rc.mu.Unlock()
+
+		entries = append(entries, meta)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastUsedAt.After(entries[j].LastUsedAt)
+	})
+	return entries, nil
+}
+
+func (rc *RepoCache) BudgetBytes() int64 {
+	return rc.budgetBytes
+}
+
+func (rc *RepoCache) Evict() []string {
+	rc.sizeMu.Lock()
+	defer rc.sizeMu.Unlock()
+
+	entries, err := rc.Entries()
+	if err != nil {
+		return nil
+	}
+
+	var total int64
+	for _, entry := range entries {
+		total += entry.SizeBytes
+	}
+
+	var evicted []string
+	for i := len(entries) - 1; i >= 0 && total > rc.budgetBytes; i-- {
+		entry := entries[i]
+		if err := rc.Purge(entry.Key); err != nil {
+			continue
+		}
+		total -= entry.SizeBytes
+		evicted = append(evicted, entry.Key)
+	}
+	return evicted
+}
+
+func (rc *RepoCache) Purge(key string) error {
+	if _, err := hex.DecodeString(key); err != nil || len(key) != 16 {
+		return ErrRepoCacheEntryNotFound
+	}
+	if _, err := rc.readMeta(key); err != nil {
+		return ErrRepoCacheEntryNotFound
+	}
+
+	lock := rc.repoLock(key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	rc.mu.Lock()
+	inUse := rc.inUse[key]
+	rc.mu.Unlock()
+	if inUse > 0 {
+		return ErrRepoCacheEntryInUse
+	}
+
+	os.Remove(rc.metaPath(key))
+	return os.RemoveAll(rc.mirrorDir(key))
+}
+
+func (rc *RepoCache) PurgeAll() (purged int, skipped int) {
+	entries, _ := rc.Entries()
+	for _, entry := range entries {
+		if err := rc.Purge(entry.Key); err != nil {
+			skipped++
+			continue
+		}
+		purged++
+	}
+	return purged, skipped
+}
+
+func (rc *RepoCache) readMeta(key string) (RepoCacheEntry, error) {
+	var meta RepoCacheEntry
+	data, err := os.ReadFile(rc.metaPath(key))
+	if err != nil {
+		return meta, err
+	}
+	err = json.Unmarshal(data, &meta)
+	return meta, err
+}
+
+func (rc *RepoCache) writeMeta(meta RepoCacheEntry) error {
+	meta.SizeBytes = 0
+	meta.InUse = 0
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(rc.metaPath(meta.Key), data, 0644)
+}
+
+func runGit(ctx context.Context, logs *validationLog, args ...string) error {
+	output, err := exec.CommandContext(ctx, "git", args...).CombinedOutput()
+	if err != nil && logs != nil {
+		logs.Add(fmt.Sprintf("  git %s failed: %s", strings.Join(args, " "), strings.TrimSpace(string(output))))
+	}
+	return err
+}
+
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/diff"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/adzzatxperts/backend/internal/storage"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrRevisionNotFound   = errors.New("revision not found")
+	ErrUnknownArtifact    = errors.New("unknown artifact")
+	ErrRevisionTooLarge   = errors.New("revision is too large to diff")
+	maxRevisionDiffBytes  = 5 * 1024 * 1024
+	artifactDownloadNames = map[string]string{
+		models.ArtifactTestPatch:     "test.patch",
+		models.ArtifactDockerfile:    "Dockerfile",
+		models.ArtifactSolutionPatch: "solution.patch",
+	}
+)
+
+type RevisionDiff struct {
+	Artifact    string `json:"artifact"`
+	FromVersion int    `json:"fromVersion"`
+	ToVersion   int    `json:"toVersion"`
+	Identical   bool   `json:"identical"`
+	Diff        string `json:"diff"`
+}
+
+func IsArtifact(name string) bool {
+	_, ok := artifactDownloadNames[name]
+	return ok
+}
+
+func StageRevision(artifact, fileName string, data []byte, uploadedBy *uuid.UUID) (*models.SubmissionRevision, error) {
+	if !IsArtifact(artifact) {
+		return nil, ErrUnknownArtifact
+	}
+
+	key, err := storage.UploadFile(data, fileName, "text/plain")
+	if err != nil {
+		return nil, err
+	}
+	return newRevision(uuid.Nil, artifact, key, fileName, data, uploadedBy), nil
+}
+
+func DiscardStagedRevisions(revisions []*models.SubmissionRevision) {
+	for _, revision := range revisions {
+		if err := storage.DeleteFile(revision.StorageKey); err != nil {
+			log.Printf("Failed to delete staged %s upload %s: %v", revision.Artifact, revision.StorageKey, err)
+		}
+	}
+}
+
+func RecordRevision(submissionID uuid.UUID, artifact, storageKey, fileName string, data []byte, uploadedBy *uuid.UUID) (*models.SubmissionRevision, error) {
+	if !IsArtifact(artifact) {
+		return nil, ErrUnknownArtifact
+	}
+
+	revision := newRevision(submissionID, artifact, storageKey, fileName, data, uploadedBy)
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var submission models.ProjectVSubmission
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&submission, submissionID).Error
+		if err != nil {
+			return err
+		}
+
+		var latest int
+		err = tx.Model(&models.SubmissionRevision{}).
+			Where("submission_id = ? AND artifact = ?", submissionID, artifact).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error
+		if err != nil {
+			return err
+		}
+		revision.Version = latest + 1
+		return tx.Create(revision).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+func newRevision(submissionID uuid.UUID, artifact, storageKey, fileName string, data []byte, uploadedBy *uuid.UUID) *models.SubmissionRevision {
+	sum := sha256.Sum256(data)
+	return &models.SubmissionRevision{
+		SubmissionID: submissionID,
+		Artifact:     artifact,
+		StorageKey:   storageKey,
+		FileName:     fileName,
+		ContentType:  "text/plain",
+		SizeBytes:    int64(len(data)),
+		SHA256:       hex.EncodeToString(sum[:]),
+		UploadedByID: uploadedBy,
+	}
+}
+
+func GetRevisions(submissionID uuid.UUID) ([]models.SubmissionRevision, error) {
+	var revisions []models.SubmissionRevision
+	err := database.DB.
+		Preload("UploadedBy").
+		Where("submission_id = ?", submissionID).
+		Order("artifact ASC, version DESC").
+		Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range revisions {
+		name := fmt.Sprintf("v%d-%s", revisions[i].Version, artifactDownloadNames[revisions[i].Artifact])
+		if url, err := storage.GetSignedDownloadURL(revisions[i].StorageKey, name, 3600); err == nil {
+			revisions[i].DownloadURL = url
+		}
+	}
+	return revisions, nil
+}
+
+func GetRevision(submissionID uuid.UUID, artifact string, version int) (*models.SubmissionRevision, error) {
+	var revision models.SubmissionRevision
+	err := database.DB.
+		Where("submission_id = ? AND artifact = ? AND version = ?", submissionID, artifact, version).
+		First(&revision).Error
+	if err != nil {
+		return nil, ErrRevisionNotFound
+	}
+	return &revision, nil
+}
+
+func DiffRevisions(submissionID uuid.UUID, artifact string, fromVersion, toVersion int) (*RevisionDiff, error) {
+	if !IsArtifact(artifact) {
+		return nil, ErrUnknownArtifact
+	}
+
+	from, err := GetRevision(submissionID, artifact, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := GetRevision(submissionID, artifact, toVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RevisionDiff{Artifact: artifact, FromVersion: fromVersion, ToVersion: toVersion}
+	if from.SHA256 != "" && from.SHA256 == to.SHA256 {
+		result.Identical = true
+		return result, nil
+	}
+	if from.SizeBytes > int64(maxRevisionDiffBytes) || to.SizeBytes > int64(maxRevisionDiffBytes) {
+		return nil, ErrRevisionTooLarge
+	}
+
+	fromData, err := storage.DownloadFile(from.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	toData, err := storage.DownloadFile(to.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(fromData) > maxRevisionDiffBytes || len(toData) > maxRevisionDiffBytes {
+		return nil, ErrRevisionTooLarge
+	}
+
+	name := artifactDownloadNames[artifact]
+	result.Diff = diff.Unified(
+		fmt.Sprintf("v%d/%s", fromVersion, name),
+		fmt.Sprintf("v%d/%s", toVersion, name),
+		string(fromData), string(toData), diff.DefaultContext,
+	)
+	result.Identical = result.Diff == ""
+	return result, nil
+}
+
+func DeleteSubmissionArtifacts(submission *models.ProjectVSubmission) int {
+	keys := map[string]bool{
+		submission.TestPatchURL:     true,
+		submission.DockerfileURL:    true,
+		submission.SolutionPatchURL: true,
+	}
+
+	var revisions []models.SubmissionRevision
+	database.DB.Select("storage_key").Where("submission_id = ?", submission.ID).Find(&revisions)
+	for _, revision := range revisions {
+		keys[revision.StorageKey] = true
+	}
+
+	deleted := 0
+	for key := range keys {
+		if key == "" {
+			continue
+		}
+		if err := storage.DeleteFile(key); err != nil {
+			log.Printf("Failed to delete artifact %s for submission %s: %v", key, submission.ID, err)
+			continue
+		}
+		deleted++
+	}
+	return deleted
+}
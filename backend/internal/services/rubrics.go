@@ -0,0 +1,358 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRubricNotFound      = errors.New("rubric not found")
+	ErrRubricNotConfigured = errors.New("no active rubric is configured for this task")
+	ErrRubricRequired      = errors.New("a completed review rubric is required")
+	ErrRubricMismatch      = errors.New("rubric does not apply to this task")
+	ErrRubricIncomplete    = errors.New("every rubric criterion must be scored")
+	ErrRubricInvalidScore  = errors.New("rubric score is out of range")
+	ErrRubricInvalid       = errors.New("invalid rubric")
+)
+
+type RubricCriterionInput struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Weight      float64 `json:"weight"`
+	MinScore    int     `json:"minScore"`
+	MaxScore    int     `json:"maxScore"`
+}
+
+type RubricInput struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Category    string                 `json:"category"`
+	Difficulty  string                 `json:"difficulty"`
+	Active      *bool                  `json:"active"`
+	Criteria    []RubricCriterionInput `json:"criteria"`
+}
+
+type RubricScoreInput struct {
+	CriterionID uuid.UUID `json:"criterionId"`
+	Score       int       `json:"score"`
+	Note        string    `json:"note"`
+}
+
+type RubricSubmission struct {
+	RubricID uuid.UUID          `json:"rubricId"`
+	Scores   []RubricScoreInput `json:"scores"`
+	Comment  string             `json:"comment"`
+}
+
+type RubricQualityStat struct {
+	UserID       uuid.UUID `json:"userId"`
+	UserName     string    `json:"userName"`
+	Evaluations  int64     `json:"evaluations"`
+	AverageScore float64   `json:"averageScore"`
+	MinScore     float64   `json:"minScore"`
+	MaxScore     float64   `json:"maxScore"`
+}
+
+type RubricCriterionStat struct {
+	CriterionName string  `json:"criterionName"`
+	Evaluations   int64   `json:"evaluations"`
+	AverageScore  float64 `json:"averageScore"`
+}
+
+type RubricQualityMetrics struct {
+	TotalEvaluations int64                 `json:"totalEvaluations"`
+	AverageScore     float64               `json:"averageScore"`
+	Contributors     []RubricQualityStat   `json:"contributors"`
+	Reviewers        []RubricQualityStat   `json:"reviewers"`
+	Criteria         []RubricCriterionStat `json:"criteria"`
+}
+
+func ListRubrics(includeInactive bool) ([]models.Rubric, error) {
+	query := database.DB.Preload("Criteria", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") })
+	if !includeInactive {
+		query = query.Where("active = ?", true)
+	}
+
+	var rubrics []models.Rubric
+	err := query.Order("category ASC, difficulty ASC, name ASC").Find(&rubrics).Error
+	return rubrics, err
+}
+
+func GetRubric(id uuid.UUID) (*models.Rubric, error) {
+	var rubric models.Rubric
+	err := database.DB.
+		Preload("Criteria", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		First(&rubric, id).Error
+	if err != nil {
+		return nil, ErrRubricNotFound
+	}
+	return &rubric, nil
+}
+
+func CreateRubric(input RubricInput, createdBy *uuid.UUID) (*models.Rubric, error) {
+	criteria, err := buildRubricCriteria(input)
+	if err != nil {
+		return nil, err
+	}
+
+	rubric := models.Rubric{
+		Name:        strings.TrimSpace(input.Name),
+		Description: strings.TrimSpace(input.Description),
+		Category:    strings.TrimSpace(input.Category),
+		Difficulty:  strings.TrimSpace(input.Difficulty),
+		Active:      input.Active == nil || *input.Active,
+		CreatedByID: createdBy,
+		Criteria:    criteria,
+	}
+	if err := database.DB.Create(&rubric).Error; err != nil {
+		return nil, err
+	}
+	return &rubric, nil
+}
+
+func UpdateRubric(id uuid.UUID, input RubricInput) (*models.Rubric, error) {
+	criteria, err := buildRubricCriteria(input)
+	if err != nil {
+		return nil, err
+	}
+
+	rubric, err := GetRubric(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{
+			"name":        strings.TrimSpace(input.Name),
+			"description": strings.TrimSpace(input.Description),
+			"category":    strings.TrimSpace(input.Category),
+			"difficulty":  strings.TrimSpace(input.Difficulty),
+		}
+		if input.Active != nil {
+			updates["active"] = *input.Active
+		}
+		if err := tx.Model(rubric).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("rubric_id = ?", rubric.ID).Delete(&models.RubricCriterion{}).Error; err != nil {
+			return err
+		}
+		for i := range criteria {
+			criteria[i].RubricID = rubric.ID
+		}
+		return tx.Create(&criteria).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return GetRubric(id)
+}
+
+func DeleteRubric(id uuid.UUID) error {
+	result := database.DB.Delete(&models.Rubric{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRubricNotFound
+	}
+	return nil
+}
+
+func buildRubricCriteria(input RubricInput) ([]models.RubricCriterion, error) {
+	if strings.TrimSpace(input.Name) == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrRubricInvalid)
+	}
+	if len(input.Criteria) == 0 {
+		return nil, fmt.Errorf("%w: at least one criterion is required", ErrRubricInvalid)
+	}
+
+	criteria := make([]models.RubricCriterion, 0, len(input.Criteria))
+	for i, criterion := range input.Criteria {
+		name := strings.TrimSpace(criterion.Name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: criterion %d needs a name", ErrRubricInvalid, i+1)
+		}
+		if criterion.Weight <= 0 {
+			return nil, fmt.Errorf("%w: criterion %q needs a positive weight", ErrRubricInvalid, name)
+		}
+		if criterion.MaxScore <= criterion.MinScore {
+			return nil, fmt.Errorf("%w: criterion %q needs maxScore greater than minScore", ErrRubricInvalid, name)
+		}
+		criteria = append(criteria, models.RubricCriterion{
+			Position:    i + 1,
+			Name:        name,
+			Description: strings.TrimSpace(criterion.Description),
+			Weight:      criterion.Weight,
+			MinScore:    criterion.MinScore,
+			MaxScore:    criterion.MaxScore,
+		})
+	}
+	return criteria, nil
+}
+
+func ResolveRubric(category, difficulty string) (*models.Rubric, error) {
+	var rubric models.Rubric
+	err := database.DB.
+		Preload("Criteria", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Where("active = ?", true).
+		Where("category = '' OR LOWER(category) = LOWER(?)", category).
+		Where("difficulty = '' OR LOWER(difficulty) = LOWER(?)", difficulty).
+		Order("(category <> '') DESC, (difficulty <> '') DESC, updated_at DESC").
+		First(&rubric).Error
+	if err != nil {
+		return nil, ErrRubricNotConfigured
+	}
+	return &rubric, nil
+}
+
+func BuildRubricEvaluation(submission *models.ProjectVSubmission, reviewerID *uuid.UUID, input *RubricSubmission) (*models.RubricEvaluation, error) {
+	if input == nil || len(input.Scores) == 0 {
+		return nil, ErrRubricRequired
+	}
+
+	rubric, err := ResolveRubric(submission.Category, submission.Difficulty)
+	if err != nil {
+		return nil, err
+	}
+	if input.RubricID != uuid.Nil && input.RubricID != rubric.ID {
+		return nil, ErrRubricMismatch
+	}
+
+	scores := make(map[uuid.UUID]RubricScoreInput, len(input.Scores))
+	for _, score := range input.Scores {
+		scores[score.CriterionID] = score
+	}
+
+	evaluation := &models.RubricEvaluation{
+		SubmissionID: submission.ID,
+		RubricID:     &rubric.ID,
+		RubricName:   rubric.Name,
+		ReviewerID:   reviewerID,
+		Comment:      strings.TrimSpace(input.Comment),
+		Scores:       make([]models.RubricScore, 0, len(rubric.Criteria)),
+	}
+
+	var weighted, totalWeight float64
+	for _, criterion := range rubric.Criteria {
+		score, ok := scores[criterion.ID]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing %q", ErrRubricIncomplete, criterion.Name)
+		}
+		if score.Score < criterion.MinScore || score.Score > criterion.MaxScore {
+			return nil, fmt.Errorf("%w: %q must be between %d and %d", ErrRubricInvalidScore, criterion.Name, criterion.MinScore, criterion.MaxScore)
+		}
+		delete(scores, criterion.ID)
+
+		criterionID := criterion.ID
+		evaluation.Scores = append(evaluation.Scores, models.RubricScore{
+			CriterionID:   &criterionID,
+			CriterionName: criterion.Name,
+			Weight:        criterion.Weight,
+			MinScore:      criterion.MinScore,
+			MaxScore:      criterion.MaxScore,
+			Score:         score.Score,
+			Note:          strings.TrimSpace(score.Note),
+		})
+		weighted += criterion.Weight * float64(score.Score-criterion.MinScore) / float64(criterion.MaxScore-criterion.MinScore)
+		totalWeight += criterion.Weight
+	}
+	if len(scores) > 0 {
+		return nil, ErrRubricMismatch
+	}
+	if totalWeight > 0 {
+		evaluation.WeightedScore = math.Round(weighted/totalWeight*10000) / 100
+	}
+
+	return evaluation, nil
+}
+
+func GetRubricEvaluations(submissionID uuid.UUID) ([]models.RubricEvaluation, error) {
+	var evaluations []models.RubricEvaluation
+	err := database.DB.
+		Preload("Scores").
+		Preload("Reviewer").
+		Where("submission_id = ?", submissionID).
+		Order("created_at DESC").
+		Find(&evaluations).Error
+	return evaluations, err
+}
+
+func GetRubricQualityMetrics(limit int) (*RubricQualityMetrics, error) {
+	metrics := &RubricQualityMetrics{
+		Contributors: make([]RubricQualityStat, 0),
+		Reviewers:    make([]RubricQualityStat, 0),
+		Criteria:     make([]RubricCriterionStat, 0),
+	}
+
+	var overall struct {
+		Total   int64
+		Average float64
+	}
+	err := database.DB.Model(&models.RubricEvaluation{}).
+		Select("COUNT(*) AS total, COALESCE(AVG(weighted_score), 0) AS average").
+		Scan(&overall).Error
+	if err != nil {
+		return nil, err
+	}
+	metrics.TotalEvaluations = overall.Total
+	metrics.AverageScore = math.Round(overall.Average*100) / 100
+
+	err = database.DB.Raw(`
+		SELECT u.id AS user_id, u.name AS user_name, COUNT(e.id) AS evaluations,
+			AVG(e.weighted_score) AS average_score, MIN(e.weighted_score) AS min_score, MAX(e.weighted_score) AS max_score
+		FROM rubric_evaluations e
+		JOIN project_v_submissions s ON s.id = e.submission_id
+		JOIN users u ON u.id = s.contributor_id
+		GROUP BY u.id, u.name
+		ORDER BY average_score DESC, evaluations DESC
+		LIMIT ?
+	`, limit).Scan(&metrics.Contributors).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.DB.Raw(`
+		SELECT u.id AS user_id, u.name AS user_name, COUNT(e.id) AS evaluations,
+			AVG(e.weighted_score) AS average_score, MIN(e.weighted_score) AS min_score, MAX(e.weighted_score) AS max_score
+		FROM rubric_evaluations e
+		JOIN users u ON u.id = e.reviewer_id
+		GROUP BY u.id, u.name
+		ORDER BY evaluations DESC
+		LIMIT ?
+	`, limit).Scan(&metrics.Reviewers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.DB.Raw(`
+		SELECT criterion_name, COUNT(*) AS evaluations,
+			AVG(100.0 * (score - min_score) / NULLIF(max_score - min_score, 0)) AS average_score
+		FROM rubric_scores
+		GROUP BY criterion_name
+		ORDER BY criterion_name ASC
+	`).Scan(&metrics.Criteria).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range metrics.Contributors {
+		metrics.Contributors[i].AverageScore = math.Round(metrics.Contributors[i].AverageScore*100) / 100
+	}
+	for i := range metrics.Reviewers {
+		metrics.Reviewers[i].AverageScore = math.Round(metrics.Reviewers[i].AverageScore*100) / 100
+	}
+	for i := range metrics.Criteria {
+		metrics.Criteria[i].AverageScore = math.Round(metrics.Criteria[i].AverageScore*100) / 100
+	}
+
+	return metrics, nil
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestDefaultRubricAppliesToUnconfiguredTasks(t *testing.T) {
+	setupTestDB(t)
+
+	submission := &models.ProjectVSubmission{
+		ID:         uuid.New(),
+		Category:   "category-" + uuid.NewString()[:8],
+		Difficulty: "difficulty-" + uuid.NewString()[:8],
+	}
+	rubric, err := ResolveRubric(submission.Category, submission.Difficulty)
+	if err != nil {
+		t.Fatalf("ResolveRubric: %v", err)
+	}
+	if rubric.Category != "" || rubric.Difficulty != "" {
+		t.Fatalf("resolved rubric %q is scoped to %q/%q, expected the general rubric", rubric.Name, rubric.Category, rubric.Difficulty)
+	}
+	if len(rubric.Criteria) == 0 {
+		t.Fatalf("general rubric %q has no criteria", rubric.Name)
+	}
+
+	input := &RubricSubmission{RubricID: rubric.ID}
+	for _, criterion := range rubric.Criteria {
+		input.Scores = append(input.Scores, RubricScoreInput{CriterionID: criterion.ID, Score: criterion.MaxScore})
+	}
+	evaluation, err := BuildRubricEvaluation(submission, nil, input)
+	if err != nil {
+		t.Fatalf("BuildRubricEvaluation: %v", err)
+	}
+	if evaluation.WeightedScore != 100 {
+		t.Fatalf("weighted score = %v, want 100", evaluation.WeightedScore)
+	}
+
+	input.Scores = input.Scores[1:]
+	if _, err := BuildRubricEvaluation(submission, nil, input); !errors.Is(err, ErrRubricIncomplete) {
+		t.Fatalf("missing score error = %v, want ErrRubricIncomplete", err)
+	}
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type SandboxConfig struct {
+	Builder      string
+	Build        ContainerLimits
+	Test         ContainerLimits
+	BuildTimeout time.Duration
+	TestTimeout  time.Duration
+}
+
+func LoadSandboxConfig() SandboxConfig {
+	config := SandboxConfig{
+		Builder:      strings.ToLower(os.Getenv("SANDBOX_BUILDER")),
+		BuildTimeout: envDuration("SANDBOX_BUILD_TIMEOUT", 20*time.Minute),
+		TestTimeout:  envDuration("SANDBOX_TEST_TIMEOUT", 5*time.Minute),
+	}
+
+	config.Build = ContainerLimits{
+		CPUs:           envFloat("SANDBOX_BUILD_CPUS", envFloat("SANDBOX_CPUS", 2)),
+		MemoryMB:       envInt("SANDBOX_BUILD_MEMORY_MB", envInt("SANDBOX_MEMORY_MB", 4096)),
+		PIDs:           envInt("SANDBOX_BUILD_PIDS_LIMIT", envInt("SANDBOX_PIDS_LIMIT", 1024)),
+		Network:        os.Getenv("SANDBOX_BUILD_NETWORK"),
+		TimeoutSeconds: int(config.BuildTimeout.Seconds()),
+	}
+
+	switch config.Builder {
+	case SandboxBuilderIsolated, SandboxBuilderDefault:
+	case "":
+		config.Builder = SandboxBuilderIsolated
+	default:
+		log.Printf("⚠️  Invalid value for SANDBOX_BUILDER: %q, using %s", config.Builder, SandboxBuilderIsolated)
+		config.Builder = SandboxBuilderIsolated
+	}
+
+	testNetwork := os.Getenv("SANDBOX_TEST_NETWORK")
+	if testNetwork == "" {
+		testNetwork = "none"
+	}
+	config.Test = ContainerLimits{
+		CPUs:           envFloat("SANDBOX_CPUS", 2),
+		MemoryMB:       envInt("SANDBOX_MEMORY_MB", 2048),
+		PIDs:           envInt("SANDBOX_PIDS_LIMIT", 512),
+		DiskMB:         envInt("SANDBOX_DISK_MB", 0),
+		Network:        testNetwork,
+		TimeoutSeconds: int(config.TestTimeout.Seconds()),
+	}
+
+	return config
+}
+
+func (l ContainerLimits) JSON() *string {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return nil
+	}
+	limits := string(data)
+	return &limits
+}
+
+func envFloat(key string, fallback float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil && parsed >= 0 {
+			return parsed
+		}
+		log.Printf("⚠️  Invalid value for %s: %q, using %g", key, value, fallback)
+	}
+	return fallback
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionRevoked  = errors.New("session has been terminated")
+)
+
+type Session struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"userId"`
+	UserAgent  string     `json:"userAgent"`
+	IPAddress  string     `json:"ipAddress"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	Current    bool       `json:"current"`
+}
+
+type sessionEntry struct {
+	active   bool
+	loadedAt time.Time
+}
+
+var (
+	sessionsMu sync.RWMutex
+	sessions   = make(map[uuid.UUID]sessionEntry)
+)
+
+func ListUserSessions(userID uuid.UUID, currentSessionID string) ([]Session, error) {
+	var tokens []models.RefreshToken
+	err := database.DB.
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("COALESCE(last_used_at, created_at) DESC").
+		Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]Session, 0, len(tokens))
+	for _, token := range tokens {
+		session := Session{
+			ID:         sessionID(&token),
+			UserID:     token.UserID,
+			UserAgent:  token.UserAgent,
+			IPAddress:  token.IPAddress,
+			CreatedAt:  token.CreatedAt,
+			LastUsedAt: token.LastUsedAt,
+			ExpiresAt:  token.ExpiresAt,
+		}
+		if token.StartedAt != nil {
+			session.CreatedAt = *token.StartedAt
+		}
+		session.Current = session.ID.String() == currentSessionID
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+func RevokeSession(userID, id uuid.UUID) error {
+	result := database.DB.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL AND (family_id = ? OR (family_id IS NULL AND id = ?))", userID, id, id).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSessionNotFound
+	}
+	markSessionRevoked(id)
+	return nil
+}
+
+func CheckSession(id string) error {
+	sessionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil
+	}
+
+	active, err := sessionActive(sessionID)
+	if err != nil {
+		return err
+	}
+	if !active {
+		return ErrSessionRevoked
+	}
+	return nil
+}
+
+func sessionActive(id uuid.UUID) (bool, error) {
+	tokenVersionOnce.Do(loadTokenVersionConfig)
+
+	sessionsMu.RLock()
+	entry, ok := sessions[id]
+	sessionsMu.RUnlock()
+	if ok && time.Since(entry.loadedAt) < tokenVersionTTL {
+		return entry.active, nil
+	}
+
+	var active bool
+	err := database.DB.Raw(`
+		SELECT EXISTS (
+			SELECT 1 FROM refresh_tokens
+			WHERE (family_id = ? OR (family_id IS NULL AND id = ?)) AND revoked_at IS NULL AND expires_at > ?
+		)
+	`, id, id, time.Now()).Scan(&active).Error
+	if err != nil {
+		return false, err
+	}
+
+	cacheSession(id, sessionEntry{active: active, loadedAt: time.Now()})
+	return active, nil
+}
+
+func cacheSession(id uuid.UUID, entry sessionEntry) {
+	tokenVersionOnce.Do(loadTokenVersionConfig)
+
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+
+	if cached, ok := sessions[id]; ok && !cached.active {
+		entry.active = false
+	}
+	if len(sessions) >= tokenVersionLimit {
+		for cachedID, cached := range sessions {
+			if time.Since(cached.loadedAt) >= tokenVersionTTL {
+				delete(sessions, cachedID)
+			}
+		}
+		if len(sessions) >= tokenVersionLimit {
+			sessions = make(map[uuid.UUID]sessionEntry)
+		}
+	}
+	sessions[id] = entry
+}
+
+func markSessionRevoked(id uuid.UUID) {
+	cacheSession(id, sessionEntry{active: false, loadedAt: time.Now()})
+}
+
+func sessionID(token *models.RefreshToken) uuid.UUID {
+	if token.FamilyID != nil {
+		return *token.FamilyID
+	}
+	return token.ID
+}
+
+func truncateString(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+	return value[:limit]
+}
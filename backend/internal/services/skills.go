@@ -0,0 +1,117 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/adzzatxperts/backend/internal/assignment"
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInvalidSkills = errors.New("invalid skill profile")
+
+type Skills struct {
+	Languages     []string `json:"languages"`
+	Categories    []string `json:"categories"`
+	MaxDifficulty string   `json:"maxDifficulty"`
+}
+
+func GetSkills(userID uuid.UUID) (*Skills, error) {
+	var profile models.SkillProfile
+	err := database.DB.Where("user_id = ?", userID).First(&profile).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &Skills{Languages: []string{}, Categories: []string{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return skillsFromProfile(&profile), nil
+}
+
+func SaveSkills(userID uuid.UUID, skills Skills) (*Skills, error) {
+	normalized, err := normalizeSkills(skills)
+	if err != nil {
+		return nil, err
+	}
+
+	languages, _ := json.Marshal(normalized.Languages)
+	categories, _ := json.Marshal(normalized.Categories)
+	languagesJSON := string(languages)
+	categoriesJSON := string(categories)
+
+	profile := models.SkillProfile{
+		UserID:        userID,
+		Languages:     &languagesJSON,
+		Categories:    &categoriesJSON,
+		MaxDifficulty: normalized.MaxDifficulty,
+	}
+	err = database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"languages", "categories", "max_difficulty", "updated_at"}),
+	}).Create(&profile).Error
+	if err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+func normalizeSkills(skills Skills) (*Skills, error) {
+	normalized := &Skills{
+		Languages:  dedupeSkills(skills.Languages),
+		Categories: dedupeSkills(skills.Categories),
+	}
+	if skills.MaxDifficulty != "" {
+		rank := assignment.DifficultyRank(skills.MaxDifficulty)
+		if rank == 0 {
+			return nil, fmt.Errorf("%w: maxDifficulty must be one of %s", ErrInvalidSkills, strings.Join(models.Difficulties, ", "))
+		}
+		normalized.MaxDifficulty = models.Difficulties[rank-1]
+	}
+	return normalized, nil
+}
+
+func dedupeSkills(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		key := strings.ToLower(value)
+		if value == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, value)
+	}
+	return result
+}
+
+func skillsFromProfile(profile *models.SkillProfile) *Skills {
+	skills := &Skills{Languages: []string{}, Categories: []string{}, MaxDifficulty: profile.MaxDifficulty}
+	if profile.Languages != nil {
+		json.Unmarshal([]byte(*profile.Languages), &skills.Languages)
+	}
+	if profile.Categories != nil {
+		json.Unmarshal([]byte(*profile.Categories), &skills.Categories)
+	}
+	return skills
+}
+
+func loadSkillProfiles(tx *gorm.DB, userIDs []uuid.UUID) map[uuid.UUID]*Skills {
+	profiles := make(map[uuid.UUID]*Skills, len(userIDs))
+	if len(userIDs) == 0 {
+		return profiles
+	}
+
+	var rows []models.SkillProfile
+	tx.Where("user_id IN ?", userIDs).Find(&rows)
+	for i := range rows {
+		profiles[rows[i].UserID] = skillsFromProfile(&rows[i])
+	}
+	return profiles
+}
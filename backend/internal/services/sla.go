@@ -0,0 +1,493 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/adzzatxperts/backend/internal/assignment"
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrSLAPolicyNotFound = errors.New("SLA policy not found")
+	ErrSLAPolicyExists   = errors.New("an SLA policy already exists for this pipeline and status")
+	ErrInvalidSLAPolicy  = errors.New("invalid SLA policy")
+)
+
+type SLAPolicyInput struct {
+	Name                 string `json:"name"`
+	Pipeline             string `json:"pipeline"`
+	Status               string `json:"status"`
+	ThresholdMinutes     int    `json:"thresholdMinutes"`
+	EscalateAfterMinutes int    `json:"escalateAfterMinutes"`
+	Reassign             bool   `json:"reassign"`
+	Active               *bool  `json:"active"`
+}
+
+type SLAStats struct {
+	OpenBreaches  int64            `json:"openBreaches"`
+	Escalated     int64            `json:"escalated"`
+	Reassigned    int64            `json:"reassigned"`
+	TotalBreaches int64            `json:"totalBreaches"`
+	ByPipeline    map[string]int64 `json:"byPipeline"`
+}
+
+type SLASchedulerConfig struct {
+	Interval time.Duration
+}
+
+type slaCandidate struct {
+	ID         uuid.UUID
+	Title      string
+	AssigneeID *uuid.UUID
+	StartedAt  time.Time
+}
+
+func LoadSLASchedulerConfig() SLASchedulerConfig {
+	return SLASchedulerConfig{
+		Interval: envDuration("SLA_SCHEDULER_INTERVAL", 5*time.Minute),
+	}
+}
+
+func StartSLAScheduler(config SLASchedulerConfig) {
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(config.Interval)
+		defer ticker.Stop()
+
+		for {
+			RunSLACycle()
+			<-ticker.C
+		}
+	}()
+}
+
+func ListSLAPolicies() ([]models.SLAPolicy, error) {
+	var policies []models.SLAPolicy
+	err := database.DB.Order("pipeline ASC, status ASC").Find(&policies).Error
+	return policies, err
+}
+
+func CreateSLAPolicy(input SLAPolicyInput, createdBy *uuid.UUID) (*models.SLAPolicy, error) {
+	if err := validateSLAPolicy(&input); err != nil {
+		return nil, err
+	}
+
+	var existing int64
+	database.DB.Model(&models.SLAPolicy{}).
+		Where("pipeline = ? AND status = ?", input.Pipeline, input.Status).
+		Count(&existing)
+	if existing > 0 {
+		return nil, ErrSLAPolicyExists
+	}
+
+	policy := models.SLAPolicy{
+		Name:                 input.Name,
+		Pipeline:             input.Pipeline,
+		Status:               input.Status,
+		ThresholdMinutes:     input.ThresholdMinutes,
+		EscalateAfterMinutes: input.EscalateAfterMinutes,
+		Reassign:             input.Reassign,
+		Active:               input.Active == nil || *input.Active,
+		CreatedByID:          createdBy,
+	}
+	if err := database.DB.Create(&policy).Error; err != nil {
+		return nil, err
+	}
+	if !policy.Active {
+		database.DB.Model(&policy).Update("active", false)
+	}
+	return &policy, nil
+}
+
+func UpdateSLAPolicy(id uuid.UUID, input SLAPolicyInput) (*models.SLAPolicy, error) {
+	var policy models.SLAPolicy
+	if err := database.DB.First(&policy, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSLAPolicyNotFound
+		}
+		return nil, err
+	}
+	if err := validateSLAPolicy(&input); err != nil {
+		return nil, err
+	}
+
+	var existing int64
+	database.DB.Model(&models.SLAPolicy{}).
+		Where("pipeline = ? AND status = ? AND id <> ?", input.Pipeline, input.Status, id).
+		Count(&existing)
+	if existing > 0 {
+		return nil, ErrSLAPolicyExists
+	}
+
+	policy.Name = input.Name
+	policy.Pipeline = input.Pipeline
+	policy.Status = input.Status
+	policy.ThresholdMinutes = input.ThresholdMinutes
+	policy.EscalateAfterMinutes = input.EscalateAfterMinutes
+	policy.Reassign = input.Reassign
+	if input.Active != nil {
+		policy.Active = *input.Active
+	}
+	if err := database.DB.Save(&policy).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func DeleteSLAPolicy(id uuid.UUID) (*models.SLAPolicy, error) {
+	var policy models.SLAPolicy
+	if err := database.DB.First(&policy, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSLAPolicyNotFound
+		}
+		return nil, err
+	}
+	if err := database.DB.Delete(&policy).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func ListSLABreaches(openOnly bool, limit int) ([]models.SLABreach, error) {
+	var breaches []models.SLABreach
+	query := database.DB.Preload("Policy").Preload("Assignee").Order("breached_at DESC").Limit(limit)
+	if openOnly {
+		query = query.Where("resolved_at IS NULL")
+	}
+	err := query.Find(&breaches).Error
+	return breaches, err
+}
+
+func GetSLAStats() (*SLAStats, error) {
+	stats := &SLAStats{ByPipeline: make(map[string]int64)}
+
+	if err := database.DB.Model(&models.SLABreach{}).Count(&stats.TotalBreaches).Error; err != nil {
+		return nil, err
+	}
+	database.DB.Model(&models.SLABreach{}).Where("resolved_at IS NULL").Count(&stats.OpenBreaches)
+	database.DB.Model(&models.SLABreach{}).Where("resolved_at IS NULL AND escalated_at IS NOT NULL").Count(&stats.Escalated)
+	database.DB.Model(&models.SLABreach{}).Where("reassigned_at IS NOT NULL").Count(&stats.Reassigned)
+
+	var rows []struct {
+		Pipeline string
+		Count    int64
+	}
+	database.DB.Model(&models.SLABreach{}).
+		Select("pipeline, COUNT(*) AS count").
+		Where("resolved_at IS NULL").
+		Group("pipeline").
+		Scan(&rows)
+	for _, row := range rows {
+		stats.ByPipeline[row.Pipeline] = row.Count
+	}
+	return stats, nil
+}
+
+func validateSLAPolicy(input *SLAPolicyInput) error {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Status = strings.ToUpper(strings.TrimSpace(input.Status))
+
+	config, ok := assignmentPipelines[input.Pipeline]
+	if !ok {
+		return fmt.Errorf("%w: pipeline must be one of %s", ErrInvalidSLAPolicy, strings.Join(models.Pipelines, ", "))
+	}
+	known := false
+	for _, status := range config.statuses {
+		if status == input.Status {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return fmt.Errorf("%w: status for %s must be one of %s", ErrInvalidSLAPolicy, input.Pipeline, strings.Join(config.statuses, ", "))
+	}
+	if input.ThresholdMinutes < 1 {
+		return fmt.Errorf("%w: thresholdMinutes must be at least 1", ErrInvalidSLAPolicy)
+	}
+	if input.EscalateAfterMinutes < 0 {
+		return fmt.Errorf("%w: escalateAfterMinutes cannot be negative", ErrInvalidSLAPolicy)
+	}
+	if input.Name == "" {
+		input.Name = fmt.Sprintf("%s %s within %s", input.Pipeline, input.Status, slaDuration(input.ThresholdMinutes))
+	}
+	return nil
+}
+
+func RunSLACycle() {
+	var policies []models.SLAPolicy
+	if err := database.DB.Where("active = ?", true).Find(&policies).Error; err != nil {
+		log.Printf("SLA scheduler failed to load policies: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for i := range policies {
+		if err := evaluateSLAPolicy(&policies[i], now); err != nil {
+			log.Printf("SLA scheduler failed to evaluate policy %q: %v", policies[i].Name, err)
+		}
+	}
+
+	database.DB.Model(&models.SLABreach{}).
+		Where("resolved_at IS NULL AND policy_id IN (?)",
+			database.DB.Model(&models.SLAPolicy{}).Select("id").Where("active = ?", false)).
+		Update("resolved_at", now)
+}
+
+func evaluateSLAPolicy(policy *models.SLAPolicy, now time.Time) error {
+	config, ok := assignmentPipelines[policy.Pipeline]
+	if !ok {
+		return ErrUnknownPipeline
+	}
+
+	entered := database.DB.Table("status_transitions").
+		Select("MAX(created_at)").
+		Where("subject_type = ? AND subject_id = "+config.table+".id AND to_status = ?", config.subject, policy.Status)
+	reassigned := database.DB.Table("sla_breaches").
+		Select("MAX(reassigned_at)").
+		Where("policy_id = ? AND subject_id = "+config.table+".id", policy.ID)
+
+	var candidates []slaCandidate
+	err := database.DB.Table(config.table).
+		Select("id, title, "+config.column+" AS assignee_id, COALESCE(GREATEST((?), (?)), updated_at) AS started_at", entered, reassigned).
+		Where("status = ?", policy.Status).
+		Scan(&candidates).Error
+	if err != nil {
+		return err
+	}
+
+	threshold := time.Duration(policy.ThresholdMinutes) * time.Minute
+	open := make([]uuid.UUID, 0)
+	for _, candidate := range candidates {
+		breachedAt := candidate.StartedAt.Add(threshold)
+		if now.Before(breachedAt) {
+			continue
+		}
+
+		breach, err := recordSLABreach(policy, config, candidate, breachedAt)
+		if err != nil {
+			return err
+		}
+		if processSLABreach(policy, breach, now) {
+			continue
+		}
+		open = append(open, breach.ID)
+	}
+
+	query := database.DB.Model(&models.SLABreach{}).Where("policy_id = ? AND resolved_at IS NULL", policy.ID)
+	if len(open) > 0 {
+		query = query.Where("id NOT IN ?", open)
+	}
+	return query.Update("resolved_at", now).Error
+}
+
+func recordSLABreach(policy *models.SLAPolicy, config assignmentPipeline, candidate slaCandidate, breachedAt time.Time) (*models.SLABreach, error) {
+	breach := models.SLABreach{
+		PolicyID:    policy.ID,
+		Pipeline:    policy.Pipeline,
+		SubjectType: config.subject,
+		SubjectID:   candidate.ID,
+		Title:       candidate.Title,
+		Status:      policy.Status,
+		AssigneeID:  candidate.AssigneeID,
+		StartedAt:   candidate.StartedAt,
+		BreachedAt:  breachedAt,
+	}
+	err := database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "policy_id"}, {Name: "subject_id"}, {Name: "started_at"}},
+		DoNothing: true,
+	}).Create(&breach).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.DB.
+		Where("policy_id = ? AND subject_id = ? AND started_at = ?", policy.ID, candidate.ID, candidate.StartedAt).
+		First(&breach).Error
+	if err != nil {
+		return nil, err
+	}
+	return &breach, nil
+}
+
+func processSLABreach(policy *models.SLAPolicy, breach *models.SLABreach, now time.Time) bool {
+	overdue := slaDuration(policy.ThresholdMinutes)
+
+	if breach.NotifiedAt == nil && markSLABreach(breach.ID, "notified_at", now) {
+		if breach.AssigneeID != nil {
+			notifyUser(*breach.AssigneeID, "SLA breached on "+breach.Title,
+				fmt.Sprintf("This task has been %s for more than %s, please take action", breach.Status, overdue), breach.SubjectID)
+		}
+		logSLAActivity("SLA_BREACH",
+			fmt.Sprintf("Task \"%s\" breached SLA \"%s\" (%s for more than %s)", breach.Title, policy.Name, breach.Status, overdue),
+			breach, nil)
+	}
+
+	escalateAt := breach.BreachedAt.Add(time.Duration(policy.EscalateAfterMinutes) * time.Minute)
+	if breach.EscalatedAt != nil || now.Before(escalateAt) || !markSLABreach(breach.ID, "escalated_at", now) {
+		return false
+	}
+
+	var admins []models.User
+	database.DB.Select("id").Where("role = ?", models.RoleAdmin).Find(&admins)
+	for _, admin := range admins {
+		notifyUser(admin.ID, "SLA escalation: "+breach.Title,
+			fmt.Sprintf("Task has been %s for more than %s under policy \"%s\"", breach.Status, overdue, policy.Name), breach.SubjectID)
+	}
+	logSLAActivity("SLA_ESCALATION",
+		fmt.Sprintf("Task \"%s\" escalated to admins after breaching SLA \"%s\"", breach.Title, policy.Name),
+		breach, map[string]interface{}{"adminCount": len(admins)})
+
+	if !policy.Reassign || breach.AssigneeID == nil {
+		return false
+	}
+
+	decision, err := reassignForSLA(policy.Pipeline, breach.SubjectID, breach.Status, *breach.AssigneeID)
+	if err != nil {
+		log.Printf("SLA scheduler failed to reassign %s: %v", breach.SubjectID, err)
+		return false
+	}
+	if decision == nil {
+		logSLAActivity("SLA_REASSIGN_SKIPPED",
+			fmt.Sprintf("No other assignee is available to take over \"%s\"", breach.Title),
+			breach, nil)
+		return false
+	}
+
+	newAssignee := decision.Candidate.UserID
+	database.DB.Model(&models.SLABreach{}).Where("id = ?", breach.ID).Updates(map[string]interface{}{
+		"reassigned_at":    now,
+		"reassigned_to_id": newAssignee,
+		"resolved_at":      now,
+	})
+	reason := fmt.Sprintf("The previous assignee missed the %s SLA for %s", overdue, breach.Status)
+	notifyUser(newAssignee, "Task reassigned to you: "+breach.Title, reason, breach.SubjectID)
+	if err := SendTaskAssignedEmail(newAssignee, policy.Pipeline, breach.Title, reason); err != nil {
+		log.Printf("Failed to email SLA reassignment of %s: %v", breach.SubjectID, err)
+	}
+
+	metadata := decision.Metadata()
+	metadata["newAssigneeId"] = newAssignee.String()
+	metadata["newAssigneeName"] = decision.Candidate.Name
+	logSLAActivity("SLA_REASSIGN",
+		fmt.Sprintf("Task \"%s\" reassigned to %s after breaching SLA \"%s\"", breach.Title, decision.Candidate.Name, policy.Name),
+		breach, metadata)
+	return true
+}
+
+func markSLABreach(id uuid.UUID, column string, now time.Time) bool {
+	result := database.DB.Model(&models.SLABreach{}).
+		Where("id = ? AND "+column+" IS NULL", id).
+		Update(column, now)
+	return result.Error == nil && result.RowsAffected > 0
+}
+
+func reassignForSLA(pipeline string, subjectID uuid.UUID, status string, current uuid.UUID) (*assignment.Decision, error) {
+	config := assignmentPipelines[pipeline]
+
+	var decision *assignment.Decision
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockAssignmentPipeline(tx, pipeline); err != nil {
+			return err
+		}
+
+		locked := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ? AND "+config.column+" = ?", subjectID, status, current)
+
+		var target interface{}
+		var task assignment.Task
+		if pipeline == models.PipelineProjectX {
+			var submission models.Submission
+			if err := locked.Take(&submission).Error; err != nil {
+				return err
+			}
+			target, task = &submission, projectXTask(&submission)
+		} else {
+			var submission models.ProjectVSubmission
+			if err := locked.Take(&submission).Error; err != nil {
+				return err
+			}
+			target, task = &submission, projectVTask(&submission)
+		}
+
+		var err error
+		decision, err = SelectAssignee(tx, pipeline, task, current)
+		if err != nil || decision == nil {
+			return err
+		}
+
+		updates := map[string]interface{}{config.column: decision.Candidate.UserID}
+		if pipeline == models.PipelineProjectX {
+			updates["assigned_at"] = time.Now()
+		}
+		return tx.Model(target).UpdateColumns(updates).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decision, nil
+}
+
+func notifyUser(userID uuid.UUID, title, message string, subjectID uuid.UUID) {
+	if validationHub == nil {
+		return
+	}
+	validationHub.BroadcastToUser(userID, "notification", map[string]interface{}{
+		"title":        title,
+		"message":      message,
+		"submissionId": subjectID,
+	})
+}
+
+func logSLAActivity(action, description string, breach *models.SLABreach, metadata map[string]interface{}) {
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadata["policyId"] = breach.PolicyID.String()
+	metadata["pipeline"] = breach.Pipeline
+	metadata["status"] = breach.Status
+	metadata["startedAt"] = breach.StartedAt
+	metadata["breachedAt"] = breach.BreachedAt
+	if breach.AssigneeID != nil {
+		metadata["assigneeId"] = breach.AssigneeID.String()
+	}
+
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	userName := "System"
+	userRole := "SYSTEM"
+	targetType := breach.SubjectType
+	LogActivity(LogActivityParams{
+		Action:      action,
+		Description: description,
+		UserID:      &userID,
+		UserName:    &userName,
+		UserRole:    &userRole,
+		TargetID:    &breach.SubjectID,
+		TargetType:  &targetType,
+		Metadata:    metadata,
+	})
+}
+
+func slaDuration(minutes int) string {
+	switch {
+	case minutes%(24*60) == 0:
+		return fmt.Sprintf("%dd", minutes/(24*60))
+	case minutes%60 == 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
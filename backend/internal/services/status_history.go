@@ -0,0 +1,123 @@
+package services
+
+import (
+	"time"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type StatusChange struct {
+	SubjectType string
+	SubjectID   uuid.UUID
+	From        string
+	To          string
+	Action      string
+	ActorID     *uuid.UUID
+	ActorName   string
+	ActorRole   string
+	Reason      string
+}
+
+type StatusTimelineEntry struct {
+	models.StatusTransition
+	DurationSeconds int64 `json:"durationSeconds"`
+	Current         bool  `json:"current"`
+}
+
+type StatusTimeline struct {
+	SubjectType   string                `json:"subjectType"`
+	SubjectID     uuid.UUID             `json:"subjectId"`
+	CurrentStatus string                `json:"currentStatus"`
+	Transitions   []StatusTimelineEntry `json:"transitions"`
+	TimeInStatus  map[string]int64      `json:"timeInStatus"`
+}
+
+func RecordStatusTransition(change StatusChange) error {
+	return RecordStatusTransitionTx(database.DB, change)
+}
+
+func RecordStatusTransitionTx(tx *gorm.DB, change StatusChange) error {
+	if change.From == change.To {
+		return nil
+	}
+
+	transition := models.StatusTransition{
+		SubjectType: change.SubjectType,
+		SubjectID:   change.SubjectID,
+		ToStatus:    change.To,
+		ActorID:     change.ActorID,
+		ActorRole:   change.ActorRole,
+	}
+	if change.From != "" {
+		transition.FromStatus = &change.From
+	}
+	if change.Action != "" {
+		transition.Action = &change.Action
+	}
+	if change.ActorName != "" {
+		transition.ActorName = &change.ActorName
+	}
+	if change.Reason != "" {
+		transition.Reason = &change.Reason
+	}
+	if transition.ActorRole == "" {
+		transition.ActorRole = "SYSTEM"
+	}
+
+	return tx.Create(&transition).Error
+}
+
+func SystemStatusChange(subjectType string, subjectID uuid.UUID, from, to, reason string) StatusChange {
+	return StatusChange{
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		From:        from,
+		To:          to,
+		ActorName:   "System",
+		ActorRole:   "SYSTEM",
+		Reason:      reason,
+	}
+}
+
+func GetStatusTimeline(subjectType string, subjectID uuid.UUID, currentStatus string) (*StatusTimeline, error) {
+	var transitions []models.StatusTransition
+	err := database.DB.
+		Where("subject_type = ? AND subject_id = ?", subjectType, subjectID).
+		Order("created_at ASC").
+		Find(&transitions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := &StatusTimeline{
+		SubjectType:   subjectType,
+		SubjectID:     subjectID,
+		CurrentStatus: currentStatus,
+		Transitions:   make([]StatusTimelineEntry, 0, len(transitions)),
+		TimeInStatus:  make(map[string]int64),
+	}
+
+	now := time.Now()
+	for i, transition := range transitions {
+		end := now
+		if i+1 < len(transitions) {
+			end = transitions[i+1].CreatedAt
+		}
+		duration := int64(end.Sub(transition.CreatedAt).Seconds())
+		if duration < 0 {
+			duration = 0
+		}
+
+		timeline.Transitions = append(timeline.Transitions, StatusTimelineEntry{
+			StatusTransition: transition,
+			DurationSeconds:  duration,
+			Current:          i == len(transitions)-1,
+		})
+		timeline.TimeInStatus[transition.ToStatus] += duration
+	}
+
+	return timeline, nil
+}
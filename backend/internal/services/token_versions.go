@@ -0,0 +1,109 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrTokenRevoked = errors.New("token has been revoked")
+
+type tokenVersionEntry struct {
+	version  int
+	exists   bool
+	loadedAt time.Time
+}
+
+var (
+	tokenVersionsMu   sync.RWMutex
+	tokenVersions     = make(map[uuid.UUID]tokenVersionEntry)
+	tokenVersionOnce  sync.Once
+	tokenVersionTTL   time.Duration
+	tokenVersionLimit int
+)
+
+func loadTokenVersionConfig() {
+	tokenVersionTTL = envDuration("TOKEN_VERSION_CACHE_TTL", 30*time.Second)
+	tokenVersionLimit = envInt("TOKEN_VERSION_CACHE_SIZE", 10000)
+}
+
+func CheckTokenVersion(userID uuid.UUID, version int) error {
+	current, exists, err := currentTokenVersion(userID)
+	if err != nil {
+		return err
+	}
+	if !exists || current != version {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+func currentTokenVersion(userID uuid.UUID) (int, bool, error) {
+	tokenVersionOnce.Do(loadTokenVersionConfig)
+
+	tokenVersionsMu.RLock()
+	entry, ok := tokenVersions[userID]
+	tokenVersionsMu.RUnlock()
+	if ok && time.Since(entry.loadedAt) < tokenVersionTTL {
+		return entry.version, entry.exists, nil
+	}
+
+	var user models.User
+	err := database.DB.Select("id", "token_version").First(&user, userID).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, false, err
+	}
+
+	entry = tokenVersionEntry{version: user.TokenVersion, exists: err == nil, loadedAt: time.Now()}
+	cacheTokenVersion(userID, entry)
+	return entry.version, entry.exists, nil
+}
+
+func cacheTokenVersion(userID uuid.UUID, entry tokenVersionEntry) {
+	tokenVersionOnce.Do(loadTokenVersionConfig)
+
+	tokenVersionsMu.Lock()
+	defer tokenVersionsMu.Unlock()
+
+	if cached, ok := tokenVersions[userID]; ok && entry.exists && (!cached.exists || cached.version > entry.version) {
+		entry.version, entry.exists = cached.version, cached.exists
+	}
+	if len(tokenVersions) >= tokenVersionLimit {
+		for id, cached := range tokenVersions {
+			if time.Since(cached.loadedAt) >= tokenVersionTTL {
+				delete(tokenVersions, id)
+			}
+		}
+		if len(tokenVersions) >= tokenVersionLimit {
+			tokenVersions = make(map[uuid.UUID]tokenVersionEntry)
+		}
+	}
+	tokenVersions[userID] = entry
+}
+
+func BumpTokenVersion(userID uuid.UUID) error {
+	var version int
+	err := database.DB.Raw("UPDATE users SET token_version = token_version + 1 WHERE id = ? RETURNING token_version", userID).
+		Scan(&version).Error
+	if err != nil {
+		return err
+	}
+	cacheTokenVersion(userID, tokenVersionEntry{version: version, exists: true, loadedAt: time.Now()})
+	return nil
+}
+
+func RevokeUserTokens(userID uuid.UUID) error {
+	if err := BumpTokenVersion(userID); err != nil {
+		return err
+	}
+	return RevokeUserRefreshTokens(userID)
+}
+
+func ForgetDeletedUser(userID uuid.UUID) {
+	cacheTokenVersion(userID, tokenVersionEntry{exists: false, loadedAt: time.Now()})
+}
@@ -0,0 +1,551 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/adzzatxperts/backend/internal/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotStarted     = errors.New("two-factor setup has not been started")
+	ErrTwoFactorRequired       = errors.New("two-factor authentication is required for your role")
+	ErrInvalidTwoFactorCode    = errors.New("invalid authentication code")
+	ErrInvalidChallenge        = errors.New("invalid or expired sign-in challenge")
+	ErrTooManyAttempts         = errors.New("too many failed attempts, please try again later")
+	ErrInvalidRole             = errors.New("invalid role")
+)
+
+const (
+	ChallengeTwoFactor      = "2fa"
+	ChallengeTwoFactorSetup = "2fa_setup"
+
+	recoveryCodeCount = 10
+)
+
+var twoFactorRoles = []models.UserRole{
+	models.RoleContributor,
+	models.RoleTester,
+	models.RoleReviewer,
+	models.RoleAdmin,
+}
+
+type TwoFactorStatus struct {
+	Enabled                bool       `json:"enabled"`
+	Required               bool       `json:"required"`
+	Pending                bool       `json:"pending"`
+	EnabledAt              *time.Time `json:"enabledAt,omitempty"`
+	RecoveryCodesRemaining int64      `json:"recoveryCodesRemaining"`
+}
+
+type TwoFactorSetup struct {
+	Secret     string `json:"secret"`
+	OtpauthURL string `json:"otpauthUrl"`
+}
+
+type SigninChallenge struct {
+	ChallengeToken string `json:"challengeToken"`
+	Purpose        string `json:"purpose"`
+	ExpiresIn      int    `json:"expiresIn"`
+}
+
+type TwoFactorPolicyView struct {
+	Role        models.UserRole `json:"role"`
+	Required    bool            `json:"required"`
+	TotalUsers  int64           `json:"totalUsers"`
+	Enrolled    int64           `json:"enrolled"`
+	UpdatedByID *uuid.UUID      `json:"updatedById,omitempty"`
+	UpdatedAt   *time.Time      `json:"updatedAt,omitempty"`
+}
+
+var (
+	usedChallengesMu sync.Mutex
+	usedChallenges   = make(map[string]time.Time)
+)
+
+func GetTwoFactorStatus(user *models.User) (*TwoFactorStatus, error) {
+	required, err := IsTwoFactorRequired(user.Role)
+	if err != nil {
+		return nil, err
+	}
+	status := &TwoFactorStatus{Required: required}
+
+	var record models.TwoFactorAuth
+	err = database.DB.Where("user_id = ?", user.ID).First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return status, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	status.Enabled = record.EnabledAt != nil
+	status.Pending = record.EnabledAt == nil
+	status.EnabledAt = record.EnabledAt
+	database.DB.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", user.ID).
+		Count(&status.RecoveryCodesRemaining)
+	return status, nil
+}
+
+func IsTwoFactorRequired(role models.UserRole) (bool, error) {
+	var policy models.TwoFactorPolicy
+	err := database.DB.Where("role = ?", role).First(&policy).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return policy.Required, nil
+}
+
+func BeginTwoFactorSetup(user *models.User) (*TwoFactorSetup, error) {
+	var existing models.TwoFactorAuth
+	err := database.DB.Where("user_id = ?", user.ID).First(&existing).Error
+	if err == nil && existing.EnabledAt != nil {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := utils.EncryptSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	record := models.TwoFactorAuth{UserID: user.ID, Secret: encrypted}
+	err = database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "last_used_step", "updated_at"}),
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "two_factor_auths.enabled_at IS NULL"}}},
+	}).Create(&record).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorSetup{
+		Secret:     secret,
+		OtpauthURL: utils.TOTPProvisioningURI(twoFactorIssuer(), user.Email, secret),
+	}, nil
+}
+
+func EnableTwoFactor(userID uuid.UUID, code string) ([]string, error) {
+	var codes []string
+	var failure error
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var record models.TwoFactorAuth
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&record).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTwoFactorNotStarted
+		}
+		if err != nil {
+			return err
+		}
+		if record.EnabledAt != nil {
+			return ErrTwoFactorAlreadyEnabled
+		}
+		if twoFactorLocked(&record) {
+			return ErrTooManyAttempts
+		}
+
+		step, err := checkTOTP(&record, code)
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			failure, err = failTwoFactor(tx, userID)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&record).Updates(map[string]interface{}{
+			"enabled_at":      time.Now(),
+			"last_used_step":  step,
+			"failed_attempts": 0,
+			"locked_until":    nil,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		codes, err = replaceRecoveryCodes(tx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if failure != nil {
+		return nil, failure
+	}
+	return codes, nil
+}
+
+func DisableTwoFactor(user *models.User, code string) error {
+	required, err := IsTwoFactorRequired(user.Role)
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrTwoFactorRequired
+	}
+
+	_, err = withTwoFactorCode(user.ID, code, func(tx *gorm.DB) error {
+		return deleteTwoFactor(tx, user.ID)
+	})
+	return err
+}
+
+func ResetTwoFactor(userID uuid.UUID) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		return deleteTwoFactor(tx, userID)
+	})
+}
+
+func RegenerateRecoveryCodes(userID uuid.UUID, code string) ([]string, error) {
+	var codes []string
+	_, err := withTwoFactorCode(userID, code, func(tx *gorm.DB) error {
+		var err error
+		codes, err = replaceRecoveryCodes(tx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func NewSigninChallenge(user *models.User) (*SigninChallenge, error) {
+	status, err := GetTwoFactorStatus(user)
+	if err != nil {
+		return nil, err
+	}
+
+	purpose := ""
+	switch {
+	case status.Enabled:
+		purpose = ChallengeTwoFactor
+	case status.Required:
+		purpose = ChallengeTwoFactorSetup
+	default:
+		return nil, nil
+	}
+
+	ttl := envDuration("TWO_FACTOR_CHALLENGE_TTL", 5*time.Minute)
+	token, err := utils.GenerateChallengeToken(user.ID.String(), purpose, ttl)
+	if err != nil {
+		return nil, err
+	}
+	return &SigninChallenge{ChallengeToken: token, Purpose: purpose, ExpiresIn: int(ttl.Seconds())}, nil
+}
+
+func CompleteTwoFactorSignin(challengeToken, code string) (*models.User, bool, error) {
+	user, claims, err := openChallenge(challengeToken, ChallengeTwoFactor)
+	if err != nil {
+		return nil, false, err
+	}
+
+	usedRecovery, err := withTwoFactorCode(user.ID, code, nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	consumeChallenge(claims)
+	return user, usedRecovery, nil
+}
+
+func BeginChallengeSetup(challengeToken string) (*TwoFactorSetup, error) {
+	user, _, err := openChallenge(challengeToken, ChallengeTwoFactorSetup)
+	if err != nil {
+		return nil, err
+	}
+	return BeginTwoFactorSetup(user)
+}
+
+func CompleteChallengeSetup(challengeToken, code string) (*models.User, []string, error) {
+	user, claims, err := openChallenge(challengeToken, ChallengeTwoFactorSetup)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	codes, err := EnableTwoFactor(user.ID, code)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	consumeChallenge(claims)
+	return user, codes, nil
+}
+
+func ListTwoFactorPolicies() ([]TwoFactorPolicyView, error) {
+	var policies []models.TwoFactorPolicy
+	if err := database.DB.Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	byRole := make(map[models.UserRole]*models.TwoFactorPolicy, len(policies))
+	for i := range policies {
+		byRole[policies[i].Role] = &policies[i]
+	}
+
+	views := make([]TwoFactorPolicyView, 0, len(twoFactorRoles))
+	for _, role := range twoFactorRoles {
+		view := TwoFactorPolicyView{Role: role}
+		if policy, ok := byRole[role]; ok {
+			view.Required = policy.Required
+			view.UpdatedByID = policy.UpdatedByID
+			view.UpdatedAt = &policy.UpdatedAt
+		}
+		database.DB.Model(&models.User{}).Where("role = ?", role).Count(&view.TotalUsers)
+		database.DB.Model(&models.TwoFactorAuth{}).
+			Joins("JOIN users ON users.id = two_factor_auths.user_id").
+			Where("users.role = ? AND two_factor_auths.enabled_at IS NOT NULL", role).
+			Count(&view.Enrolled)
+		views = append(views, view)
+	}
+	return views, nil
+}
+
+func SetTwoFactorPolicy(role models.UserRole, required bool, updatedBy *uuid.UUID) (*models.TwoFactorPolicy, error) {
+	known := false
+	for _, candidate := range twoFactorRoles {
+		if candidate == role {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return nil, ErrInvalidRole
+	}
+
+	policy := models.TwoFactorPolicy{Role: role, Required: required, UpdatedByID: updatedBy}
+	err := database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "role"}},
+		DoUpdates: clause.AssignmentColumns([]string{"required", "updated_by_id", "updated_at"}),
+	}).Create(&policy).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if err := database.DB.Where("role = ?", role).First(&policy).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func withTwoFactorCode(userID uuid.UUID, code string, fn func(tx *gorm.DB) error) (bool, error) {
+	var usedRecovery bool
+	var failure error
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		usedRecovery, err = verifyTwoFactorCode(tx, userID, code)
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			failure, err = failTwoFactor(tx, userID)
+			return err
+		}
+		if err != nil || fn == nil {
+			return err
+		}
+		return fn(tx)
+	})
+	if err != nil {
+		return false, err
+	}
+	if failure != nil {
+		return false, failure
+	}
+	return usedRecovery, nil
+}
+
+func verifyTwoFactorCode(tx *gorm.DB, userID uuid.UUID, code string) (bool, error) {
+	var record models.TwoFactorAuth
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, ErrTwoFactorNotEnabled
+	}
+	if err != nil {
+		return false, err
+	}
+	if record.EnabledAt == nil {
+		return false, ErrTwoFactorNotEnabled
+	}
+	if twoFactorLocked(&record) {
+		return false, ErrTooManyAttempts
+	}
+
+	step, err := checkTOTP(&record, code)
+	if err == nil {
+		return false, tx.Model(&record).Updates(map[string]interface{}{
+			"last_used_step":  step,
+			"failed_attempts": 0,
+			"locked_until":    nil,
+		}).Error
+	}
+	if !errors.Is(err, ErrInvalidTwoFactorCode) {
+		return false, err
+	}
+
+	result := tx.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hashRecoveryCode(code)).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, ErrInvalidTwoFactorCode
+	}
+	err = tx.Model(&record).Updates(map[string]interface{}{
+		"failed_attempts": 0,
+		"locked_until":    nil,
+	}).Error
+	return true, err
+}
+
+func twoFactorLocked(record *models.TwoFactorAuth) bool {
+	return record.LockedUntil != nil && time.Now().Before(*record.LockedUntil)
+}
+
+func failTwoFactor(tx *gorm.DB, userID uuid.UUID) (failure error, err error) {
+	maxAttempts := envInt("TWO_FACTOR_MAX_ATTEMPTS", 5)
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	now := time.Now()
+	lockedUntil := now.Add(envDuration("TWO_FACTOR_LOCKOUT", 15*time.Minute))
+
+	var state struct {
+		FailedAttempts int
+		LockedUntil    *time.Time
+	}
+	err = tx.Raw(`
+		UPDATE two_factor_auths
+		SET failed_attempts = failed_attempts + 1,
+			locked_until = CASE WHEN (failed_attempts + 1) % ? = 0 THEN ?::timestamptz ELSE locked_until END,
+			updated_at = ?
+		WHERE user_id = ?
+		RETURNING failed_attempts, locked_until
+	`, maxAttempts, lockedUntil, now, userID).Scan(&state).Error
+	if err != nil {
+		return nil, err
+	}
+	if state.LockedUntil != nil && now.Before(*state.LockedUntil) {
+		return ErrTooManyAttempts, nil
+	}
+	return ErrInvalidTwoFactorCode, nil
+}
+
+func checkTOTP(record *models.TwoFactorAuth, code string) (int64, error) {
+	secret, err := utils.DecryptSecret(record.Secret)
+	if err != nil {
+		return 0, err
+	}
+	step, ok := utils.ValidateTOTPCode(secret, code, time.Now(), 1)
+	if !ok || step <= record.LastUsedStep {
+		return 0, ErrInvalidTwoFactorCode
+	}
+	return step, nil
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uuid.UUID) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]models.RecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		bytes := make([]byte, 10)
+		if _, err := rand.Read(bytes); err != nil {
+			return nil, err
+		}
+		raw := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(bytes))
+		code := raw[:8] + "-" + raw[8:16]
+		codes = append(codes, code)
+		records = append(records, models.RecoveryCode{UserID: userID, CodeHash: hashRecoveryCode(code)})
+	}
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func deleteTwoFactor(tx *gorm.DB, userID uuid.UUID) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	result := tx.Where("user_id = ?", userID).Delete(&models.TwoFactorAuth{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTwoFactorNotEnabled
+	}
+	return nil
+}
+
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+func openChallenge(token, purpose string) (*models.User, *utils.Claims, error) {
+	claims, err := utils.ValidateChallengeToken(token, purpose)
+	if err != nil {
+		return nil, nil, ErrInvalidChallenge
+	}
+
+	usedChallengesMu.Lock()
+	_, used := usedChallenges[claims.ID]
+	usedChallengesMu.Unlock()
+	if used {
+		return nil, nil, ErrInvalidChallenge
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return nil, nil, ErrInvalidChallenge
+	}
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		return nil, nil, ErrInvalidChallenge
+	}
+	return &user, claims, nil
+}
+
+func consumeChallenge(claims *utils.Claims) {
+	now := time.Now()
+
+	usedChallengesMu.Lock()
+	defer usedChallengesMu.Unlock()
+	for id, expiresAt := range usedChallenges {
+		if !now.Before(expiresAt) {
+			delete(usedChallenges, id)
+		}
+	}
+	usedChallenges[claims.ID] = claims.ExpiresAt.Time
+}
+
+func twoFactorIssuer() string {
+	if issuer := os.Getenv("TWO_FACTOR_ISSUER"); issuer != "" {
+		return issuer
+	}
+	return "Adzzat"
+}
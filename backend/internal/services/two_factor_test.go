@@ -0,0 +1,81 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/adzzatxperts/backend/internal/utils"
+)
+
+func TestTwoFactorLockout(t *testing.T) {
+	setupTestDB(t)
+	if os.Getenv("TWO_FACTOR_ENCRYPTION_KEY") == "" && os.Getenv("JWT_SECRET") == "" {
+		t.Setenv("TWO_FACTOR_ENCRYPTION_KEY", "two-factor-test-key")
+	}
+	t.Setenv("TWO_FACTOR_MAX_ATTEMPTS", "3")
+	t.Setenv("TWO_FACTOR_LOCKOUT", "1h")
+
+	user := createTestUser(t, models.RoleTester, "two-factor")
+	setup, err := BeginTwoFactorSetup(&user)
+	if err != nil {
+		t.Fatalf("BeginTwoFactorSetup: %v", err)
+	}
+	step := utils.TOTPStep(time.Now())
+	code, err := utils.GenerateTOTPCode(setup.Secret, step-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := EnableTwoFactor(user.ID, code); err != nil {
+		t.Fatalf("EnableTwoFactor: %v", err)
+	}
+
+	results := make([]error, 10)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, results[i] = withTwoFactorCode(user.ID, "not-a-code", nil)
+		}(i)
+	}
+	wg.Wait()
+
+	var invalid, locked int
+	for _, err := range results {
+		switch {
+		case errors.Is(err, ErrInvalidTwoFactorCode):
+			invalid++
+		case errors.Is(err, ErrTooManyAttempts):
+			locked++
+		default:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if invalid != 2 || locked != 8 {
+		t.Fatalf("got %d invalid and %d locked responses, want 2 and 8", invalid, locked)
+	}
+
+	code, err = utils.GenerateTOTPCode(setup.Secret, step)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := withTwoFactorCode(user.ID, code, nil); !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("valid code while locked: %v, want ErrTooManyAttempts", err)
+	}
+
+	database.DB.Model(&models.TwoFactorAuth{}).Where("user_id = ?", user.ID).Update("locked_until", time.Now().Add(-time.Minute))
+	if _, err := withTwoFactorCode(user.ID, code, nil); err != nil {
+		t.Fatalf("valid code after the lockout: %v", err)
+	}
+
+	var record models.TwoFactorAuth
+	database.DB.Where("user_id = ?", user.ID).First(&record)
+	if record.FailedAttempts != 0 || record.LockedUntil != nil {
+		t.Fatalf("failed attempts = %d, locked until %v after a valid code, want a reset", record.FailedAttempts, record.LockedUntil)
+	}
+}
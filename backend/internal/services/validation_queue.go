@@ -0,0 +1,436 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	ValidationTriggerCreate   = "CREATE"
+	ValidationTriggerResubmit = "RESUBMIT"
+	ValidationTriggerRerun    = "ADMIN_RERUN"
+)
+
+var (
+	ErrValidationJobNotFound    = errors.New("validation job not found")
+	ErrValidationJobNotActive   = errors.New("validation job is not pending or running")
+	ErrValidationJobStillActive = errors.New("validation job is still pending or running")
+)
+
+type ValidationQueueConfig struct {
+	Workers      int
+	MaxAttempts  int
+	PollInterval time.Duration
+	RetryBackoff time.Duration
+	StaleAfter   time.Duration
+}
+
+type validationQueue struct {
+	config   ValidationQueueConfig
+	workerID string
+
+	mu      sync.Mutex
+	running map[uuid.UUID]context.CancelFunc
+}
+
+var queue *validationQueue
+
+func LoadValidationQueueConfig() ValidationQueueConfig {
+	return ValidationQueueConfig{
+		Workers:      envInt("VALIDATION_WORKERS", 2),
+		MaxAttempts:  envInt("VALIDATION_MAX_ATTEMPTS", 3),
+		PollInterval: envDuration("VALIDATION_POLL_INTERVAL", 5*time.Second),
+		RetryBackoff: envDuration("VALIDATION_RETRY_BACKOFF", 30*time.Second),
+		StaleAfter:   envDuration("VALIDATION_STALE_AFTER", 2*time.Minute),
+	}
+}
+
+func StartValidationQueue(config ValidationQueueConfig) error {
+	if config.Workers < 1 {
+		config.Workers = 1
+	}
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+
+	hostname, _ := os.Hostname()
+	queue = &validationQueue{
+		config:   config,
+		workerID: fmt.Sprintf("%s-%s", hostname, uuid.New().String()[:8]),
+		running:  make(map[uuid.UUID]context.CancelFunc),
+	}
+
+	recovered, err := queue.recoverOrphanedJobs()
+	if err != nil {
+		return fmt.Errorf("failed to recover orphaned validation jobs: %w", err)
+	}
+	if recovered > 0 {
+		log.Printf("  ♻️  Recovered %d orphaned validation jobs", recovered)
+	}
+
+	for i := 0; i < config.Workers; i++ {
+		go queue.work(fmt.Sprintf("%s/%d", queue.workerID, i))
+	}
+	go queue.heartbeat()
+
+	return nil
+}
+
+func EnqueueValidation(submissionID uuid.UUID, trigger string, requestedByID *uuid.UUID) (*models.ValidationJob, error) {
+	maxAttempts := 3
+	if queue != nil {
+		maxAttempts = queue.config.MaxAttempts
+	}
+
+	var job models.ValidationJob
+	var superseded []uuid.UUID
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var submission models.ProjectVSubmission
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&submission, submissionID).Error; err != nil {
+			return err
+		}
+
+		var err error
+		superseded, err = cancelActiveJobs(tx, "Superseded by a newer validation job", "submission_id = ?", submissionID)
+		if err != nil {
+			return err
+		}
+
+		job = models.ValidationJob{
+			SubmissionID:  submissionID,
+			Status:        models.ValidationJobPending,
+			Trigger:       trigger,
+			MaxAttempts:   maxAttempts,
+			RunAfter:      time.Now(),
+			RequestedByID: requestedByID,
+		}
+		return tx.Create(&job).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stopRunningJobs(superseded)
+	return &job, nil
+}
+
+func CancelValidationJob(jobID uuid.UUID) (*models.ValidationJob, error) {
+	var job models.ValidationJob
+	if err := database.DB.First(&job, jobID).Error; err != nil {
+		return nil, ErrValidationJobNotFound
+	}
+
+	if job.Status != models.ValidationJobPending && job.Status != models.ValidationJobRunning {
+		return nil, ErrValidationJobNotActive
+	}
+
+	if err := cancelJob(job.ID, "Cancelled by admin"); err != nil {
+		return nil, err
+	}
+
+	database.DB.First(&job, jobID)
+	return &job, nil
+}
+
+func RerunValidationJob(jobID uuid.UUID, requestedByID *uuid.UUID) (*models.ValidationJob, error) {
+	var job models.ValidationJob
+	if err := database.DB.First(&job, jobID).Error; err != nil {
+		return nil, ErrValidationJobNotFound
+	}
+
+	if job.Status == models.ValidationJobPending || job.Status == models.ValidationJobRunning {
+		return nil, ErrValidationJobStillActive
+	}
+
+	return EnqueueValidation(job.SubmissionID, ValidationTriggerRerun, requestedByID)
+}
+
+func CancelValidationJobsForSubmission(submissionID uuid.UUID, reason string) {
+	ids, err := cancelActiveJobs(database.DB, reason, "submission_id = ?", submissionID)
+	if err != nil {
+		log.Printf("Failed to cancel validation jobs for submission %s: %v", submissionID, err)
+	}
+	stopRunningJobs(ids)
+}
+
+func cancelJob(jobID uuid.UUID, reason string) error {
+	ids, err := cancelActiveJobs(database.DB, reason, "id = ?", jobID)
+	if err != nil {
+		return err
+	}
+	stopRunningJobs(ids)
+	return nil
+}
+
+var activeJobStatuses = []models.ValidationJobStatus{
+	models.ValidationJobPending,
+	models.ValidationJobRunning,
+}
+
+func cancelActiveJobs(tx *gorm.DB, reason string, query interface{}, args ...interface{}) ([]uuid.UUID, error) {
+	var cancelled []models.ValidationJob
+	err := tx.Model(&cancelled).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+		Where(query, args...).
+		Where("status IN ?", activeJobStatuses).
+		Updates(map[string]interface{}{
+			"status":      models.ValidationJobCancelled,
+			"finished_at": time.Now(),
+			"last_error":  reason,
+			"locked_by":   nil,
+			"locked_at":   nil,
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(cancelled))
+	for i, job := range cancelled {
+		ids[i] = job.ID
+	}
+	return ids, nil
+}
+
+func stopRunningJobs(ids []uuid.UUID) {
+	if queue == nil {
+		return
+	}
+
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	for _, id := range ids {
+		if cancel, ok := queue.running[id]; ok {
+			cancel()
+		}
+	}
+}
+
+func (q *validationQueue) work(workerID string) {
+	ticker := time.NewTicker(q.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			job, err := q.claimNext(workerID)
+			if err != nil {
+				log.Printf("Validation worker %s failed to claim job: %v", workerID, err)
+				break
+			}
+			if job == nil {
+				break
+			}
+			q.run(job)
+		}
+
+		<-ticker.C
+	}
+}
+
+func (q *validationQueue) claimNext(workerID string) (*models.ValidationJob, error) {
+	var job models.ValidationJob
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND run_after <= ?", models.ValidationJobPending, time.Now()).
+			Order("run_after ASC").
+			First(&job).Error
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		job.Status = models.ValidationJobRunning
+		job.Attempts++
+		job.LockedBy = &workerID
+		job.LockedAt = &now
+		job.StartedAt = &now
+
+		return tx.Model(&job).Updates(map[string]interface{}{
+			"status":     job.Status,
+			"attempts":   job.Attempts,
+			"locked_by":  workerID,
+			"locked_at":  now,
+			"started_at": now,
+		}).Error
+	})
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+func (q *validationQueue) run(job *models.ValidationJob) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	q.mu.Lock()
+	q.running[job.ID] = cancel
+	q.mu.Unlock()
+
+	defer func() {
+		q.mu.Lock()
+		delete(q.running, job.ID)
+		q.mu.Unlock()
+	}()
+
+	log.Printf("Validation job %s started for submission %s (attempt %d/%d)", job.ID, job.SubmissionID, job.Attempts, job.MaxAttempts)
+
+	err := ProcessProjectVSubmission(ctx, job.SubmissionID, job)
+
+	var current models.ValidationJob
+	if dbErr := database.DB.First(&current, job.ID).Error; dbErr != nil || current.Status != models.ValidationJobRunning {
+		return
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"locked_by":   nil,
+		"locked_at":   nil,
+		"finished_at": now,
+	}
+
+	switch {
+	case err == nil:
+		updates["status"] = models.ValidationJobSucceeded
+		updates["last_error"] = ""
+	case current.Attempts < current.MaxAttempts:
+		backoff := q.config.RetryBackoff * time.Duration(1<<uint(current.Attempts-1))
+		updates["status"] = models.ValidationJobPending
+		updates["last_error"] = err.Error()
+		updates["run_after"] = now.Add(backoff)
+		updates["finished_at"] = nil
+		log.Printf("Validation job %s failed, retrying in %s: %v", job.ID, backoff, err)
+	default:
+		updates["status"] = models.ValidationJobFailed
+		updates["last_error"] = err.Error()
+		log.Printf("Validation job %s failed permanently: %v", job.ID, err)
+	}
+
+	database.DB.Model(&models.ValidationJob{}).
+		Where("id = ? AND status = ?", job.ID, models.ValidationJobRunning).
+		Updates(updates)
+}
+
+func (q *validationQueue) heartbeat() {
+	ticker := time.NewTicker(q.config.StaleAfter / 4)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		q.mu.Lock()
+		ids := make([]uuid.UUID, 0, len(q.running))
+		for id := range q.running {
+			ids = append(ids, id)
+		}
+		q.mu.Unlock()
+
+		if len(ids) > 0 {
+			database.DB.Model(&models.ValidationJob{}).
+				Where("id IN ? AND status = ?", ids, models.ValidationJobRunning).
+				Update("locked_at", time.Now())
+
+			var stillRunning []uuid.UUID
+			err := database.DB.Model(&models.ValidationJob{}).
+				Where("id IN ? AND status = ?", ids, models.ValidationJobRunning).
+				Pluck("id", &stillRunning).Error
+			if err == nil {
+				stopRunningJobs(missingIDs(ids, stillRunning))
+			}
+		}
+
+		if recovered, err := q.recoverOrphanedJobs(); err == nil && recovered > 0 {
+			log.Printf("Recovered %d orphaned validation jobs", recovered)
+		}
+	}
+}
+
+func missingIDs(ids, present []uuid.UUID) []uuid.UUID {
+	found := make(map[uuid.UUID]bool, len(present))
+	for _, id := range present {
+		found[id] = true
+	}
+
+	var missing []uuid.UUID
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+func (q *validationQueue) recoverOrphanedJobs() (int, error) {
+	var orphaned []models.ValidationJob
+	err := database.DB.Where("status = ? AND (locked_at IS NULL OR locked_at < ?)",
+		models.ValidationJobRunning, time.Now().Add(-q.config.StaleAfter)).
+		Find(&orphaned).Error
+	if err != nil {
+		return 0, err
+	}
+
+	recovered := 0
+	for _, job := range orphaned {
+		updates := map[string]interface{}{
+			"locked_by":  nil,
+			"locked_at":  nil,
+			"last_error": "Worker stopped before the job finished",
+		}
+		if job.Attempts < job.MaxAttempts {
+			updates["status"] = models.ValidationJobPending
+			updates["run_after"] = time.Now()
+		} else {
+			updates["status"] = models.ValidationJobFailed
+			updates["finished_at"] = time.Now()
+		}
+
+		result := database.DB.Model(&models.ValidationJob{}).
+			Where("id = ? AND status = ?", job.ID, models.ValidationJobRunning).
+			Updates(updates)
+		if result.Error == nil && result.RowsAffected > 0 {
+			recovered++
+			if updates["status"] == models.ValidationJobFailed {
+				database.DB.Model(&models.ProjectVSubmission{}).
+					Where("id = ?", job.SubmissionID).
+					Update("processing_complete", true)
+			}
+		}
+	}
+
+	return recovered, nil
+}
+
+func envInt(key string, fallback int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+		log.Printf("⚠️  Invalid value for %s: %q, using %d", key, value, fallback)
+	}
+	return fallback
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			return parsed
+		}
+		log.Printf("⚠️  Invalid value for %s: %q, using %s", key, value, fallback)
+	}
+	return fallback
+}
@@ -0,0 +1,347 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/adzzatxperts/backend/internal/testreport"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const maxStepOutputBytes = 64 * 1024
+
+type validationRecorder struct {
+	run          *models.ValidationRun
+	steps        map[string]*models.ValidationStep
+	logs         *validationLog
+	current      *models.ValidationStep
+	stepLogStart int
+	failed       bool
+}
+
+func startValidationRun(submission *models.ProjectVSubmission, job *models.ValidationJob, logs *validationLog) (*validationRecorder, error) {
+	run := &models.ValidationRun{
+		SubmissionID:     submission.ID,
+		Status:           models.ValidationRunRunning,
+		CommitHash:       submission.CommitHash,
+		TestPatchKey:     submission.TestPatchURL,
+		DockerfileKey:    submission.DockerfileURL,
+		SolutionPatchKey: submission.SolutionPatchURL,
+		StartedAt:        time.Now(),
+	}
+	if job != nil {
+		run.JobID = &job.ID
+		run.Trigger = job.Trigger
+	}
+
+	for i, name := range models.ValidationStepNames {
+		run.Steps = append(run.Steps, models.ValidationStep{
+			Position: i + 1,
+			Name:     name,
+			Status:   models.ValidationStepPending,
+		})
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var lastRunNumber int
+		if err := tx.Model(&models.ValidationRun{}).
+			Where("submission_id = ?", submission.ID).
+			Select("COALESCE(MAX(run_number), 0)").
+			Scan(&lastRunNumber).Error; err != nil {
+			return err
+		}
+		run.RunNumber = lastRunNumber + 1
+		return tx.Create(run).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logs.stream = openValidationStream(submission, run.ID)
+
+	recorder := &validationRecorder{
+		run:   run,
+		steps: make(map[string]*models.ValidationStep),
+		logs:  logs,
+	}
+	for i := range run.Steps {
+		recorder.steps[run.Steps[i].Name] = &run.Steps[i]
+	}
+
+	return recorder, nil
+}
+
+func (r *validationRecorder) startStep(name string) {
+	step := r.steps[name]
+	now := time.Now()
+
+	step.Status = models.ValidationStepRunning
+	step.StartedAt = &now
+	database.DB.Save(step)
+	r.logs.stream.step(step.Name, step.Status)
+
+	r.current = step
+	r.stepLogStart = r.logs.Len()
+}
+
+func (r *validationRecorder) recordLimits(limits ContainerLimits) {
+	if r.current == nil {
+		return
+	}
+	r.current.Limits = limits.JSON()
+	database.DB.Model(r.current).Update("limits", r.current.Limits)
+}
+
+func (r *validationRecorder) recordTestSummary(summary *testreport.Summary) {
+	if r.current == nil || summary == nil {
+		return
+	}
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return
+	}
+	encoded := string(data)
+	r.current.TestSummary = &encoded
+	database.DB.Model(r.current).Update("test_summary", r.current.TestSummary)
+}
+
+func (r *validationRecorder) passStep(cmdErr error) {
+	r.finishStep(models.ValidationStepPassed, cmdErr, "")
+}
+
+func (r *validationRecorder) skipStep(message string) {
+	r.finishStep(models.ValidationStepSkipped, nil, message)
+}
+
+func (r *validationRecorder) failStep(cmdErr error, message string) {
+	r.failed = true
+	r.finishStep(models.ValidationStepFailed, cmdErr, message)
+}
+
+func (r *validationRecorder) finishStep(status models.ValidationStepStatus, cmdErr error, message string) {
+	step := r.current
+	if step == nil {
+		return
+	}
+
+	now := time.Now()
+	step.Status = status
+	step.FinishedAt = &now
+	if step.StartedAt != nil {
+		step.DurationMs = now.Sub(*step.StartedAt).Milliseconds()
+	}
+	if status != models.ValidationStepSkipped {
+		step.ExitCode = exitCodeOf(cmdErr)
+	}
+	step.Output, step.OutputTruncated = truncateOutput(r.logs.Since(r.stepLogStart), maxStepOutputBytes)
+	step.Error = message
+	database.DB.Save(step)
+	r.logs.stream.step(step.Name, step.Status)
+
+	r.current = nil
+}
+
+func (r *validationRecorder) complete(runErr error) {
+	if r.current != nil {
+		message := "Step did not finish"
+		if runErr != nil {
+			message = runErr.Error()
+		}
+		r.failStep(runErr, message)
+	}
+
+	for _, name := range models.ValidationStepNames {
+		step := r.steps[name]
+		if step.Status == models.ValidationStepPending {
+			step.Status = models.ValidationStepSkipped
+			database.DB.Save(step)
+			r.logs.stream.step(step.Name, step.Status)
+		}
+	}
+
+	now := time.Now()
+	run := r.run
+	run.FinishedAt = &now
+	run.DurationMs = now.Sub(run.StartedAt).Milliseconds()
+
+	switch {
+	case errors.Is(runErr, context.Canceled):
+		run.Status = models.ValidationRunCancelled
+		run.Error = runErr.Error()
+	case runErr != nil:
+		run.Status = models.ValidationRunErrored
+		run.Error = runErr.Error()
+	case r.failed:
+		run.Status = models.ValidationRunFailed
+		for _, name := range models.ValidationStepNames {
+			if step := r.steps[name]; step.Status == models.ValidationStepFailed {
+				run.Error = fmt.Sprintf("%s: %s", step.Name, step.Error)
+				break
+			}
+		}
+	default:
+		run.Status = models.ValidationRunPassed
+	}
+
+	database.DB.Omit("Steps").Save(run)
+	r.logs.stream.finish(run.Status)
+}
+
+func exitCodeOf(err error) *int {
+	code := 0
+	if err == nil {
+		return &code
+	}
+
+	var containerErr *ContainerExitError
+	if errors.As(err, &containerErr) {
+		code = containerErr.ExitCode
+		return &code
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code = exitErr.ExitCode()
+		return &code
+	}
+
+	return nil
+}
+
+func truncateOutput(output string, limit int) (string, bool) {
+	if len(output) <= limit {
+		return output, false
+	}
+
+	marker := "\n... [output truncated] ...\n"
+	head := (limit - len(marker)) / 2
+	tail := limit - len(marker) - head
+	return output[:head] + marker + output[len(output)-tail:], true
+}
+
+func GetValidationRuns(submissionID uuid.UUID) ([]models.ValidationRun, error) {
+	var runs []models.ValidationRun
+	err := database.DB.
+		Where("submission_id = ?", submissionID).
+		Preload("Steps", func(db *gorm.DB) *gorm.DB {
+			return db.Omit("output").Order("position ASC")
+		}).
+		Order("run_number DESC").
+		Find(&runs).Error
+	return runs, err
+}
+
+func GetValidationRun(submissionID, runID uuid.UUID) (*models.ValidationRun, error) {
+	var run models.ValidationRun
+	err := database.DB.
+		Where("id = ? AND submission_id = ?", runID, submissionID).
+		Preload("Steps", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		First(&run).Error
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func GetLatestValidationRuns(submissionIDs []uuid.UUID) (map[uuid.UUID]*models.ValidationRun, error) {
+	latest := make(map[uuid.UUID]*models.ValidationRun)
+	if len(submissionIDs) == 0 {
+		return latest, nil
+	}
+
+	var runs []models.ValidationRun
+	err := database.DB.
+		Raw(`SELECT DISTINCT ON (submission_id) * FROM validation_runs
+			WHERE submission_id IN ?
+			ORDER BY submission_id, run_number DESC`, submissionIDs).
+		Scan(&runs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	runIDs := make([]uuid.UUID, 0, len(runs))
+	for i := range runs {
+		latest[runs[i].SubmissionID] = &runs[i]
+		runIDs = append(runIDs, runs[i].ID)
+	}
+	if len(runIDs) == 0 {
+		return latest, nil
+	}
+
+	var steps []models.ValidationStep
+	err = database.DB.Omit("output").
+		Where("run_id IN ?", runIDs).
+		Order("position ASC").
+		Find(&steps).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byRun := make(map[uuid.UUID]*models.ValidationRun, len(runs))
+	for i := range runs {
+		byRun[runs[i].ID] = &runs[i]
+	}
+	for _, step := range steps {
+		run := byRun[step.RunID]
+		run.Steps = append(run.Steps, step)
+	}
+	return latest, nil
+}
+
+func AttachLatestValidationRuns(submissions []models.ProjectVSubmission) error {
+	ids := make([]uuid.UUID, 0, len(submissions))
+	for _, submission := range submissions {
+		ids = append(ids, submission.ID)
+	}
+
+	latest, err := GetLatestValidationRuns(ids)
+	if err != nil {
+		return err
+	}
+	for i := range submissions {
+		if run, ok := latest[submissions[i].ID]; ok {
+			submissions[i].LatestRun = run
+			submissions[i].ValidationStepResults = ValidationStepResultsFor(run)
+		}
+	}
+	return nil
+}
+
+func ValidationStepResultsFor(run *models.ValidationRun) models.ValidationStepResults {
+	var results models.ValidationStepResults
+	if run == nil {
+		return results
+	}
+
+	for _, step := range run.Steps {
+		passed := step.Status == models.ValidationStepPassed
+		switch step.Name {
+		case models.StepClone:
+			results.CloneSuccess, results.CloneError = passed, step.Error
+		case models.StepTestPatch:
+			results.TestPatchSuccess, results.TestPatchError = passed, step.Error
+		case models.StepDockerBuild:
+			results.DockerBuildSuccess, results.DockerBuildError = passed, step.Error
+		case models.StepBaseTests:
+			results.BaseTestSuccess, results.BaseTestError = passed, step.Error
+		case models.StepNewTests:
+			results.NewTestSuccess, results.NewTestError = passed, step.Error
+		case models.StepSolutionPatch:
+			results.SolutionPatchSuccess, results.SolutionPatchError = passed, step.Error
+		case models.StepFinalBaseTests:
+			results.FinalBaseTestSuccess, results.FinalBaseTestError = passed, step.Error
+		case models.StepFinalNewTests:
+			results.FinalNewTestSuccess, results.FinalNewTestError = passed, step.Error
+		}
+	}
+	return results
+}
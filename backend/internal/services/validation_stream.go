@@ -0,0 +1,238 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/adzzatxperts/backend/internal/websocket"
+	"github.com/google/uuid"
+)
+
+const (
+	ValidationLogMessage       = "validation_log"
+	ValidationLogReplayMessage = "validation_log_replay"
+)
+
+const (
+	ValidationEventLine = "line"
+	ValidationEventStep = "step"
+	ValidationEventRun  = "run"
+)
+
+type ValidationLogEvent struct {
+	SubmissionID uuid.UUID `json:"submissionId"`
+	RunID        uuid.UUID `json:"runId"`
+	Seq          int       `json:"seq"`
+	Kind         string    `json:"kind"`
+	Line         string    `json:"line,omitempty"`
+	Step         string    `json:"step,omitempty"`
+	Status       string    `json:"status,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+type validationStream struct {
+	submissionID uuid.UUID
+	runID        uuid.UUID
+	audience     map[uuid.UUID]bool
+
+	mu       sync.Mutex
+	events   []ValidationLogEvent
+	seq      int
+	finished bool
+}
+
+var (
+	validationHub     *websocket.Hub
+	validationStreams = struct {
+		sync.Mutex
+		bySubmission map[uuid.UUID]*validationStream
+	}{bySubmission: make(map[uuid.UUID]*validationStream)}
+)
+
+func SetValidationHub(hub *websocket.Hub) {
+	validationHub = hub
+	if hub != nil {
+		hub.Handle(ValidationLogReplayMessage, replayValidationLog)
+	}
+}
+
+func openValidationStream(submission *models.ProjectVSubmission, runID uuid.UUID) *validationStream {
+	stream := &validationStream{
+		submissionID: submission.ID,
+		runID:        runID,
+		audience:     map[uuid.UUID]bool{submission.ContributorID: true},
+	}
+	if submission.TesterID != nil {
+		stream.audience[*submission.TesterID] = true
+	}
+
+	validationStreams.Lock()
+	validationStreams.bySubmission[submission.ID] = stream
+	validationStreams.Unlock()
+
+	stream.publish(ValidationLogEvent{Kind: ValidationEventRun, Status: string(models.ValidationRunRunning)})
+	return stream
+}
+
+func (s *validationStream) line(line string) {
+	s.publish(ValidationLogEvent{Kind: ValidationEventLine, Line: line})
+}
+
+func (s *validationStream) step(name string, status models.ValidationStepStatus) {
+	s.publish(ValidationLogEvent{Kind: ValidationEventStep, Step: name, Status: string(status)})
+}
+
+func (s *validationStream) finish(status models.ValidationRunStatus) {
+	s.publish(ValidationLogEvent{Kind: ValidationEventRun, Status: string(status)})
+
+	s.mu.Lock()
+	s.finished = true
+	s.mu.Unlock()
+
+	time.AfterFunc(envDuration("VALIDATION_LOG_RETENTION", 15*time.Minute), func() {
+		validationStreams.Lock()
+		if validationStreams.bySubmission[s.submissionID] == s {
+			delete(validationStreams.bySubmission, s.submissionID)
+		}
+		validationStreams.Unlock()
+	})
+}
+
+func (s *validationStream) publish(event ValidationLogEvent) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.seq++
+	event.SubmissionID = s.submissionID
+	event.RunID = s.runID
+	event.Seq = s.seq
+	event.Timestamp = time.Now()
+
+	s.events = append(s.events, event)
+	if limit := envInt("VALIDATION_LOG_BUFFER", 5000); len(s.events) > limit {
+		s.events = append([]ValidationLogEvent(nil), s.events[len(s.events)-limit:]...)
+	}
+	s.mu.Unlock()
+
+	if validationHub != nil {
+		validationHub.BroadcastToMatching(ValidationLogMessage, event, s.canView)
+	}
+}
+
+func (s *validationStream) canView(client *websocket.Client) bool {
+	return client.Role == string(models.RoleAdmin) || s.audience[client.UserID]
+}
+
+func replayValidationLog(client *websocket.Client, data json.RawMessage) {
+	var request struct {
+		SubmissionID uuid.UUID `json:"submissionId"`
+		AfterSeq     int       `json:"afterSeq"`
+	}
+	if err := json.Unmarshal(data, &request); err != nil {
+		return
+	}
+
+	validationStreams.Lock()
+	stream := validationStreams.bySubmission[request.SubmissionID]
+	validationStreams.Unlock()
+
+	if stream == nil || !stream.canView(client) {
+		validationHub.SendToClient(client, ValidationLogReplayMessage, map[string]interface{}{
+			"submissionId": request.SubmissionID,
+			"events":       []ValidationLogEvent{},
+			"active":       false,
+		})
+		return
+	}
+
+	stream.mu.Lock()
+	events := make([]ValidationLogEvent, 0, len(stream.events))
+	for _, event := range stream.events {
+		if event.Seq > request.AfterSeq {
+			events = append(events, event)
+		}
+	}
+	active := !stream.finished
+	stream.mu.Unlock()
+
+	validationHub.SendToClient(client, ValidationLogReplayMessage, map[string]interface{}{
+		"submissionId": request.SubmissionID,
+		"runId":        stream.runID,
+		"events":       events,
+		"active":       active,
+	})
+}
+
+type validationLog struct {
+	mu     sync.Mutex
+	lines  []string
+	stream *validationStream
+}
+
+func (l *validationLog) Add(line string) {
+	l.mu.Lock()
+	l.lines = append(l.lines, line)
+	stream := l.stream
+	l.mu.Unlock()
+
+	for _, part := range strings.Split(line, "\n") {
+		stream.line(part)
+	}
+}
+
+func (l *validationLog) Record(line string) {
+	l.mu.Lock()
+	l.lines = append(l.lines, line)
+	l.mu.Unlock()
+}
+
+func (l *validationLog) LiveWriter() *liveLogWriter {
+	return &liveLogWriter{log: l}
+}
+
+func (l *validationLog) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.lines)
+}
+
+func (l *validationLog) Since(start int) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return strings.Join(l.lines[start:], "\n")
+}
+
+func (l *validationLog) String() string {
+	return l.Since(0)
+}
+
+type liveLogWriter struct {
+	log     *validationLog
+	pending []byte
+}
+
+func (w *liveLogWriter) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	for {
+		i := bytes.IndexByte(w.pending, '\n')
+		if i < 0 {
+			break
+		}
+		w.log.stream.line("  " + string(bytes.TrimRight(w.pending[:i], "\r")))
+		w.pending = w.pending[i+1:]
+	}
+	return len(p), nil
+}
+
+func (w *liveLogWriter) Flush() {
+	if len(w.pending) > 0 {
+		w.log.stream.line("  " + string(w.pending))
+		w.pending = nil
+	}
+}
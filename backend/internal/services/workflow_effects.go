@@ -0,0 +1,44 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/adzzatxperts/backend/internal/workflow"
+	"gorm.io/gorm"
+)
+
+var ErrNoAssigneeAvailable = errors.New("no approved assignee is available")
+
+func RegisterWorkflowEffects() {
+	workflow.RegisterEffect(workflow.EffectAssignTester, func(tx *gorm.DB, submission *models.ProjectVSubmission, actor workflow.Actor) error {
+		if submission.TesterID != nil {
+			return nil
+		}
+		testerID, err := AutoAssignTester(tx, submission.ID)
+		if err != nil {
+			return err
+		}
+		if testerID == nil {
+			return ErrNoAssigneeAvailable
+		}
+		submission.TesterID = testerID
+		return nil
+	})
+
+	workflow.RegisterEffect(workflow.EffectAssignReviewer, func(tx *gorm.DB, submission *models.ProjectVSubmission, actor workflow.Actor) error {
+		if submission.ReviewerID != nil {
+			return nil
+		}
+		reviewerID, err := AutoAssignReviewer(tx, submission.ID)
+		if err != nil {
+			return err
+		}
+		if reviewerID != nil {
+			submission.ReviewerID = reviewerID
+		}
+		return nil
+	})
+
+	workflow.RegisterObserver(emailWorkflowEvent)
+}
@@ -0,0 +1,279 @@
+package testreport
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	FormatJUnit = "junit"
+	FormatTAP   = "tap"
+)
+
+const (
+	StatusPassed  = "PASSED"
+	StatusFailed  = "FAILED"
+	StatusSkipped = "SKIPPED"
+)
+
+type Case struct {
+	Name      string `json:"name"`
+	Classname string `json:"classname,omitempty"`
+	Status    string `json:"status"`
+	Message   string `json:"message,omitempty"`
+}
+
+func (c Case) ID() string {
+	if c.Classname == "" {
+		return c.Name
+	}
+	return c.Classname + "::" + c.Name
+}
+
+type Summary struct {
+	Format  string `json:"format"`
+	Total   int    `json:"total"`
+	Passed  int    `json:"passed"`
+	Failed  int    `json:"failed"`
+	Skipped int    `json:"skipped"`
+	Cases   []Case `json:"cases,omitempty"`
+}
+
+func Parse(output string) *Summary {
+	if summary := ParseJUnit(output); summary != nil {
+		return summary
+	}
+	return ParseTAP(output)
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure"`
+	Error     *junitFailure `xml:"error"`
+	Skipped   *junitFailure `xml:"skipped"`
+}
+
+type junitSuite struct {
+	Cases  []junitCase  `xml:"testcase"`
+	Suites []junitSuite `xml:"testsuite"`
+}
+
+var junitStart = regexp.MustCompile(`<testsuites?[\s>]`)
+
+func ParseJUnit(output string) *Summary {
+	loc := junitStart.FindStringIndex(output)
+	if loc == nil {
+		return nil
+	}
+	document := output[loc[0]:]
+
+	end := strings.LastIndex(document, "</testsuites>")
+	if end >= 0 {
+		document = document[:end+len("</testsuites>")]
+	} else if end = strings.LastIndex(document, "</testsuite>"); end >= 0 {
+		document = document[:end+len("</testsuite>")]
+	} else {
+		return nil
+	}
+
+	var root junitSuite
+	if err := xml.Unmarshal([]byte(document), &root); err != nil {
+		return nil
+	}
+
+	summary := &Summary{Format: FormatJUnit}
+	collectJUnit(summary, root)
+	if summary.Total == 0 {
+		return nil
+	}
+	return summary
+}
+
+func collectJUnit(summary *Summary, suite junitSuite) {
+	for _, tc := range suite.Cases {
+		c := Case{Name: tc.Name, Classname: tc.Classname, Status: StatusPassed}
+		switch {
+		case tc.Failure != nil:
+			c.Status = StatusFailed
+			c.Message = firstNonEmpty(tc.Failure.Message, tc.Failure.Body)
+		case tc.Error != nil:
+			c.Status = StatusFailed
+			c.Message = firstNonEmpty(tc.Error.Message, tc.Error.Body)
+		case tc.Skipped != nil:
+			c.Status = StatusSkipped
+			c.Message = firstNonEmpty(tc.Skipped.Message, tc.Skipped.Body)
+		}
+		summary.add(c)
+	}
+	for _, child := range suite.Suites {
+		collectJUnit(summary, child)
+	}
+}
+
+var (
+	tapPlan = regexp.MustCompile(`^1\.\.(\d+)`)
+	tapLine = regexp.MustCompile(`^(not ok|ok)\b\s*(\d+)?\s*(?:-\s*)?([^#]*?)\s*(?:#\s*(.*))?$`)
+)
+
+func ParseTAP(output string) *Summary {
+	summary := &Summary{Format: FormatTAP}
+	planned := -1
+
+	for _, raw := range strings.Split(output, "\n") {
+		line := strings.TrimSpace(raw)
+		if match := tapPlan.FindStringSubmatch(line); match != nil {
+			planned, _ = strconv.Atoi(match[1])
+			continue
+		}
+
+		match := tapLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		c := Case{Name: strings.TrimSpace(match[3]), Status: StatusPassed}
+		if c.Name == "" {
+			c.Name = "test " + match[2]
+		}
+		directive := strings.ToUpper(match[4])
+		switch {
+		case strings.HasPrefix(directive, "SKIP"), strings.HasPrefix(directive, "TODO"):
+			c.Status = StatusSkipped
+			c.Message = strings.TrimSpace(match[4])
+		case match[1] == "not ok":
+			c.Status = StatusFailed
+		}
+		summary.add(c)
+	}
+
+	if summary.Total == 0 {
+		return nil
+	}
+
+	if planned > summary.Total {
+		missing := planned - summary.Total
+		for i := 0; i < missing; i++ {
+			summary.add(Case{
+				Name:    "test " + strconv.Itoa(summary.Total+1),
+				Status:  StatusFailed,
+				Message: "planned test did not report a result",
+			})
+		}
+	}
+
+	return summary
+}
+
+func (s *Summary) add(c Case) {
+	s.Cases = append(s.Cases, c)
+	s.Total++
+	switch c.Status {
+	case StatusPassed:
+		s.Passed++
+	case StatusFailed:
+		s.Failed++
+	case StatusSkipped:
+		s.Skipped++
+	}
+}
+
+func (s *Summary) String() string {
+	return strconv.Itoa(s.Passed) + " passed, " + strconv.Itoa(s.Failed) + " failed, " +
+		strconv.Itoa(s.Skipped) + " skipped (" + s.Format + ")"
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+type Transitions struct {
+	FailToPass []string `json:"failToPass"`
+	PassToPass []string `json:"passToPass"`
+	PassToFail []string `json:"passToFail"`
+}
+
+func Merge(summaries ...*Summary) map[string]string {
+	results := make(map[string]string)
+	for _, summary := range summaries {
+		if summary == nil {
+			continue
+		}
+		for _, c := range summary.Cases {
+			if results[c.ID()] == StatusFailed {
+				continue
+			}
+			results[c.ID()] = c.Status
+		}
+	}
+	return results
+}
+
+func Compare(before, after map[string]string) Transitions {
+	transitions := Transitions{
+		FailToPass: []string{},
+		PassToPass: []string{},
+		PassToFail: []string{},
+	}
+
+	for id, status := range after {
+		previous, seen := before[id]
+		switch {
+		case status == StatusPassed && (!seen || previous == StatusFailed):
+			transitions.FailToPass = append(transitions.FailToPass, id)
+		case status == StatusPassed && previous == StatusPassed:
+			transitions.PassToPass = append(transitions.PassToPass, id)
+		case status == StatusFailed && previous == StatusPassed:
+			transitions.PassToFail = append(transitions.PassToFail, id)
+		}
+	}
+	for id, previous := range before {
+		if _, ok := after[id]; !ok && previous == StatusPassed {
+			transitions.PassToFail = append(transitions.PassToFail, id)
+		}
+	}
+
+	sort.Strings(transitions.FailToPass)
+	sort.Strings(transitions.PassToPass)
+	sort.Strings(transitions.PassToFail)
+	return transitions
+}
+
+func ParseJUnitFiles(dir string) *Summary {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.xml"))
+	sort.Strings(paths)
+
+	var merged *Summary
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		summary := ParseJUnit(string(data))
+		if summary == nil {
+			continue
+		}
+		if merged == nil {
+			merged = &Summary{Format: FormatJUnit}
+		}
+		for _, c := range summary.Cases {
+			merged.add(c)
+		}
+	}
+	return merged
+}
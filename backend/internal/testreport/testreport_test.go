@@ -0,0 +1,247 @@
+package testreport
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseJUnit(t *testing.T) {
+	cases := []struct {
+		name   string
+		output string
+		want   []Case
+	}{
+		{
+			name: "nested testsuites with surrounding output",
+			output: `Running tests...
+<?xml version="1.0"?>
+<testsuites>
+  <testsuite name="outer">
+    <testcase classname="pkg.A" name="passes"/>
+    <testsuite name="inner">
+      <testcase classname="pkg.B" name="fails"><failure message="expected 1">trace</failure></testcase>
+    </testsuite>
+  </testsuite>
+</testsuites>
+done`,
+			want: []Case{
+				{Name: "passes", Classname: "pkg.A", Status: StatusPassed},
+				{Name: "fails", Classname: "pkg.B", Status: StatusFailed, Message: "expected 1"},
+			},
+		},
+		{
+			name: "single testsuite with error and skipped cases",
+			output: `<testsuite name="suite">
+  <testcase name="errors"><error>panic: boom</error></testcase>
+  <testcase name="skipped"><skipped message="needs network"/></testcase>
+  <testcase name="skipped without message"><skipped/></testcase>
+</testsuite>`,
+			want: []Case{
+				{Name: "errors", Status: StatusFailed, Message: "panic: boom"},
+				{Name: "skipped", Status: StatusSkipped, Message: "needs network"},
+				{Name: "skipped without message", Status: StatusSkipped},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			summary := ParseJUnit(tc.output)
+			if summary == nil {
+				t.Fatal("ParseJUnit returned nil")
+			}
+			if summary.Format != FormatJUnit {
+				t.Errorf("format = %q", summary.Format)
+			}
+			if !reflect.DeepEqual(summary.Cases, tc.want) {
+				t.Errorf("cases = %+v, want %+v", summary.Cases, tc.want)
+			}
+			checkCounts(t, summary)
+		})
+	}
+
+	for _, output := range []string{"", "no xml here", "<testsuite><testcase name=", "<testsuites></testsuites>"} {
+		if summary := ParseJUnit(output); summary != nil {
+			t.Errorf("ParseJUnit(%q) = %v, want nil", output, summary)
+		}
+	}
+}
+
+func TestParseTAP(t *testing.T) {
+	cases := []struct {
+		name   string
+		output string
+		want   []Case
+	}{
+		{
+			name:   "results with descriptions",
+			output: "TAP version 13\n1..3\nok 1 - adds numbers\nnot ok 2 - divides by zero\nok 3\n",
+			want: []Case{
+				{Name: "adds numbers", Status: StatusPassed},
+				{Name: "divides by zero", Status: StatusFailed},
+				{Name: "test 3", Status: StatusPassed},
+			},
+		},
+		{
+			name:   "skip and todo directives",
+			output: "1..3\nok 1 - slow path # SKIP no database\nnot ok 2 - new parser # TODO not implemented\nok 3 - plain # skip lowercase\n",
+			want: []Case{
+				{Name: "slow path", Status: StatusSkipped, Message: "SKIP no database"},
+				{Name: "new parser", Status: StatusSkipped, Message: "TODO not implemented"},
+				{Name: "plain", Status: StatusSkipped, Message: "skip lowercase"},
+			},
+		},
+		{
+			name:   "plan with missing results",
+			output: "1..4\nok 1 - first\nok 2 - second\n",
+			want: []Case{
+				{Name: "first", Status: StatusPassed},
+				{Name: "second", Status: StatusPassed},
+				{Name: "test 3", Status: StatusFailed, Message: "planned test did not report a result"},
+				{Name: "test 4", Status: StatusFailed, Message: "planned test did not report a result"},
+			},
+		},
+		{
+			name:   "plan at the end",
+			output: "ok 1 - first\nnot ok 2 - second\n1..2\n",
+			want: []Case{
+				{Name: "first", Status: StatusPassed},
+				{Name: "second", Status: StatusFailed},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			summary := ParseTAP(tc.output)
+			if summary == nil {
+				t.Fatal("ParseTAP returned nil")
+			}
+			if !reflect.DeepEqual(summary.Cases, tc.want) {
+				t.Errorf("cases = %+v, want %+v", summary.Cases, tc.want)
+			}
+			checkCounts(t, summary)
+		})
+	}
+
+	if summary := ParseTAP("1..3\nno results at all\n"); summary != nil {
+		t.Errorf("ParseTAP without results = %v, want nil", summary)
+	}
+}
+
+func TestParsePrefersJUnit(t *testing.T) {
+	output := "ok 1 - tap line\n<testsuite><testcase name=\"junit case\"/></testsuite>"
+	summary := Parse(output)
+	if summary == nil || summary.Format != FormatJUnit {
+		t.Fatalf("Parse = %v, want a JUnit summary", summary)
+	}
+	if summary := Parse("1..1\nok 1 - only tap\n"); summary == nil || summary.Format != FormatTAP {
+		t.Fatalf("Parse = %v, want a TAP summary", summary)
+	}
+}
+
+func TestParseJUnitFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.xml":      `<testsuite><testcase name="one"/></testsuite>`,
+		"b.xml":      `<testsuite><testcase name="two"><failure/></testcase></testsuite>`,
+		"broken.xml": `<testsuite>`,
+		"notes.txt":  `<testsuite><testcase name="ignored"/></testsuite>`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	summary := ParseJUnitFiles(dir)
+	if summary == nil {
+		t.Fatal("ParseJUnitFiles returned nil")
+	}
+	if summary.Total != 2 || summary.Passed != 1 || summary.Failed != 1 {
+		t.Fatalf("summary = %s, want 1 passed and 1 failed", summary)
+	}
+	if summary := ParseJUnitFiles(t.TempDir()); summary != nil {
+		t.Fatalf("empty directory = %v, want nil", summary)
+	}
+}
+
+func TestMergeKeepsFailures(t *testing.T) {
+	first := &Summary{Cases: []Case{{Name: "a", Status: StatusFailed}, {Name: "b", Status: StatusPassed}}}
+	second := &Summary{Cases: []Case{{Name: "a", Status: StatusPassed}, {Name: "b", Status: StatusSkipped}, {Name: "c", Classname: "pkg", Status: StatusPassed}}}
+
+	got := Merge(first, nil, second)
+	want := map[string]string{"a": StatusFailed, "b": StatusSkipped, "pkg::c": StatusPassed}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Merge = %v, want %v", got, want)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	cases := []struct {
+		name   string
+		before map[string]string
+		after  map[string]string
+		want   Transitions
+	}{
+		{
+			name:   "fixed, kept and broken",
+			before: map[string]string{"fixed": StatusFailed, "kept": StatusPassed, "broken": StatusPassed},
+			after:  map[string]string{"fixed": StatusPassed, "kept": StatusPassed, "broken": StatusFailed},
+			want:   Transitions{FailToPass: []string{"fixed"}, PassToPass: []string{"kept"}, PassToFail: []string{"broken"}},
+		},
+		{
+			name:   "new test missing from before counts as fail to pass",
+			before: map[string]string{},
+			after:  map[string]string{"new": StatusPassed, "new failing": StatusFailed},
+			want:   Transitions{FailToPass: []string{"new"}, PassToPass: []string{}, PassToFail: []string{}},
+		},
+		{
+			name:   "passing test missing from after counts as pass to fail",
+			before: map[string]string{"removed": StatusPassed, "removed failing": StatusFailed},
+			after:  map[string]string{},
+			want:   Transitions{FailToPass: []string{}, PassToPass: []string{}, PassToFail: []string{"removed"}},
+		},
+		{
+			name:   "skipped tests are ignored",
+			before: map[string]string{"skip before": StatusSkipped, "skip after": StatusPassed},
+			after:  map[string]string{"skip before": StatusPassed, "skip after": StatusSkipped},
+			want:   Transitions{FailToPass: []string{}, PassToPass: []string{}, PassToFail: []string{}},
+		},
+		{
+			name:   "results are sorted",
+			before: map[string]string{"b": StatusFailed, "a": StatusFailed},
+			after:  map[string]string{"b": StatusPassed, "a": StatusPassed, "c": StatusPassed},
+			want:   Transitions{FailToPass: []string{"a", "b", "c"}, PassToPass: []string{}, PassToFail: []string{}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Compare(tc.before, tc.after); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("Compare = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func checkCounts(t *testing.T, summary *Summary) {
+	t.Helper()
+
+	var passed, failed, skipped int
+	for _, c := range summary.Cases {
+		switch c.Status {
+		case StatusPassed:
+			passed++
+		case StatusFailed:
+			failed++
+		case StatusSkipped:
+			skipped++
+		}
+	}
+	if summary.Total != len(summary.Cases) || summary.Passed != passed || summary.Failed != failed || summary.Skipped != skipped {
+		t.Errorf("counts %s (total %d) do not match %d cases", summary, summary.Total, len(summary.Cases))
+	}
+}
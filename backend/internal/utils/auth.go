@@ -2,12 +2,15 @@ package utils
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -16,9 +19,12 @@ func getJWTSecret() []byte {
 }
 
 type Claims struct {
-	UserID string `json:"userId"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID       string `json:"userId"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+	TokenVersion int    `json:"ver"`
+	SessionID    string `json:"sid,omitempty"`
+	Purpose      string `json:"purpose,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -32,18 +38,39 @@ func CheckPassword(password, hash string) bool {
 	return err == nil
 }
 
-func GenerateJWT(userID, email, role string) (string, error) {
+func ValidateJWT(tokenString string) (*Claims, error) {
+	jwtSecret := getJWTSecret()
+	if len(jwtSecret) == 0 {
+		return nil, errors.New("JWT_SECRET not set")
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		return jwtSecret, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*Claims); ok && token.Valid && claims.Purpose == "" {
+		return claims, nil
+	}
+
+	return nil, errors.New("invalid token")
+}
+
+func GenerateChallengeToken(userID, purpose string, ttl time.Duration) (string, error) {
 	jwtSecret := getJWTSecret()
 	if len(jwtSecret) == 0 {
 		return "", errors.New("JWT_SECRET not set")
 	}
 
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:  userID,
+		Purpose: purpose,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(7 * 24 * time.Hour)),
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 		},
@@ -53,7 +80,7 @@ func GenerateJWT(userID, email, role string) (string, error) {
 	return token.SignedString(jwtSecret)
 }
 
-func ValidateJWT(tokenString string) (*Claims, error) {
+func ValidateChallengeToken(tokenString, purpose string) (*Claims, error) {
 	jwtSecret := getJWTSecret()
 	if len(jwtSecret) == 0 {
 		return nil, errors.New("JWT_SECRET not set")
@@ -62,16 +89,15 @@ func ValidateJWT(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		return jwtSecret, nil
 	})
-
 	if err != nil {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+	if claims, ok := token.Claims.(*Claims); ok && token.Valid && claims.Purpose == purpose {
 		return claims, nil
 	}
 
-	return nil, errors.New("invalid token")
+	return nil, errors.New("invalid challenge token")
 }
 
 func GenerateRefreshToken() (string, error) {
@@ -84,18 +110,21 @@ func GenerateRefreshToken() (string, error) {
 	return base64.URLEncoding.EncodeToString(bytes), nil
 }
 
-func GenerateShortLivedJWT(userID, email, role string) (string, error) {
+func GenerateShortLivedJWT(userID, email, role string, version int, sessionID string) (string, error) {
 	jwtSecret := getJWTSecret()
 	if len(jwtSecret) == 0 {
 		return "", errors.New("JWT_SECRET not set")
 	}
 
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:       userID,
+		Email:        email,
+		Role:         role,
+		TokenVersion: version,
+		SessionID:    sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(15 * time.Minute)),
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(AccessTokenTTL())),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 		},
@@ -106,5 +135,21 @@ func GenerateShortLivedJWT(userID, email, role string) (string, error) {
 }
 
 func GetRefreshTokenExpiry() time.Time {
-	return time.Now().Add(30 * 24 * time.Hour)
+	return time.Now().Add(durationFromEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour))
+}
+
+func AccessTokenTTL() time.Duration {
+	return durationFromEnv("ACCESS_TOKEN_TTL", 15*time.Minute)
+}
+
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	if parsed, err := time.ParseDuration(os.Getenv(key)); err == nil && parsed > 0 {
+		return parsed
+	}
+	return fallback
 }
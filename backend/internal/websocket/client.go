@@ -53,7 +53,7 @@ func (c *Client) readPump() {
 			break
 		}
 
-		log.Printf("Received message from client %s: %s", c.ID, message)
+		c.Hub.dispatch(c, message)
 	}
 }
 
@@ -14,9 +14,12 @@ type Message struct {
 	Data interface{} `json:"data"`
 }
 
+type MessageHandler func(client *Client, data json.RawMessage)
+
 type Client struct {
 	ID     uuid.UUID
 	UserID uuid.UUID
+	Role   string
 	Send   chan []byte
 	Hub    *Hub
 	conn   *websocket.Conn
@@ -31,6 +34,8 @@ type Hub struct {
 
 	unregister chan *Client
 
+	handlers map[string]MessageHandler
+
 	mu sync.RWMutex
 }
 
@@ -40,6 +45,7 @@ func NewHub() *Hub {
 		broadcast:  make(chan []byte, 256),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
+		handlers:   make(map[string]MessageHandler),
 	}
 }
 
@@ -119,6 +125,86 @@ func (h *Hub) BroadcastToUser(userID uuid.UUID, messageType string, data interfa
 	return nil
 }
 
+func (h *Hub) BroadcastToMatching(messageType string, data interface{}, match func(client *Client) bool) error {
+	msg := Message{
+		Type: messageType,
+		Data: data,
+	}
+
+	jsonMsg, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for client := range h.clients {
+		if match(client) {
+			select {
+			case client.Send <- jsonMsg:
+			default:
+
+			}
+		}
+	}
+
+	return nil
+}
+
+func (h *Hub) SendToClient(client *Client, messageType string, data interface{}) error {
+	msg := Message{
+		Type: messageType,
+		Data: data,
+	}
+
+	jsonMsg, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if _, ok := h.clients[client]; ok {
+		select {
+		case client.Send <- jsonMsg:
+		default:
+
+		}
+	}
+
+	return nil
+}
+
+func (h *Hub) Handle(messageType string, handler MessageHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[messageType] = handler
+}
+
+func (h *Hub) dispatch(client *Client, raw []byte) {
+	var msg struct {
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		log.Printf("Invalid message from client %s: %v", client.ID, err)
+		return
+	}
+
+	h.mu.RLock()
+	handler, ok := h.handlers[msg.Type]
+	h.mu.RUnlock()
+
+	if !ok {
+		log.Printf("Received message from client %s: %s", client.ID, raw)
+		return
+	}
+
+	handler(client, msg.Data)
+}
+
 func (h *Hub) GetConnectedUsers() int {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
@@ -0,0 +1,382 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+)
+
+const (
+	ActionStartTesting     = "START_TESTING"
+	ActionSendFeedback     = "SEND_TESTER_FEEDBACK"
+	ActionResubmit         = "RESUBMIT"
+	ActionResumeTesting    = "RESUME_TESTING"
+	ActionSubmitToPlatform = "MARK_TASK_SUBMITTED"
+	ActionMarkEligible     = "MARK_ELIGIBLE"
+	ActionRequestReview    = "REQUEST_REVIEW"
+	ActionRequestChanges   = "REQUEST_CHANGES"
+	ActionChangesDone      = "MARK_CHANGES_DONE"
+	ActionConfirmChanges   = "CONFIRM_CHANGES"
+	ActionFinalChecks      = "MARK_FINAL_CHECKS"
+	ActionReject           = "REJECT"
+	ActionApprove          = "APPROVE"
+)
+
+const (
+	GuardContributorOwns    = "contributor_owns_submission"
+	GuardFeedbackProvided   = "feedback_provided"
+	GuardPlatformSubmission = "platform_submission_provided"
+	GuardTaskLinkProvided   = "task_link_provided"
+	GuardChangesRequested   = "changes_were_requested"
+	GuardRejectionReason    = "rejection_reason_provided"
+	GuardAccountPostedIn    = "account_posted_in_provided"
+	GuardCommentsResolved   = "blocking_comments_resolved"
+	GuardRubricScored       = "rubric_scored"
+)
+
+const (
+	EffectClaimTester    = "claim_tester"
+	EffectClaimReviewer  = "claim_reviewer"
+	EffectAssignTester   = "assign_tester"
+	EffectAssignReviewer = "assign_reviewer"
+)
+
+const (
+	roleAdmin       = string(models.RoleAdmin)
+	roleTester      = string(models.RoleTester)
+	roleReviewer    = string(models.RoleReviewer)
+	roleContributor = string(models.RoleContributor)
+)
+
+var Statuses = []models.ProjectVStatus{
+	models.ProjectVStatusSubmitted,
+	models.ProjectVStatusInTesting,
+	models.ProjectVStatusRework,
+	models.ProjectVStatusReworkDone,
+	models.ProjectVStatusTaskSubmittedToPlatform,
+	models.ProjectVStatusEligible,
+	models.ProjectVStatusPendingReview,
+	models.ProjectVStatusChangesRequested,
+	models.ProjectVStatusChangesDone,
+	models.ProjectVStatusFinalChecks,
+	models.ProjectVStatusApproved,
+	models.ProjectVStatusRejected,
+}
+
+var transitions = []Transition{
+	{
+		Action:  ActionStartTesting,
+		Label:   "start testing",
+		From:    []models.ProjectVStatus{models.ProjectVStatusSubmitted},
+		To:      models.ProjectVStatusInTesting,
+		Roles:   []string{RoleSystem, roleTester, roleAdmin},
+		Effects: []string{EffectClaimTester, EffectAssignTester},
+	},
+	{
+		Action: ActionSendFeedback,
+		Label:  "send feedback",
+		From: []models.ProjectVStatus{
+			models.ProjectVStatusSubmitted,
+			models.ProjectVStatusInTesting,
+			models.ProjectVStatusReworkDone,
+			models.ProjectVStatusTaskSubmittedToPlatform,
+			models.ProjectVStatusEligible,
+		},
+		To:      models.ProjectVStatusRework,
+		Roles:   []string{RoleSystem, roleTester, roleAdmin},
+		Guards:  []string{GuardFeedbackProvided},
+		Effects: []string{EffectClaimTester},
+		apply: func(submission *models.ProjectVSubmission, params Params) {
+			submission.TesterFeedback = params.Feedback
+		},
+	},
+	{
+		Action: ActionResubmit,
+		Label:  "resubmit the task",
+		From:   []models.ProjectVStatus{models.ProjectVStatusRework},
+		To:     models.ProjectVStatusReworkDone,
+		Roles:  []string{roleContributor, roleAdmin},
+		Guards: []string{GuardContributorOwns, GuardCommentsResolved},
+	},
+	{
+		Action:  ActionResumeTesting,
+		Label:   "resume testing",
+		From:    []models.ProjectVStatus{models.ProjectVStatusReworkDone},
+		To:      models.ProjectVStatusInTesting,
+		Roles:   []string{roleTester, roleAdmin},
+		Effects: []string{EffectClaimTester},
+	},
+	{
+		Action: ActionSubmitToPlatform,
+		Label:  "mark the task as submitted",
+		From: []models.ProjectVStatus{
+			models.ProjectVStatusSubmitted,
+			models.ProjectVStatusInTesting,
+			models.ProjectVStatusReworkDone,
+		},
+		To:      models.ProjectVStatusTaskSubmittedToPlatform,
+		Roles:   []string{roleTester, roleAdmin},
+		Guards:  []string{GuardPlatformSubmission},
+		Effects: []string{EffectClaimTester},
+		apply: func(submission *models.ProjectVSubmission, params Params) {
+			account, link := params.SubmittedAccount, params.TaskLinkSubmitted
+			submission.SubmittedAccount = &account
+			submission.TaskLinkSubmitted = &link
+		},
+	},
+	{
+		Action: ActionMarkEligible,
+		Label:  "mark the task as eligible for manual review",
+		From: []models.ProjectVStatus{
+			models.ProjectVStatusInTesting,
+			models.ProjectVStatusReworkDone,
+			models.ProjectVStatusTaskSubmittedToPlatform,
+		},
+		To:      models.ProjectVStatusEligible,
+		Roles:   []string{roleTester, roleAdmin},
+		Guards:  []string{GuardTaskLinkProvided},
+		Effects: []string{EffectClaimTester, EffectAssignReviewer},
+		apply: func(submission *models.ProjectVSubmission, params Params) {
+			link := params.TaskLink
+			submission.TaskLink = &link
+		},
+	},
+	{
+		Action: ActionRequestReview,
+		Label:  "send the task for review",
+		From: []models.ProjectVStatus{
+			models.ProjectVStatusInTesting,
+			models.ProjectVStatusReworkDone,
+			models.ProjectVStatusTaskSubmittedToPlatform,
+			models.ProjectVStatusEligible,
+		},
+		To:      models.ProjectVStatusPendingReview,
+		Roles:   []string{roleTester, roleAdmin},
+		Effects: []string{EffectClaimTester, EffectAssignReviewer},
+	},
+	{
+		Action: ActionRequestChanges,
+		Label:  "request changes",
+		From: []models.ProjectVStatus{
+			models.ProjectVStatusEligible,
+			models.ProjectVStatusPendingReview,
+			models.ProjectVStatusChangesDone,
+		},
+		To:      models.ProjectVStatusChangesRequested,
+		Roles:   []string{roleReviewer, roleAdmin},
+		Guards:  []string{GuardFeedbackProvided, GuardRubricScored},
+		Effects: []string{EffectClaimReviewer},
+		apply: func(submission *models.ProjectVSubmission, params Params) {
+			submission.ReviewerFeedback = params.Feedback
+			submission.HasChangesRequested = true
+			submission.ChangesDone = false
+		},
+	},
+	{
+		Action: ActionChangesDone,
+		Label:  "mark changes as done",
+		From:   []models.ProjectVStatus{models.ProjectVStatusChangesRequested},
+		To:     models.ProjectVStatusInTesting,
+		Roles:  []string{roleContributor, roleAdmin},
+		Guards: []string{GuardContributorOwns, GuardCommentsResolved},
+		apply: func(submission *models.ProjectVSubmission, params Params) {
+			submission.ChangesDone = true
+		},
+	},
+	{
+		Action:  ActionConfirmChanges,
+		Label:   "confirm requested changes",
+		From:    []models.ProjectVStatus{models.ProjectVStatusInTesting},
+		To:      models.ProjectVStatusChangesDone,
+		Roles:   []string{roleTester, roleAdmin},
+		Guards:  []string{GuardChangesRequested},
+		Effects: []string{EffectClaimTester},
+	},
+	{
+		Action: ActionFinalChecks,
+		Label:  "mark the task for final checks",
+		From: []models.ProjectVStatus{
+			models.ProjectVStatusEligible,
+			models.ProjectVStatusPendingReview,
+			models.ProjectVStatusChangesDone,
+		},
+		To:      models.ProjectVStatusFinalChecks,
+		Roles:   []string{roleReviewer, roleAdmin},
+		Guards:  []string{GuardRubricScored},
+		Effects: []string{EffectClaimReviewer},
+	},
+	{
+		Action: ActionReject,
+		Label:  "reject the task",
+		From: []models.ProjectVStatus{
+			models.ProjectVStatusEligible,
+			models.ProjectVStatusPendingReview,
+			models.ProjectVStatusChangesDone,
+		},
+		To:      models.ProjectVStatusRejected,
+		Roles:   []string{roleReviewer, roleAdmin},
+		Guards:  []string{GuardRejectionReason, GuardRubricScored},
+		Effects: []string{EffectClaimReviewer},
+		apply: func(submission *models.ProjectVSubmission, params Params) {
+			reason := params.RejectionReason
+			submission.RejectionReason = &reason
+		},
+	},
+	{
+		Action: ActionApprove,
+		Label:  "approve the task",
+		From: []models.ProjectVStatus{
+			models.ProjectVStatusEligible,
+			models.ProjectVStatusFinalChecks,
+		},
+		To:     models.ProjectVStatusApproved,
+		Roles:  []string{roleAdmin},
+		Guards: []string{GuardAccountPostedIn},
+		apply: func(submission *models.ProjectVSubmission, params Params) {
+			account := params.AccountPostedIn
+			submission.AccountPostedIn = &account
+		},
+	},
+}
+
+var guards = map[string]Guard{
+	GuardContributorOwns: {
+		Description: "Contributors may only act on their own submissions",
+		check: func(submission *models.ProjectVSubmission, actor Actor, params Params) error {
+			if actor.Role == roleContributor && submission.ContributorID != actor.ID {
+				return &Error{Kind: ErrForbidden, Message: "You don't have permission to update this submission"}
+			}
+			return nil
+		},
+	},
+	GuardFeedbackProvided: {
+		Description: "Feedback text is required",
+		check:       requireParam("Feedback is required", func(p Params) string { return p.Feedback }),
+	},
+	GuardPlatformSubmission: {
+		Description: "Submitted account and task link are required",
+		check: func(submission *models.ProjectVSubmission, actor Actor, params Params) error {
+			if strings.TrimSpace(params.SubmittedAccount) == "" || strings.TrimSpace(params.TaskLinkSubmitted) == "" {
+				return &Error{Kind: ErrGuardFailed, Message: "Submitted account and task link are required"}
+			}
+			return nil
+		},
+	},
+	GuardTaskLinkProvided: {
+		Description: "Task link is required",
+		check:       requireParam("Task link is required", func(p Params) string { return p.TaskLink }),
+	},
+	GuardChangesRequested: {
+		Description: "A reviewer must have requested changes on the task",
+		check: func(submission *models.ProjectVSubmission, actor Actor, params Params) error {
+			if !submission.HasChangesRequested || !submission.ChangesDone {
+				return &Error{Kind: ErrGuardFailed, Message: "Task does not have completed changes to confirm"}
+			}
+			return nil
+		},
+	},
+	GuardRejectionReason: {
+		Description: "Rejection reason is required",
+		check:       requireParam("Rejection reason is required", func(p Params) string { return p.RejectionReason }),
+	},
+	GuardCommentsResolved: {
+		Description: "All blocking review comments must be resolved",
+		check: func(submission *models.ProjectVSubmission, actor Actor, params Params) error {
+			var open int64
+			err := database.DB.Model(&models.Comment{}).
+				Where("submission_id = ? AND parent_id IS NULL AND blocking = ? AND resolved = ?", submission.ID, true, false).
+				Count(&open).Error
+			if err != nil {
+				return err
+			}
+			if open > 0 {
+				return &Error{Kind: ErrGuardFailed, Message: fmt.Sprintf("%d blocking comments must be resolved before resubmitting", open)}
+			}
+			return nil
+		},
+	},
+	GuardRubricScored: {
+		Description: "A completed review rubric is required",
+		check: func(submission *models.ProjectVSubmission, actor Actor, params Params) error {
+			if params.Rubric == nil || params.Rubric.SubmissionID != submission.ID || len(params.Rubric.Scores) == 0 {
+				return &Error{Kind: ErrGuardFailed, Message: "A completed review rubric is required"}
+			}
+			return nil
+		},
+	},
+	GuardAccountPostedIn: {
+		Description: "Account the task was posted in is required",
+		check:       requireParam("Account posted in is required", func(p Params) string { return p.AccountPostedIn }),
+	},
+}
+
+var effectDescriptions = map[string]string{
+	EffectClaimTester:    "Assigns the acting tester or admin if the task has no tester",
+	EffectClaimReviewer:  "Assigns the acting reviewer or admin if the task has no reviewer",
+	EffectAssignTester:   "Auto-assigns a tester chosen by the configured assignment strategy if the task has no tester",
+	EffectAssignReviewer: "Auto-assigns a reviewer chosen by the configured assignment strategy if the task has no reviewer",
+}
+
+func requireParam(message string, value func(Params) string) GuardFunc {
+	return func(submission *models.ProjectVSubmission, actor Actor, params Params) error {
+		if strings.TrimSpace(value(params)) == "" {
+			return &Error{Kind: ErrGuardFailed, Message: message}
+		}
+		return nil
+	}
+}
+
+type Graph struct {
+	Statuses    []models.ProjectVStatus `json:"statuses"`
+	Transitions []Transition            `json:"transitions"`
+	Guards      []Guard                 `json:"guards"`
+	Effects     []Effect                `json:"effects"`
+}
+
+func Describe() Graph {
+	graph := Graph{
+		Statuses:    Statuses,
+		Transitions: append([]Transition(nil), transitions...),
+		Guards:      make([]Guard, 0, len(guards)),
+		Effects:     make([]Effect, 0, len(effectDescriptions)),
+	}
+	for i, t := range graph.Transitions {
+		if t.Guards == nil {
+			graph.Transitions[i].Guards = []string{}
+		}
+		if t.Effects == nil {
+			graph.Transitions[i].Effects = []string{}
+		}
+		for _, name := range t.Guards {
+			if !containsGuard(graph.Guards, name) {
+				graph.Guards = append(graph.Guards, Guard{Name: name, Description: guards[name].Description})
+			}
+		}
+		for _, name := range t.Effects {
+			if !containsEffect(graph.Effects, name) {
+				graph.Effects = append(graph.Effects, Effect{Name: name, Description: effectDescriptions[name]})
+			}
+		}
+	}
+	return graph
+}
+
+func containsGuard(list []Guard, name string) bool {
+	for _, g := range list {
+		if g.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containsEffect(list []Effect, name string) bool {
+	for _, e := range list {
+		if e.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,405 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const RoleSystem = "SYSTEM"
+
+var (
+	ErrUnknownAction     = errors.New("unknown workflow action")
+	ErrForbidden         = errors.New("role is not allowed to perform this action")
+	ErrInvalidTransition = errors.New("transition is not allowed from the current status")
+	ErrGuardFailed       = errors.New("transition guard failed")
+	ErrEffectFailed      = errors.New("transition side effect failed")
+	ErrConflict          = errors.New("submission status changed concurrently")
+)
+
+type Actor struct {
+	ID   uuid.UUID
+	Name string
+	Role string
+}
+
+var SystemActor = Actor{Name: "System", Role: RoleSystem}
+
+func (a Actor) Is(roles ...models.UserRole) bool {
+	for _, role := range roles {
+		if a.Role == string(role) {
+			return true
+		}
+	}
+	return false
+}
+
+type Params struct {
+	Feedback          string
+	RejectionReason   string
+	TaskLink          string
+	SubmittedAccount  string
+	TaskLinkSubmitted string
+	AccountPostedIn   string
+	Reason            string
+	Rubric            *models.RubricEvaluation
+	Fields            []string
+	Revisions         []*models.SubmissionRevision
+}
+
+func (p Params) reason() string {
+	for _, value := range []string{p.Reason, p.RejectionReason, p.Feedback} {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+type Error struct {
+	Kind    error
+	Message string
+	Action  string
+	Guard   string
+	Current models.ProjectVStatus
+	Allowed []models.ProjectVStatus
+	Roles   []string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+type GuardFunc func(submission *models.ProjectVSubmission, actor Actor, params Params) error
+
+type EffectFunc func(tx *gorm.DB, submission *models.ProjectVSubmission, actor Actor) error
+
+type Event struct {
+	Submission         *models.ProjectVSubmission
+	From               models.ProjectVStatus
+	Action             string
+	Actor              Actor
+	Reason             string
+	PreviousTesterID   *uuid.UUID
+	PreviousReviewerID *uuid.UUID
+}
+
+type ObserverFunc func(event Event)
+
+type Guard struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	check       GuardFunc
+}
+
+type Effect struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type Transition struct {
+	Action  string                  `json:"action"`
+	Label   string                  `json:"label"`
+	From    []models.ProjectVStatus `json:"from"`
+	To      models.ProjectVStatus   `json:"to"`
+	Roles   []string                `json:"roles"`
+	Guards  []string                `json:"guards"`
+	Effects []string                `json:"effects"`
+	apply   func(submission *models.ProjectVSubmission, params Params)
+}
+
+func (t Transition) AllowsRole(role string) bool {
+	for _, r := range t.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (t Transition) AllowsFrom(status models.ProjectVStatus) bool {
+	for _, s := range t.From {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+var columns = []string{
+	"status", "tester_id", "reviewer_id",
+	"tester_feedback", "submitted_account", "task_link", "task_link_submitted",
+	"reviewer_feedback", "has_changes_requested", "changes_done", "rejection_reason", "account_posted_in",
+	"updated_at",
+}
+
+var (
+	effectsMu sync.RWMutex
+	effects   = map[string]EffectFunc{
+		EffectClaimTester:   claimTester,
+		EffectClaimReviewer: claimReviewer,
+	}
+)
+
+var (
+	observersMu sync.RWMutex
+	observers   []ObserverFunc
+)
+
+func RegisterEffect(name string, effect EffectFunc) {
+	effectsMu.Lock()
+	defer effectsMu.Unlock()
+	effects[name] = effect
+}
+
+func RegisterObserver(observer ObserverFunc) {
+	observersMu.Lock()
+	defer observersMu.Unlock()
+	observers = append(observers, observer)
+}
+
+func Lookup(action string) (Transition, bool) {
+	for _, t := range transitions {
+		if t.Action == action {
+			return t, true
+		}
+	}
+	return Transition{}, false
+}
+
+func ActionFor(from, to models.ProjectVStatus) (string, bool) {
+	for _, t := range transitions {
+		if t.To == to && t.AllowsFrom(from) {
+			return t.Action, true
+		}
+	}
+	return "", false
+}
+
+func Available(submission *models.ProjectVSubmission, actor Actor) []string {
+	actions := []string{}
+	for _, t := range transitions {
+		if t.AllowsFrom(submission.Status) && t.AllowsRole(actor.Role) {
+			actions = append(actions, t.Action)
+		}
+	}
+	return actions
+}
+
+func Check(submission *models.ProjectVSubmission, action string, actor Actor, params Params) (Transition, error) {
+	t, ok := Lookup(action)
+	if !ok {
+		return t, &Error{Kind: ErrUnknownAction, Message: fmt.Sprintf("Unknown workflow action %q", action), Action: action}
+	}
+
+	if !t.AllowsRole(actor.Role) {
+		return t, &Error{
+			Kind:    ErrForbidden,
+			Message: fmt.Sprintf("Only %s can %s", strings.Join(t.Roles, " or "), t.Label),
+			Action:  action,
+			Current: submission.Status,
+			Roles:   t.Roles,
+		}
+	}
+
+	if !t.AllowsFrom(submission.Status) {
+		return t, &Error{
+			Kind:    ErrInvalidTransition,
+			Message: fmt.Sprintf("Cannot %s while the task is %s", t.Label, submission.Status),
+			Action:  action,
+			Current: submission.Status,
+			Allowed: t.From,
+		}
+	}
+
+	for _, name := range t.Guards {
+		guard, ok := guards[name]
+		if !ok {
+			continue
+		}
+		if err := guard.check(submission, actor, params); err != nil {
+			guardErr := &Error{Kind: ErrGuardFailed, Message: err.Error()}
+			errors.As(err, &guardErr)
+			guardErr.Action = action
+			guardErr.Guard = name
+			guardErr.Current = submission.Status
+			return t, guardErr
+		}
+	}
+
+	return t, nil
+}
+
+func Apply(submission *models.ProjectVSubmission, action string, actor Actor, params Params) error {
+	t, err := Check(submission, action, actor, params)
+	if err != nil {
+		return err
+	}
+
+	previous := submission.Status
+	var previousTester, previousReviewer *uuid.UUID
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var current models.ProjectVSubmission
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "status", "tester_id", "reviewer_id").
+			First(&current, submission.ID).Error
+		if err != nil {
+			return err
+		}
+		if current.Status != previous {
+			return &Error{
+				Kind:    ErrConflict,
+				Message: "Task status changed while this update was being applied, please refresh and try again",
+				Action:  action,
+				Current: current.Status,
+			}
+		}
+		previousTester, previousReviewer = current.TesterID, current.ReviewerID
+		if submission.TesterID == nil {
+			submission.TesterID = current.TesterID
+		}
+		if submission.ReviewerID == nil {
+			submission.ReviewerID = current.ReviewerID
+		}
+
+		if t.apply != nil {
+			t.apply(submission, params)
+		}
+		submission.Status = t.To
+
+		for _, name := range t.Effects {
+			effectsMu.RLock()
+			effect, ok := effects[name]
+			effectsMu.RUnlock()
+			if !ok {
+				log.Printf("Workflow effect %s is not registered, skipping", name)
+				continue
+			}
+			if err := effect(tx, submission, actor); err != nil {
+				return &Error{
+					Kind:    ErrEffectFailed,
+					Message: fmt.Sprintf("Failed to %s: %v", t.Label, err),
+					Action:  action,
+					Current: previous,
+				}
+			}
+		}
+
+		submission.UpdatedAt = time.Now()
+		result := tx.Model(submission).
+			Where("status = ?", previous).
+			Select(append(append([]string{}, columns...), params.Fields...)).
+			Updates(submission)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return &Error{
+				Kind:    ErrConflict,
+				Message: "Task status changed while this update was being applied, please refresh and try again",
+				Action:  action,
+				Current: previous,
+			}
+		}
+		for _, revision := range params.Revisions {
+			if err := createRevision(tx, submission.ID, revision); err != nil {
+				return err
+			}
+		}
+		if params.Rubric != nil {
+			params.Rubric.Action = action
+			if err := tx.Create(params.Rubric).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(newTransition(submission.ID, previous, t, actor, params)).Error
+	})
+	if err != nil {
+		submission.Status = previous
+		return err
+	}
+
+	event := Event{
+		Submission:         submission,
+		From:               previous,
+		Action:             action,
+		Actor:              actor,
+		Reason:             params.reason(),
+		PreviousTesterID:   previousTester,
+		PreviousReviewerID: previousReviewer,
+	}
+	observersMu.RLock()
+	defer observersMu.RUnlock()
+	for _, observer := range observers {
+		observer(event)
+	}
+	return nil
+}
+
+func newTransition(submissionID uuid.UUID, from models.ProjectVStatus, t Transition, actor Actor, params Params) *models.StatusTransition {
+	fromStatus := string(from)
+	action := t.Action
+	transition := &models.StatusTransition{
+		SubjectType: models.TransitionSubjectProjectV,
+		SubjectID:   submissionID,
+		FromStatus:  &fromStatus,
+		ToStatus:    string(t.To),
+		Action:      &action,
+		ActorRole:   actor.Role,
+	}
+	if actor.ID != uuid.Nil {
+		id := actor.ID
+		transition.ActorID = &id
+	}
+	if actor.Name != "" {
+		name := actor.Name
+		transition.ActorName = &name
+	}
+	if reason := params.reason(); reason != "" {
+		transition.Reason = &reason
+	}
+	return transition
+}
+
+func createRevision(tx *gorm.DB, submissionID uuid.UUID, revision *models.SubmissionRevision) error {
+	var latest int
+	err := tx.Model(&models.SubmissionRevision{}).
+		Where("submission_id = ? AND artifact = ?", submissionID, revision.Artifact).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error
+	if err != nil {
+		return err
+	}
+	revision.SubmissionID = submissionID
+	revision.Version = latest + 1
+	return tx.Create(revision).Error
+}
+
+func claimTester(tx *gorm.DB, submission *models.ProjectVSubmission, actor Actor) error {
+	if submission.TesterID == nil && actor.Is(models.RoleTester, models.RoleAdmin) {
+		id := actor.ID
+		submission.TesterID = &id
+	}
+	return nil
+}
+
+func claimReviewer(tx *gorm.DB, submission *models.ProjectVSubmission, actor Actor) error {
+	if submission.ReviewerID == nil && actor.Is(models.RoleReviewer, models.RoleAdmin) {
+		id := actor.ID
+		submission.ReviewerID = &id
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/adzzatxperts/backend/internal/database"
+	"github.com/adzzatxperts/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	testDBOnce sync.Once
+	testDBErr  error
+)
+
+func setupTestDB(t *testing.T) {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	testDBOnce.Do(func() {
+		os.Setenv("DATABASE_URL", dsn)
+		if testDBErr = database.Connect(); testDBErr != nil {
+			return
+		}
+		database.DB.Logger = logger.Default.LogMode(logger.Silent)
+		if testDBErr = database.AutoMigrate(); testDBErr != nil {
+			return
+		}
+		database.DB.Logger = logger.Default.LogMode(logger.Silent)
+	})
+	if testDBErr != nil {
+		t.Fatalf("failed to prepare test database: %v", testDBErr)
+	}
+}
+
+func TestCheckTransitions(t *testing.T) {
+	contributorID := uuid.New()
+	submissionID := uuid.New()
+	rubric := &models.RubricEvaluation{SubmissionID: submissionID, Scores: []models.RubricScore{{CriterionName: "correctness", Score: 4}}}
+	params := Params{
+		Feedback:          "Needs more tests",
+		RejectionReason:   "Duplicate task",
+		TaskLink:          "https://platform.example.test/tasks/1",
+		SubmittedAccount:  "account-1",
+		TaskLinkSubmitted: "https://platform.example.test/tasks/1",
+		AccountPostedIn:   "account-1",
+		Rubric:            rubric,
+	}
+
+	cases := []struct {
+		from    models.ProjectVStatus
+		action  string
+		role    string
+		actorID uuid.UUID
+		wantErr error
+	}{
+		{from: models.ProjectVStatusSubmitted, action: ActionStartTesting, role: RoleSystem},
+		{from: models.ProjectVStatusSubmitted, action: ActionStartTesting, role: roleTester},
+		{from: models.ProjectVStatusSubmitted, action: ActionStartTesting, role: roleAdmin},
+		{from: models.ProjectVStatusSubmitted, action: ActionStartTesting, role: roleReviewer, wantErr: ErrForbidden},
+		{from: models.ProjectVStatusSubmitted, action: ActionStartTesting, role: roleContributor, wantErr: ErrForbidden},
+		{from: models.ProjectVStatusInTesting, action: ActionStartTesting, role: roleTester, wantErr: ErrInvalidTransition},
+		{from: models.ProjectVStatusEligible, action: ActionSendFeedback, role: roleTester},
+		{from: models.ProjectVStatusPendingReview, action: ActionSendFeedback, role: roleTester, wantErr: ErrInvalidTransition},
+		{from: models.ProjectVStatusRework, action: ActionResubmit, role: roleTester, wantErr: ErrForbidden},
+		{from: models.ProjectVStatusRework, action: ActionResubmit, role: roleContributor, actorID: uuid.New(), wantErr: ErrForbidden},
+		{from: models.ProjectVStatusInTesting, action: ActionResubmit, role: roleContributor, actorID: contributorID, wantErr: ErrInvalidTransition},
+		{from: models.ProjectVStatusReworkDone, action: ActionResumeTesting, role: roleTester},
+		{from: models.ProjectVStatusReworkDone, action: ActionResumeTesting, role: RoleSystem, wantErr: ErrForbidden},
+		{from: models.ProjectVStatusInTesting, action: ActionSubmitToPlatform, role: roleTester},
+		{from: models.ProjectVStatusEligible, action: ActionSubmitToPlatform, role: roleTester, wantErr: ErrInvalidTransition},
+		{from: models.ProjectVStatusTaskSubmittedToPlatform, action: ActionMarkEligible, role: roleTester},
+		{from: models.ProjectVStatusSubmitted, action: ActionMarkEligible, role: roleTester, wantErr: ErrInvalidTransition},
+		{from: models.ProjectVStatusEligible, action: ActionRequestReview, role: roleAdmin},
+		{from: models.ProjectVStatusEligible, action: ActionRequestReview, role: roleReviewer, wantErr: ErrForbidden},
+		{from: models.ProjectVStatusPendingReview, action: ActionRequestChanges, role: roleReviewer},
+		{from: models.ProjectVStatusPendingReview, action: ActionRequestChanges, role: roleTester, wantErr: ErrForbidden},
+		{from: models.ProjectVStatusFinalChecks, action: ActionRequestChanges, role: roleReviewer, wantErr: ErrInvalidTransition},
+		{from: models.ProjectVStatusChangesRequested, action: ActionChangesDone, role: roleReviewer, wantErr: ErrForbidden},
+		{from: models.ProjectVStatusChangesDone, action: ActionFinalChecks, role: roleReviewer},
+		{from: models.ProjectVStatusInTesting, action: ActionFinalChecks, role: roleReviewer, wantErr: ErrInvalidTransition},
+		{from: models.ProjectVStatusPendingReview, action: ActionReject, role: roleReviewer},
+		{from: models.ProjectVStatusApproved, action: ActionReject, role: roleAdmin, wantErr: ErrInvalidTransition},
+		{from: models.ProjectVStatusFinalChecks, action: ActionApprove, role: roleAdmin},
+		{from: models.ProjectVStatusFinalChecks, action: ActionApprove, role: roleReviewer, wantErr: ErrForbidden},
+		{from: models.ProjectVStatusRejected, action: ActionApprove, role: roleAdmin, wantErr: ErrInvalidTransition},
+		{from: models.ProjectVStatusSubmitted, action: "ARCHIVE", role: roleAdmin, wantErr: ErrUnknownAction},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%s %s as %s", tc.action, tc.from, tc.role), func(t *testing.T) {
+			submission := &models.ProjectVSubmission{ID: submissionID, Status: tc.from, ContributorID: contributorID}
+			actor := Actor{ID: tc.actorID, Role: tc.role}
+
+			_, err := Check(submission, tc.action, actor, params)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Check error = %v, want %v", err, tc.wantErr)
+			}
+
+			var workflowErr *Error
+			if tc.wantErr != nil && (!errors.As(err, &workflowErr) || workflowErr.Action != tc.action) {
+				t.Fatalf("Check error = %#v, want a workflow error for %s", err, tc.action)
+			}
+		})
+	}
+}
+
+func TestCheckRubricScored(t *testing.T) {
+	submission := &models.ProjectVSubmission{ID: uuid.New(), Status: models.ProjectVStatusPendingReview}
+	reviewer := Actor{ID: uuid.New(), Role: roleReviewer}
+	scores := []models.RubricScore{{CriterionName: "correctness", Score: 3}}
+
+	cases := []struct {
+		name    string
+		rubric  *models.RubricEvaluation
+		wantErr error
+	}{
+		{name: "scored rubric", rubric: &models.RubricEvaluation{SubmissionID: submission.ID, Scores: scores}},
+		{name: "missing rubric", rubric: nil, wantErr: ErrGuardFailed},
+		{name: "rubric without scores", rubric: &models.RubricEvaluation{SubmissionID: submission.ID}, wantErr: ErrGuardFailed},
+		{name: "rubric for another submission", rubric: &models.RubricEvaluation{SubmissionID: uuid.New(), Scores: scores}, wantErr: ErrGuardFailed},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, action := range []string{ActionRequestChanges, ActionFinalChecks, ActionReject} {
+				params := Params{Feedback: "Please fix", RejectionReason: "Out of scope", Rubric: tc.rubric}
+				_, err := Check(submission, action, reviewer, params)
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("%s error = %v, want %v", action, err, tc.wantErr)
+				}
+				var workflowErr *Error
+				if tc.wantErr != nil && (!errors.As(err, &workflowErr) || workflowErr.Guard != GuardRubricScored) {
+					t.Fatalf("%s failed guard = %#v, want %s", action, err, GuardRubricScored)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckGuardsRequireParams(t *testing.T) {
+	cases := []struct {
+		from   models.ProjectVStatus
+		action string
+		role   string
+		guard  string
+	}{
+		{from: models.ProjectVStatusInTesting, action: ActionSendFeedback, role: roleTester, guard: GuardFeedbackProvided},
+		{from: models.ProjectVStatusInTesting, action: ActionSubmitToPlatform, role: roleTester, guard: GuardPlatformSubmission},
+		{from: models.ProjectVStatusInTesting, action: ActionMarkEligible, role: roleTester, guard: GuardTaskLinkProvided},
+		{from: models.ProjectVStatusInTesting, action: ActionConfirmChanges, role: roleTester, guard: GuardChangesRequested},
+		{from: models.ProjectVStatusPendingReview, action: ActionReject, role: roleReviewer, guard: GuardRejectionReason},
+		{from: models.ProjectVStatusFinalChecks, action: ActionApprove, role: roleAdmin, guard: GuardAccountPostedIn},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.action, func(t *testing.T) {
+			submission := &models.ProjectVSubmission{ID: uuid.New(), Status: tc.from}
+			_, err := Check(submission, tc.action, Actor{ID: uuid.New(), Role: tc.role}, Params{Feedback: "  "})
+
+			var workflowErr *Error
+			if !errors.Is(err, ErrGuardFailed) || !errors.As(err, &workflowErr) || workflowErr.Guard != tc.guard {
+				t.Fatalf("Check error = %#v, want %s to fail", err, tc.guard)
+			}
+		})
+	}
+}
+
+func TestCheckCommentsResolved(t *testing.T) {
+	setupTestDB(t)
+
+	suffix := uuid.NewString()[:8]
+	contributor := models.User{Email: "test-contributor-" + suffix + "@example.test", PasswordHash: "-", Name: "test contributor", Role: models.RoleContributor, IsApproved: true}
+	if err := database.DB.Create(&contributor).Error; err != nil {
+		t.Fatalf("failed to create contributor: %v", err)
+	}
+	t.Cleanup(func() { database.DB.Delete(&contributor) })
+
+	submission := models.ProjectVSubmission{
+		Title:            "Workflow guard fixture",
+		Language:         "go",
+		Category:         "bug",
+		Difficulty:       models.DifficultyEasy,
+		Description:      "-",
+		GithubRepo:       "https://example.test/workflow/" + suffix,
+		CommitHash:       fmt.Sprintf("%040d", 0),
+		TestPatchURL:     "-",
+		DockerfileURL:    "-",
+		SolutionPatchURL: "-",
+		Status:           models.ProjectVStatusRework,
+		ContributorID:    contributor.ID,
+	}
+	if err := database.DB.Create(&submission).Error; err != nil {
+		t.Fatalf("failed to create submission: %v", err)
+	}
+	t.Cleanup(func() { database.DB.Delete(&submission) })
+
+	actor := Actor{ID: contributor.ID, Role: roleContributor}
+	blocking := models.Comment{SubmissionID: submission.ID, AuthorRole: roleTester, Body: "Fix the flaky test", Blocking: true}
+	for _, comment := range []*models.Comment{
+		&blocking,
+		{SubmissionID: submission.ID, AuthorRole: roleTester, Body: "Nit: rename the helper"},
+	} {
+		if err := database.DB.Create(comment).Error; err != nil {
+			t.Fatalf("failed to create comment: %v", err)
+		}
+	}
+
+	for _, action := range []string{ActionResubmit, ActionChangesDone} {
+		transition, _ := Lookup(action)
+		submission.Status = transition.From[0]
+
+		_, err := Check(&submission, action, actor, Params{})
+		var workflowErr *Error
+		if !errors.Is(err, ErrGuardFailed) || !errors.As(err, &workflowErr) || workflowErr.Guard != GuardCommentsResolved {
+			t.Fatalf("%s with an open blocking comment = %#v, want %s to fail", action, err, GuardCommentsResolved)
+		}
+	}
+
+	if err := database.DB.Model(&blocking).Update("resolved", true).Error; err != nil {
+		t.Fatalf("failed to resolve comment: %v", err)
+	}
+	for _, action := range []string{ActionResubmit, ActionChangesDone} {
+		transition, _ := Lookup(action)
+		submission.Status = transition.From[0]
+		if _, err := Check(&submission, action, actor, Params{}); err != nil {
+			t.Fatalf("%s with resolved comments = %v", action, err)
+		}
+	}
+}
+
+func TestActionFor(t *testing.T) {
+	cases := []struct {
+		from   models.ProjectVStatus
+		to     models.ProjectVStatus
+		action string
+	}{
+		{from: models.ProjectVStatusSubmitted, to: models.ProjectVStatusInTesting, action: ActionStartTesting},
+		{from: models.ProjectVStatusSubmitted, to: models.ProjectVStatusRework, action: ActionSendFeedback},
+		{from: models.ProjectVStatusRework, to: models.ProjectVStatusReworkDone, action: ActionResubmit},
+		{from: models.ProjectVStatusReworkDone, to: models.ProjectVStatusInTesting, action: ActionResumeTesting},
+		{from: models.ProjectVStatusChangesRequested, to: models.ProjectVStatusInTesting, action: ActionChangesDone},
+		{from: models.ProjectVStatusInTesting, to: models.ProjectVStatusChangesDone, action: ActionConfirmChanges},
+		{from: models.ProjectVStatusEligible, to: models.ProjectVStatusApproved, action: ActionApprove},
+		{from: models.ProjectVStatusChangesDone, to: models.ProjectVStatusRejected, action: ActionReject},
+		{from: models.ProjectVStatusSubmitted, to: models.ProjectVStatusApproved},
+		{from: models.ProjectVStatusApproved, to: models.ProjectVStatusInTesting},
+		{from: models.ProjectVStatusRejected, to: models.ProjectVStatusRework},
+	}
+
+	for _, tc := range cases {
+		action, ok := ActionFor(tc.from, tc.to)
+		if action != tc.action || ok != (tc.action != "") {
+			t.Errorf("ActionFor(%s, %s) = %q, %v, want %q", tc.from, tc.to, action, ok, tc.action)
+		}
+	}
+}
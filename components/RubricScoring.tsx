@@ -0,0 +1,123 @@
+'use client'
+
+import { useEffect, useState } from 'react'
+import { apiClient, Rubric, RubricSubmission } from '@/lib/api-client'
+
+interface RubricScoringProps {
+  submissionId: string
+  disabled?: boolean
+  onChange: (rubric: RubricSubmission | null) => void
+}
+
+export default function RubricScoring({ submissionId, disabled, onChange }: RubricScoringProps) {
+  const [rubric, setRubric] = useState<Rubric | null>(null)
+  const [scores, setScores] = useState<Record<string, number>>({})
+  const [comment, setComment] = useState('')
+  const [loading, setLoading] = useState(true)
+  const [error, setError] = useState('')
+
+  useEffect(() => {
+    let cancelled = false
+    setLoading(true)
+    setError('')
+    setScores({})
+    setComment('')
+    apiClient
+      .getSubmissionRubric(submissionId)
+      .then((data) => {
+        if (cancelled) return
+        setRubric(data.rubric || null)
+        if (!data.rubric) {
+          setError('No active rubric is configured for this task, ask an admin to add one')
+        }
+      })
+      .catch((err: any) => {
+        if (!cancelled) setError(err.response?.data?.error || 'Failed to load the review rubric')
+      })
+      .finally(() => {
+        if (!cancelled) setLoading(false)
+      })
+    return () => {
+      cancelled = true
+    }
+  }, [submissionId])
+
+  useEffect(() => {
+    if (!rubric || rubric.criteria.some((criterion) => scores[criterion.id] === undefined)) {
+      onChange(null)
+      return
+    }
+    onChange({
+      rubricId: rubric.id,
+      scores: rubric.criteria.map((criterion) => ({ criterionId: criterion.id, score: scores[criterion.id] })),
+      comment: comment.trim() || undefined,
+    })
+  }, [rubric, scores, comment, onChange])
+
+  if (loading) {
+    return <div className="text-sm text-gray-400 font-medium">Loading review rubric...</div>
+  }
+
+  if (error || !rubric) {
+    return (
+      <div className="px-4 py-3 bg-red-500/10 border-2 border-red-500/30 rounded-xl text-sm text-red-300 font-semibold">
+        {error}
+      </div>
+    )
+  }
+
+  const scored = rubric.criteria.filter((criterion) => scores[criterion.id] !== undefined).length
+
+  return (
+    <div className="bg-gray-700/50 rounded-xl p-5 border border-gray-600/50 space-y-4">
+      <div className="flex justify-between items-center">
+        <h5 className="font-bold text-gray-200">📋 {rubric.name}</h5>
+        <span className="text-xs text-gray-400 font-semibold">
+          {scored}/{rubric.criteria.length} scored
+        </span>
+      </div>
+
+      {rubric.criteria.map((criterion) => {
+        const options = Array.from(
+          { length: criterion.maxScore - criterion.minScore + 1 },
+          (_, i) => criterion.minScore + i
+        )
+        return (
+          <div key={criterion.id}>
+            <div className="flex justify-between items-baseline mb-1">
+              <span className="text-sm font-bold text-white">{criterion.name}</span>
+              <span className="text-xs text-gray-400">weight {criterion.weight}</span>
+            </div>
+            {criterion.description && <p className="text-xs text-gray-400 mb-2">{criterion.description}</p>}
+            <div className="flex flex-wrap gap-2">
+              {options.map((value) => (
+                <button
+                  key={value}
+                  type="button"
+                  disabled={disabled}
+                  onClick={() => setScores((prev) => ({ ...prev, [criterion.id]: value }))}
+                  className={`w-10 h-10 rounded-lg text-sm font-bold transition-all duration-300 disabled:opacity-50 ${
+                    scores[criterion.id] === value
+                      ? 'bg-gradient-to-r from-cyan-600 to-blue-600 text-white shadow-lg scale-105'
+                      : 'bg-gray-900/50 text-gray-300 border border-gray-600 hover:bg-gray-600/50'
+                  }`}
+                >
+                  {value}
+                </button>
+              ))}
+            </div>
+          </div>
+        )
+      })}
+
+      <textarea
+        value={comment}
+        onChange={(e) => setComment(e.target.value)}
+        rows={2}
+        disabled={disabled}
+        placeholder="Optional note about this evaluation..."
+        className="w-full px-4 py-3 rounded-xl border-2 border-gray-700 bg-gray-900/50 text-white placeholder-gray-400 focus:border-cyan-500 focus:ring-4 focus:ring-cyan-500/20 font-medium text-sm"
+      />
+    </div>
+  )
+}
@@ -0,0 +1,161 @@
+'use client'
+
+import { useEffect, useState } from 'react'
+import { useAuth } from '@/lib/auth-context'
+import { useToast } from '@/components/ToastContainer'
+import { apiClient, SigninChallenge, TwoFactorSetup } from '@/lib/api-client'
+
+interface TwoFactorChallengeProps {
+  challenge: SigninChallenge
+  onCancel: () => void
+  onComplete: () => void
+}
+
+export default function TwoFactorChallenge({ challenge, onCancel, onComplete }: TwoFactorChallengeProps) {
+  const { verifyTwoFactor, confirmTwoFactorEnrollment } = useAuth()
+  const { showToast } = useToast()
+  const [setup, setSetup] = useState<TwoFactorSetup | null>(null)
+  const [setupError, setSetupError] = useState('')
+  const [code, setCode] = useState('')
+  const [recoveryCodes, setRecoveryCodes] = useState<string[] | null>(null)
+  const [submitting, setSubmitting] = useState(false)
+
+  useEffect(() => {
+    if (!challenge.twoFactorSetupRequired) return
+    let cancelled = false
+    apiClient
+      .beginTwoFactorEnrollment(challenge.challengeToken)
+      .then((data) => {
+        if (!cancelled) setSetup(data)
+      })
+      .catch((err: any) => {
+        if (!cancelled) setSetupError(err.response?.data?.error || 'Failed to start two-factor setup')
+      })
+    return () => {
+      cancelled = true
+    }
+  }, [challenge])
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault()
+    setSubmitting(true)
+
+    try {
+      if (challenge.twoFactorSetupRequired) {
+        const codes = await confirmTwoFactorEnrollment(challenge.challengeToken, code.trim())
+        showToast('Two-factor authentication enabled!', 'success')
+        setRecoveryCodes(codes)
+      } else {
+        await verifyTwoFactor(challenge.challengeToken, code.trim())
+        showToast('Welcome back!', 'success')
+        onComplete()
+      }
+    } catch (err: any) {
+      showToast(err.response?.data?.error || 'Verification failed', 'error')
+      setCode('')
+    } finally {
+      setSubmitting(false)
+    }
+  }
+
+  if (recoveryCodes) {
+    return (
+      <div className="space-y-5 animate-slide-up">
+        <div>
+          <h2 className="text-xl font-black text-white mb-2">🔑 Save your recovery codes</h2>
+          <p className="text-sm text-gray-300">
+            Each code signs you in once if you lose access to your authenticator app. They will not be shown again.
+          </p>
+        </div>
+        <div className="grid grid-cols-2 gap-2 p-4 rounded-xl bg-gray-900/50 border-2 border-gray-700 font-mono text-sm text-white">
+          {recoveryCodes.map((recoveryCode) => (
+            <span key={recoveryCode}>{recoveryCode}</span>
+          ))}
+        </div>
+        <button
+          type="button"
+          onClick={onComplete}
+          className="w-full py-4 rounded-xl font-black text-lg transition-all duration-300 transform hover:scale-105 active:scale-95 shadow-2xl bg-gradient-to-r from-blue-600 via-indigo-600 to-purple-600 hover:from-blue-700 hover:via-indigo-700 hover:to-purple-700 text-white"
+        >
+          I saved my codes, continue
+        </button>
+      </div>
+    )
+  }
+
+  const settingUp = challenge.twoFactorSetupRequired
+
+  return (
+    <form onSubmit={handleSubmit} className="space-y-5 animate-slide-up">
+      <div>
+        <h2 className="text-xl font-black text-white mb-2">
+          {settingUp ? '🛡️ Set up two-factor authentication' : '🛡️ Two-factor authentication'}
+        </h2>
+        <p className="text-sm text-gray-300">
+          {settingUp
+            ? 'Your role requires two-factor authentication. Add this account to your authenticator app, then enter the 6-digit code it shows.'
+            : 'Enter the 6-digit code from your authenticator app, or one of your recovery codes.'}
+        </p>
+      </div>
+
+      {settingUp && setupError && (
+        <div className="px-4 py-3 bg-red-500/10 border-2 border-red-500/30 rounded-xl text-sm text-red-300 font-semibold">
+          {setupError}
+        </div>
+      )}
+
+      {settingUp && !setup && !setupError && (
+        <div className="text-sm text-gray-400 font-medium">Preparing your authenticator secret...</div>
+      )}
+
+      {setup && (
+        <div className="p-4 rounded-xl bg-gray-900/50 border-2 border-gray-700 space-y-2">
+          <p className="text-xs font-bold text-gray-400 uppercase">Secret key</p>
+          <p className="font-mono text-sm text-white break-all select-all">{setup.secret}</p>
+          <a href={setup.otpauthUrl} className="inline-block text-sm font-bold text-blue-400 hover:text-blue-300 underline-offset-4 hover:underline">
+            Open in authenticator app
+          </a>
+        </div>
+      )}
+
+      <div>
+        <label className="block text-sm font-bold mb-2.5 text-gray-200">
+          {settingUp ? 'Authentication Code' : 'Authentication or Recovery Code'}
+        </label>
+        <input
+          type="text"
+          required
+          autoFocus
+          autoComplete="one-time-code"
+          inputMode={settingUp ? 'numeric' : 'text'}
+          value={code}
+          onChange={(e) => setCode(e.target.value)}
+          className="w-full px-5 py-4 rounded-xl border-2 transition-all duration-300 focus:scale-[1.02] bg-gray-900/50 border-gray-700 text-white placeholder-gray-500 focus:border-blue-500 focus:ring-4 focus:ring-blue-500/20 focus:glow font-mono tracking-widest"
+          placeholder={settingUp ? '123456' : '123456 or xxxxxxxx-xxxxxxxx'}
+        />
+      </div>
+
+      <button
+        type="submit"
+        disabled={submitting || (settingUp && !setup)}
+        className={`w-full py-4 rounded-xl font-black text-lg transition-all duration-300 transform hover:scale-105 active:scale-95 shadow-2xl ${
+          submitting || (settingUp && !setup)
+            ? 'bg-gray-400 cursor-not-allowed opacity-50'
+            : 'bg-gradient-to-r from-blue-600 via-indigo-600 to-purple-600 hover:from-blue-700 hover:via-indigo-700 hover:to-purple-700 text-white'
+        }`}
+      >
+        {submitting ? 'Please wait...' : settingUp ? '✅ Enable & Sign In' : '🔓 Verify'}
+      </button>
+
+      <div className="text-center text-sm text-gray-300">
+        <button
+          type="button"
+          onClick={onCancel}
+          className="font-bold underline-offset-4 hover:underline transition-all text-blue-400 hover:text-blue-300"
+        >
+          Back to sign in
+        </button>
+      </div>
+    </form>
+  )
+}
@@ -1,7 +1,55 @@
-import axios, { AxiosInstance, AxiosError } from 'axios'
+import axios, { AxiosInstance, AxiosError, InternalAxiosRequestConfig } from 'axios'
+
+const PUBLIC_AUTH_PATHS = [
+  '/auth/signin',
+  '/auth/signup',
+  '/auth/refresh',
+  '/auth/revoke',
+  '/auth/forgot-password',
+  '/auth/reset-password',
+  '/auth/2fa/',
+]
+
+export interface RubricCriterion {
+  id: string
+  position: number
+  name: string
+  description: string
+  weight: number
+  minScore: number
+  maxScore: number
+}
+
+export interface Rubric {
+  id: string
+  name: string
+  description?: string
+  category: string
+  difficulty: string
+  criteria: RubricCriterion[]
+}
+
+export interface RubricSubmission {
+  rubricId: string
+  scores: { criterionId: string; score: number; note?: string }[]
+  comment?: string
+}
+
+export interface SigninChallenge {
+  twoFactorRequired: boolean
+  twoFactorSetupRequired: boolean
+  challengeToken: string
+  expiresIn: number
+}
+
+export interface TwoFactorSetup {
+  secret: string
+  otpauthUrl: string
+}
 
 class ApiClient {
   private client: AxiosInstance
+  private refreshing: Promise<string | null> | null = null
 
   constructor() {
     
@@ -30,16 +78,61 @@ class ApiClient {
     
     this.client.interceptors.response.use(
       (response) => response,
-      (error: AxiosError) => {
-        if (error.response?.status === 401 && typeof window !== 'undefined') {
-          localStorage.removeItem('authToken')
-          window.location.href = '/' 
+      async (error: AxiosError) => {
+        const request = error.config as (InternalAxiosRequestConfig & { _retried?: boolean }) | undefined
+        const publicAuth = PUBLIC_AUTH_PATHS.some((path) => request?.url?.startsWith(path))
+        if (error.response?.status === 401 && typeof window !== 'undefined' && request && !publicAuth) {
+          if (!request._retried) {
+            request._retried = true
+            const token = await this.refreshAccessToken()
+            if (token) {
+              request.headers.Authorization = `Bearer ${token}`
+              return this.client(request)
+            }
+          }
+          this.clearTokens()
+          window.location.href = '/'
         }
         return Promise.reject(error)
       }
     )
   }
 
+  private refreshAccessToken(): Promise<string | null> {
+    if (!this.refreshing) {
+      const refreshToken = localStorage.getItem('refreshToken')
+      this.refreshing = (refreshToken
+        ? axios
+            .post(`${this.getBaseURL()}/auth/refresh`, { refreshToken }, { timeout: 30000 })
+            .then((response) => {
+              this.storeTokens(response.data)
+              return response.data.accessToken as string
+            })
+            .catch(() => null)
+        : Promise.resolve(null)
+      ).finally(() => {
+        this.refreshing = null
+      })
+    }
+    return this.refreshing
+  }
+
+  private storeTokens(data: { token?: string; accessToken?: string; refreshToken?: string }) {
+    if (typeof window === 'undefined') return
+    const accessToken = data.accessToken || data.token
+    if (accessToken) {
+      localStorage.setItem('authToken', accessToken)
+    }
+    if (data.refreshToken) {
+      localStorage.setItem('refreshToken', data.refreshToken)
+    }
+  }
+
+  private clearTokens() {
+    localStorage.removeItem('authToken')
+    localStorage.removeItem('refreshToken')
+  }
+
   
   private ensureProtocol(url: string): string {
     const trimmedUrl = url.trim()
@@ -100,28 +193,37 @@ class ApiClient {
   
   async signup(data: { email: string; password: string; name: string; role: string }) {
     const response = await this.client.post('/auth/signup', data)
-    if (response.data.token) {
-      if (typeof window !== 'undefined') {
-        localStorage.setItem('authToken', response.data.token)
-      }
-    }
+    this.storeTokens(response.data)
     return response.data
   }
 
   async signin(data: { email: string; password: string }) {
     const response = await this.client.post('/auth/signin', data)
-    if (response.data.token) {
-      if (typeof window !== 'undefined') {
-        localStorage.setItem('authToken', response.data.token)
-      }
-    }
+    this.storeTokens(response.data)
+    return response.data
+  }
+
+  async verifyTwoFactor(challengeToken: string, code: string) {
+    const response = await this.client.post('/auth/2fa/verify', { challengeToken, code })
+    this.storeTokens(response.data)
+    return response.data
+  }
+
+  async beginTwoFactorEnrollment(challengeToken: string): Promise<TwoFactorSetup> {
+    const response = await this.client.post('/auth/2fa/enroll', { challengeToken })
+    return response.data
+  }
+
+  async confirmTwoFactorEnrollment(challengeToken: string, code: string) {
+    const response = await this.client.post('/auth/2fa/enroll/confirm', { challengeToken, code })
+    this.storeTokens(response.data)
     return response.data
   }
 
   async logout() {
     const response = await this.client.post('/auth/logout')
     if (typeof window !== 'undefined') {
-      localStorage.removeItem('authToken')
+      this.clearTokens()
     }
     return response.data
   }
@@ -321,15 +423,23 @@ class ApiClient {
     return response.data
   }
 
-  async markChangesRequested(id: string, feedback: string) {
+  async markChangesRequested(id: string, feedback: string, rubric: RubricSubmission) {
     const response = await this.client.put(`/projectv/submissions/${id}/changes-requested`, {
       feedback,
+      rubric,
+    })
+    return response.data
+  }
+
+  async markFinalChecks(id: string, rubric: RubricSubmission) {
+    const response = await this.client.put(`/projectv/submissions/${id}/final-checks`, {
+      rubric,
     })
     return response.data
   }
 
-  async markFinalChecks(id: string) {
-    const response = await this.client.put(`/projectv/submissions/${id}/final-checks`)
+  async getSubmissionRubric(id: string): Promise<{ rubric?: Rubric; evaluations: any[] }> {
+    const response = await this.client.get(`/projectv/submissions/${id}/rubric`)
     return response.data
   }
 
@@ -374,9 +484,10 @@ class ApiClient {
     return response.data
   }
 
-  async markRejected(id: string, rejectionReason: string) {
+  async markRejected(id: string, rejectionReason: string, rubric: RubricSubmission) {
     const response = await this.client.put(`/projectv/submissions/${id}/rejected`, {
       rejectionReason,
+      rubric,
     })
     return response.data
   }
@@ -1,7 +1,7 @@
 'use client'
 
 import { createContext, useContext, useEffect, useState, ReactNode } from 'react'
-import { apiClient } from './api-client'
+import { apiClient, SigninChallenge } from './api-client'
 
 interface User {
   id: string
@@ -15,8 +15,10 @@ interface User {
 interface AuthContextType {
   user: User | null
   loading: boolean
-  login: (email: string, password: string) => Promise<void>
-  signup: (email: string, password: string, name: string, role: string) => Promise<void>
+  login: (email: string, password: string) => Promise<SigninChallenge | null>
+  signup: (email: string, password: string, name: string, role: string) => Promise<SigninChallenge | null>
+  verifyTwoFactor: (challengeToken: string, code: string) => Promise<void>
+  confirmTwoFactorEnrollment: (challengeToken: string, code: string) => Promise<string[]>
   logout: () => Promise<void>
   refreshUser: () => Promise<void>
 }
@@ -45,14 +47,33 @@ export function AuthProvider({ children }: { children: ReactNode }) {
     }
   }
 
+  const completeSignin = (data: any): SigninChallenge | null => {
+    if (data.challengeToken) {
+      return data as SigninChallenge
+    }
+    setUser(data.user)
+    return null
+  }
+
   const login = async (email: string, password: string) => {
     const data = await apiClient.signin({ email, password })
-    setUser(data.user)
+    return completeSignin(data)
   }
 
   const signup = async (email: string, password: string, name: string, role: string) => {
     const data = await apiClient.signup({ email, password, name, role })
+    return completeSignin(data)
+  }
+
+  const verifyTwoFactor = async (challengeToken: string, code: string) => {
+    const data = await apiClient.verifyTwoFactor(challengeToken, code)
+    setUser(data.user)
+  }
+
+  const confirmTwoFactorEnrollment = async (challengeToken: string, code: string) => {
+    const data = await apiClient.confirmTwoFactorEnrollment(challengeToken, code)
     setUser(data.user)
+    return (data.recoveryCodes || []) as string[]
   }
 
   const logout = async () => {
@@ -71,7 +92,9 @@ export function AuthProvider({ children }: { children: ReactNode }) {
   }
 
   return (
-    <AuthContext.Provider value={{ user, loading, login, signup, logout, refreshUser }}>
+    <AuthContext.Provider
+      value={{ user, loading, login, signup, verifyTwoFactor, confirmTwoFactorEnrollment, logout, refreshUser }}
+    >
       {children}
     </AuthContext.Provider>
   )